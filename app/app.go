@@ -1,20 +1,22 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"nidan-kai/binid"
 	"nidan-kai/ent"
 	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/predicate"
 	"nidan-kai/ent/user"
-	"nidan-kai/keystore"
-	"nidan-kai/keystore/envkey"
 	"nidan-kai/nidankai"
 	"nidan-kai/secret"
+	"nidan-kai/token"
 	"strings"
 
 	"os"
 	"strconv"
+	"time"
 
 	"entgo.io/ent/dialect/sql"
 	"github.com/go-playground/validator/v10"
@@ -23,21 +25,44 @@ import (
 	_ "github.com/go-sql-driver/mysql"
 )
 
+// how long a pending enrollment can be confirmed
+const ENROLLMENT_TTL = 10 * time.Minute
+
+var errEnrollmentGone = errors.New("pending enrollment is already confirmed or expired")
+var errCurrentCodeUsed = errors.New("code of the current secret is already used")
+
 type App struct {
 	appName string
 
-	ent       *ent.Client
-	validator *validator.Validate
-	keystore  keystore.Keystore
+	ent        *ent.Client
+	validator  *validator.Validate
+	encryptor  secret.Encryptor
+	signer     *token.Signer
+	params     nidankai.Params
+	hotpParams nidankai.Params
+	verifier   nidankai.Verifier
+
+	// the session cookie is set on verify for forward auth
+	forwardAuth ForwardAuthConfig
 }
 
 type SetUpRequest struct {
 	Email string `form:"email" validate:"required,email,max=256"`
+	Type  string `form:"type" validate:"omitempty,oneof=totp hotp"`
+	// data uri by default
+	Format string `form:"format" validate:"omitempty,oneof=datauri png svg"`
+}
+
+type ConfirmRequest struct {
+	Email string `form:"email" validate:"required,email,max=256"`
+	Code  string `form:"code" validate:"required,number,min=6,max=8"`
+	// of the active secret to be replaced, required if there is one
+	CurrentCode string `form:"current_code" validate:"omitempty,number,min=6,max=8"`
 }
 
 type VerifyRequest struct {
 	Email string `form:"email" validate:"required,email,max=256"`
-	Code  string `form:"code" validate:"required,number,len=6"`
+	Code  string `form:"code" validate:"required,number,min=6,max=8"`
 }
 
 func NewApp() (*App, error) {
@@ -59,11 +84,33 @@ func NewApp() (*App, error) {
 		return nil, err
 	}
 
+	// the signer shares the keystore for secrets unless configured
+	stores := keystores{}
+	encryptor, err := newEncryptor(stores)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := newSigner(stores)
+	if err != nil {
+		return nil, err
+	}
+
+	forwardAuth, err := NewForwardAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	return &App{
-		appName:   "NidanKai",
-		ent:       ent,
-		validator: validator.New(),
-		keystore:  envkey.EnvKey{},
+		appName:     "NidanKai",
+		ent:         ent,
+		validator:   validator.New(),
+		encryptor:   encryptor,
+		signer:      signer,
+		params:      nidankai.DefaultParams(),
+		hotpParams:  nidankai.DefaultHotpParams(),
+		verifier:    nidankai.NewVerifier(nidankai.DefaultWindow(), time.Now),
+		forwardAuth: forwardAuth,
 	}, nil
 }
 
@@ -85,6 +132,167 @@ func (a *App) bind(ctx echo.Context, target any) error {
 	return nil
 }
 
+func (a *App) findUser(c context.Context, email string) (*ent.User, error) {
+	return a.ent.User.Query().
+		Select(
+			user.FieldID,
+			user.FieldLoginMethod,
+		).
+		Where(
+			user.Email(email),
+			user.DeletedAtIsNil(),
+		).
+		Only(c)
+}
+
+// the latest secret of the user in the status, narrowed by the predicates
+func (a *App) findMfaQr(
+	c context.Context,
+	userId binid.BinId,
+	status mfaqr.Status,
+	ps ...predicate.MfaQr,
+) (*ent.MfaQr, error) {
+	return a.ent.MfaQr.Query().
+		Select(
+			mfaqr.FieldID,
+			mfaqr.FieldSecret,
+			mfaqr.FieldSecretBound,
+			mfaqr.FieldDataKeyID,
+			mfaqr.FieldUserID,
+			mfaqr.FieldAlgorithm,
+			mfaqr.FieldDigits,
+			mfaqr.FieldPeriod,
+			mfaqr.FieldType,
+			mfaqr.FieldCounter,
+		).
+		Where(
+			mfaqr.UserID(userId),
+			mfaqr.StatusEQ(status),
+			mfaqr.DeletedAtIsNil(),
+		).
+		Where(ps...).
+		Order(sql.OrderByField(mfaqr.FieldCreatedAt, sql.OrderDesc()).ToFunc()).
+		Limit(1).
+		First(c)
+}
+
+func (a *App) findActiveMfaQr(c context.Context, userId binid.BinId) (*ent.MfaQr, error) {
+	return a.findMfaQr(c, userId, mfaqr.StatusActive)
+}
+
+func (a *App) withTx(c context.Context, fn func(tx *ent.Tx) error) error {
+	tx, err := a.ent.Tx(c)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func paramsOf(mfa *ent.MfaQr) nidankai.Params {
+	return nidankai.Params{
+		Type:      nidankai.OtpType(mfa.Type),
+		Algorithm: nidankai.Algorithm(mfa.Algorithm),
+		Digits:    mfa.Digits,
+		Period:    mfa.Period,
+	}
+}
+
+// rows encrypted with the master key before envelope encryption,
+// bound or not, are sealed with the data key of the user on the way
+func (a *App) decryptSecret(ctx echo.Context, mfa *ent.MfaQr) ([]byte, error) {
+	c := ctx.Request().Context()
+	sec, err := DecryptMfaQr(c, a.ent, a.encryptor, mfa)
+	if err != nil || mfa.DataKeyID != nil {
+		return sec, err
+	}
+
+	dataKeyId, enc, err := a.sealSecret(c, mfa.ID, mfa.UserID, sec)
+	if err != nil {
+		ctx.Logger().Warn(err)
+		return sec, nil
+	}
+
+	err = a.ent.MfaQr.Update().
+		Where(
+			mfaqr.ID(mfa.ID),
+			mfaqr.DataKeyIDIsNil(),
+		).
+		SetSecret(enc).
+		SetSecretBound(true).
+		SetDataKeyID(dataKeyId).
+		Exec(c)
+	if err != nil {
+		// still usable as it is, try again next time
+		ctx.Logger().Warn(err)
+	}
+
+	return sec, nil
+}
+
+// parses the code and checks it against the secret,
+// returns the matched time step, or counter for hotp
+func (a *App) verifyCode(
+	ctx echo.Context,
+	rawCode string,
+	mfa *ent.MfaQr,
+) (uint64, bool, error) {
+	params := paramsOf(mfa)
+	if len(rawCode) != int(params.Digits) {
+		ctx.Logger().Warn("unexpected code length")
+		return 0, false, nil
+	}
+
+	code, err := strconv.Atoi(rawCode)
+	if err != nil {
+		ctx.Logger().Warn(err)
+		return 0, false, nil
+	}
+
+	sec, err := a.decryptSecret(ctx, mfa)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if params.Type == nidankai.TypeHotp {
+		return nidankai.VerifyHotp(
+			code,
+			sec,
+			params,
+			mfa.Counter,
+			nidankai.QR_MFA_HOTP_LOOK_AHEAD,
+		)
+	}
+
+	return a.verifier.Verify(code, sec, params)
+}
+
+// marks the matched step as used on update,
+// the conditions make this atomic between concurrent requests
+func consumeStep(
+	update *ent.MfaQrUpdate,
+	mfa *ent.MfaQr,
+	step uint64,
+) *ent.MfaQrUpdate {
+	if mfa.Type == mfaqr.TypeHotp {
+		return update.
+			Where(mfaqr.CounterEQ(mfa.Counter)).
+			SetCounter(step + 1)
+	}
+
+	// accept each time step only once (RFC 6238 5.2)
+	return update.
+		Where(mfaqr.LastStepLT(step)).
+		SetLastStep(step)
+}
+
 func (a *App) SetUp(ctx echo.Context) error {
 	form := SetUpRequest{}
 
@@ -94,16 +302,7 @@ func (a *App) SetUp(ctx echo.Context) error {
 	}
 
 	c := ctx.Request().Context()
-	u, err := a.ent.User.Query().
-		Select(
-			user.FieldID,
-			user.FieldLoginMethod,
-		).
-		Where(
-			user.Email(form.Email),
-			user.DeletedAtIsNil(),
-		).
-		Only(c)
+	u, err := a.findUser(c, form.Email)
 	if ent.IsNotFound(err) {
 		ctx.Logger().Warn("could not find user")
 		return echo.ErrBadRequest
@@ -112,73 +311,91 @@ func (a *App) SetUp(ctx echo.Context) error {
 		return echo.ErrInternalServerError
 	}
 
-	if u.LoginMethod != user.LoginMethodMfaQr {
-		err := a.ent.User.Update().
-			Where(user.ID(u.ID)).
-			SetLoginMethod(user.LoginMethodMfaQr).
-			Exec(c)
-		if err != nil {
-			ctx.Logger().Error(err)
-			return echo.ErrInternalServerError
-		}
+	params := a.params
+	if form.Type == string(nidankai.TypeHotp) {
+		params = a.hotpParams
 	}
 
-	sec, err := secret.GenerateEncryptedSecret(a.keystore)
+	secId, err := binid.NewSequential()
 	if err != nil {
 		ctx.Logger().Error(err)
 		return echo.ErrInternalServerError
 	}
 
-	secId, err := binid.NewSequential()
+	// only the encrypted one is stored,
+	// and the plain one is encoded in the qr for authenticators
+	sec, err := secret.GenerateSecret()
 	if err != nil {
 		ctx.Logger().Error(err)
 		return echo.ErrInternalServerError
 	}
+	defer clear(sec)
 
-	err = a.ent.MfaQr.Create().
+	dataKeyId, enc, err := a.sealSecret(c, secId, u.ID, sec)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	// login method is not switched until the first code is confirmed,
+	// so abandoning set up doesn't lock the user out
+	create := a.ent.MfaQr.Create().
 		SetID(secId).
-		SetSecret(sec).
+		SetSecret(enc).
+		SetSecretBound(true).
+		SetDataKeyID(dataKeyId).
 		SetUserID(u.ID).
-		Exec(c)
+		SetType(mfaqr.Type(params.Type)).
+		SetAlgorithm(mfaqr.Algorithm(params.Algorithm)).
+		SetDigits(params.Digits).
+		SetStatus(mfaqr.StatusPending).
+		SetExpiresAt(time.Now().Add(ENROLLMENT_TTL))
+	if params.Type == nidankai.TypeTotp {
+		create.SetPeriod(params.Period)
+	}
+	err = create.Exec(c)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return echo.ErrInternalServerError
 	}
 
-	qr, err := nidankai.SetUp(a.appName, form.Email, sec)
+	key := nidankai.Key{
+		Issuer:      a.appName,
+		AccountName: form.Email,
+		Secret:      sec,
+		Params:      params,
+	}
+	renderer := qrRenderer(form.Format)
+	qr, err := nidankai.Render(key, renderer)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return echo.ErrInternalServerError
 	}
 
-	return ctx.String(http.StatusOK, qr)
+	return ctx.Blob(http.StatusOK, renderer.ContentType(), qr)
 }
 
-func (a *App) Verify(ctx echo.Context) error {
-	form := VerifyRequest{}
-
-	if err := a.bind(ctx, &form); err != nil {
-		ctx.Logger().Warn(err)
-		return echo.ErrBadRequest
+func qrRenderer(format string) nidankai.Renderer {
+	switch format {
+	case "png":
+		return nidankai.PngRenderer{Options: nidankai.DefaultQrOptions()}
+	case "svg":
+		return nidankai.SvgRenderer{Options: nidankai.DefaultQrOptions()}
+	default:
+		return nidankai.DataUriRenderer{Options: nidankai.DefaultQrOptions()}
 	}
+}
 
-	code, err := strconv.Atoi(form.Code)
-	if err != nil {
+func (a *App) Confirm(ctx echo.Context) error {
+	form := ConfirmRequest{}
+
+	if err := a.bind(ctx, &form); err != nil {
 		ctx.Logger().Warn(err)
 		return echo.ErrBadRequest
 	}
 
 	c := ctx.Request().Context()
-	u, err := a.ent.User.Query().
-		Select(
-			user.FieldID,
-			user.FieldLoginMethod,
-		).
-		Where(
-			user.Email(form.Email),
-			user.DeletedAtIsNil(),
-		).
-		Only(c)
+	u, err := a.findUser(c, form.Email)
 	if ent.IsNotFound(err) {
 		ctx.Logger().Warn("could not find user")
 		return echo.ErrBadRequest
@@ -187,44 +404,223 @@ func (a *App) Verify(ctx echo.Context) error {
 		return echo.ErrInternalServerError
 	}
 
-	if u.LoginMethod != user.LoginMethodMfaQr {
-		ctx.Logger().Warn("wrong login method")
+	now := time.Now()
+	mfa, err := a.findMfaQr(c, u.ID, mfaqr.StatusPending, mfaqr.ExpiresAtGT(now))
+	if ent.IsNotFound(err) {
+		ctx.Logger().Warn("could not find pending enrollment")
 		return echo.ErrBadRequest
+	} else if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
 	}
 
-	mfa, err := a.ent.MfaQr.Query().
-		Select(
-			mfaqr.FieldSecret,
-		).
-		Where(
-			mfaqr.UserID(u.ID),
-			mfaqr.DeletedAtIsNil(),
-		).
-		Order(sql.OrderByField(mfaqr.FieldCreatedAt, sql.OrderDesc()).ToFunc()).
-		Limit(1).
-		First(c)
-	if err != nil {
+	// the active secret is replaced only by who holds it,
+	// otherwise anyone knowing the email could take over
+	active, err := a.findActiveMfaQr(c, u.ID)
+	if ent.IsNotFound(err) {
+		active = nil
+	} else if err != nil {
 		ctx.Logger().Error(err)
 		return echo.ErrInternalServerError
 	}
 
-	sec, err := secret.Decrypt(mfa.Secret, a.keystore)
+	// failures are counted on the secret to be guessed
+	counted := mfa
+	if active != nil {
+		counted = active
+	}
+	until, err := a.takeAttempt(ctx, u, counted, now)
+	if err != nil {
+		return err
+	}
+
+	step, ok, err := a.verifyCode(ctx, form.Code, mfa)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return echo.ErrInternalServerError
 	}
+	if !ok {
+		ctx.Logger().Warn("invalid code")
+		return failedAttempt(ctx, until, now)
+	}
+
+	var currentStep uint64
+	if active != nil {
+		if len(form.CurrentCode) == 0 {
+			ctx.Logger().Warn("code of the current secret is required")
+			return failedAttempt(ctx, until, now)
+		}
 
-	ok, err := nidankai.Verify(code, sec)
+		currentStep, ok, err = a.verifyCode(ctx, form.CurrentCode, active)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return echo.ErrInternalServerError
+		}
+		if !ok {
+			ctx.Logger().Warn("invalid code of the current secret")
+			return failedAttempt(ctx, until, now)
+		}
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
 	if err != nil {
 		ctx.Logger().Error(err)
 		return echo.ErrInternalServerError
 	}
+
+	err = a.withTx(c, func(tx *ent.Tx) error {
+		update := tx.MfaQr.Update().
+			Where(
+				mfaqr.ID(mfa.ID),
+				mfaqr.StatusEQ(mfaqr.StatusPending),
+				mfaqr.ExpiresAtGT(now),
+			).
+			SetStatus(mfaqr.StatusActive).
+			SetActivatedAt(now).
+			ClearExpiresAt()
+		n, err := consumeStep(update, mfa, step).Save(c)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errEnrollmentGone
+		}
+
+		if active != nil {
+			update := tx.MfaQr.Update().
+				Where(
+					mfaqr.ID(active.ID),
+					mfaqr.DeletedAtIsNil(),
+				)
+			n, err := consumeStep(update, active, currentStep).Save(c)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return errCurrentCodeUsed
+			}
+		}
+
+		// previous secrets are replaced by the confirmed one
+		err = tx.MfaQr.Update().
+			Where(
+				mfaqr.UserID(u.ID),
+				mfaqr.IDNEQ(mfa.ID),
+				mfaqr.DeletedAtIsNil(),
+			).
+			SetDeletedAt(now).
+			Exec(c)
+		if err != nil {
+			return err
+		}
+
+		if u.LoginMethod != user.LoginMethodMfaQr {
+			err := tx.User.Update().
+				Where(user.ID(u.ID)).
+				SetLoginMethod(user.LoginMethodMfaQr).
+				Exec(c)
+			if err != nil {
+				return err
+			}
+		}
+
+		return replaceRecoveryCodes(c, tx, u.ID, hashes, now)
+	})
+	if errors.Is(err, errEnrollmentGone) || errors.Is(err, errCurrentCodeUsed) {
+		ctx.Logger().Warn(err)
+		return failedAttempt(ctx, until, now)
+	} else if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	if err := a.resetAttempts(c, u, counted); err != nil {
+		ctx.Logger().Warn(err)
+	}
+
+	return ctx.JSON(http.StatusOK, RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// checks the code against the active secret and consumes its time step,
+// returns echo errors to be returned from handlers as they are
+func (a *App) authenticate(ctx echo.Context, email, rawCode string) (*ent.User, error) {
+	c := ctx.Request().Context()
+	u, err := a.findUser(c, email)
+	if ent.IsNotFound(err) {
+		ctx.Logger().Warn("could not find user")
+		return nil, echo.ErrBadRequest
+	} else if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.ErrInternalServerError
+	}
+
+	if u.LoginMethod != user.LoginMethodMfaQr {
+		ctx.Logger().Warn("wrong login method")
+		return nil, echo.ErrBadRequest
+	}
+
+	mfa, err := a.findActiveMfaQr(c, u.ID)
+	if ent.IsNotFound(err) {
+		ctx.Logger().Warn("could not find active secret")
+		return nil, echo.ErrBadRequest
+	} else if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.ErrInternalServerError
+	}
+
+	// counted before checking, and not even checked while locked out
+	now := time.Now()
+	until, err := a.takeAttempt(ctx, u, mfa, now)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok, err := a.verifyCode(ctx, rawCode, mfa)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.ErrInternalServerError
+	}
 	if !ok {
 		ctx.Logger().Warn("invalid code")
+		return nil, failedAttempt(ctx, until, now)
+	}
+
+	update := a.ent.MfaQr.Update().
+		Where(mfaqr.ID(mfa.ID))
+	n, err := consumeStep(update, mfa, step).Save(c)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.ErrInternalServerError
+	}
+	if n == 0 {
+		ctx.Logger().Warn("code is already used")
+		return nil, failedAttempt(ctx, until, now)
+	}
+
+	if err := a.resetAttempts(c, u, mfa); err != nil {
+		// the code is accepted anyway, attempts are forgotten next time
+		ctx.Logger().Warn(err)
+	}
+
+	return u, nil
+}
+
+func (a *App) Verify(ctx echo.Context) error {
+	form := VerifyRequest{}
+
+	if err := a.bind(ctx, &form); err != nil {
+		ctx.Logger().Warn(err)
 		return echo.ErrBadRequest
 	}
 
-	return ctx.NoContent(http.StatusOK)
+	u, err := a.authenticate(ctx, form.Email, form.Code)
+	if err != nil {
+		return err
+	}
+
+	return a.issueSession(ctx, u)
 }
 
 func (a *App) Close() error {
@@ -0,0 +1,1223 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/enttest"
+	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/recoverycode"
+	"nidan-kai/ent/user"
+	"nidan-kai/keystore"
+	"nidan-kai/keystore/envkey"
+	"nidan-kai/nidankai"
+	"nidan-kai/ratelimit"
+	"nidan-kai/recovery"
+	"nidan-kai/secret"
+	"nidan-kai/token"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var testKEY = "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT="
+var envKey = "ENV_SECRET_KEY"
+var testEmail = "test@example.com"
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestApp(t *testing.T, name string) (*App, *testClock) {
+	t.Setenv(envKey, testKEY)
+	// a single connection, so that concurrent requests share the memory db
+	db, err := sql.Open("sqlite3", "file:"+name+"?mode=memory&_fk=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	drv := entsql.OpenDB(dialect.SQLite, db)
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(drv)))
+	t.Cleanup(func() { client.Close() })
+
+	id, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.User.Create().
+		SetID(id).
+		SetName("test").
+		SetEmail(testEmail).
+		ExecX(context.Background())
+
+	clock := &testClock{now: time.Unix(1111111109, 0)}
+	return &App{
+		appName:    "NidanKai",
+		ent:        client,
+		validator:  validator.New(),
+		encryptor:  secret.NewSealer(envkey.EnvKey{}, secret.DEFAULT_SEALER_TTL),
+		signer:     token.NewSigner(envkey.EnvKey{}, token.DEFAULT_SIGNER_TTL),
+		params:     nidankai.DefaultParams(),
+		hotpParams: nidankai.DefaultHotpParams(),
+		verifier:   nidankai.NewVerifier(nidankai.Window{}, clock.Now),
+		forwardAuth: ForwardAuthConfig{
+			LoginUrl:     "https://auth.example.com/login",
+			AllowedHosts: []string{"tool.example.com", ".internal.example.com"},
+		},
+	}, clock
+}
+
+// calls the handler with the form, returns the recorded response
+func post(t *testing.T, handler echo.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+
+	err := handler(e.NewContext(req, rec))
+	if err != nil {
+		e.HTTPErrorHandler(err, e.NewContext(req, rec))
+	}
+	return rec
+}
+
+// reads the qr as authenticator apps do, turning it around
+// as the reader misses finder patterns of some codes at an angle
+func scanQr(t *testing.T, b []byte) nidankai.Key {
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := gozxing.NewLuminanceSourceFromImage(img)
+	for range 4 {
+		bmp, err := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizer(src))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := qrcode.NewQRCodeReader().Decode(bmp, nil)
+		if err == nil {
+			key, err := nidankai.ParseURI(res.GetText())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return key
+		}
+
+		src, err = src.RotateCounterClockwise()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Fatal("could not read qr")
+	return nidankai.Key{}
+}
+
+func codeOf(t *testing.T, key nidankai.Key, now time.Time) string {
+	code, err := nidankai.Totp(key.Secret, now.Unix(), key.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%0*d", key.Params.Digits, code)
+}
+
+func TestApp_EndToEnd(t *testing.T) {
+	a, clock := newTestApp(t, "e2e")
+
+	rec := post(t, a.SetUp, url.Values{"email": {testEmail}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set up failed with %d\n", rec.Code)
+	}
+	dataUri := rec.Body.String()
+	raw, found := strings.CutPrefix(dataUri, "data:image/png;base64,")
+	if !found {
+		t.Fatal("should return data uri by default")
+	}
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := scanQr(t, b)
+	if key.Issuer != a.appName || key.AccountName != testEmail {
+		t.Fatal("unexpected account in qr")
+	}
+	if len(key.Secret) != secret.SECRET_LEN {
+		t.Fatal("qr should contain the plain seed")
+	}
+
+	t.Run("should not store the plain seed", func(t *testing.T) {
+		mfa := a.ent.MfaQr.Query().OnlyX(context.Background())
+		if bytes.Contains(mfa.Secret, key.Secret) {
+			t.Fatal("seed is stored in plain")
+		}
+	})
+
+	rec = post(t, a.Confirm, url.Values{
+		"email": {testEmail},
+		"code":  {codeOf(t, key, clock.now)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm failed with %d\n", rec.Code)
+	}
+	res := RecoveryCodesResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.RecoveryCodes) == 0 {
+		t.Fatal("should return recovery codes")
+	}
+
+	// the next time step
+	clock.now = clock.now.Add(time.Duration(key.Params.Period) * time.Second)
+	code := codeOf(t, key, clock.now)
+	rec = post(t, a.Verify, url.Values{
+		"email": {testEmail},
+		"code":  {code},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("verify failed with %d\n", rec.Code)
+	}
+
+	t.Run("should issue token", func(t *testing.T) {
+		res := TokenResponse{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		claims, err := a.signer.Verify(res.Token, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		u := a.ent.User.Query().OnlyX(context.Background())
+		if claims.Subject != u.ID.String() || claims.Issuer != a.appName {
+			t.Fatal("unexpected subject")
+		}
+		if len(claims.Amr) != 1 || claims.Amr[0] != token.AMR_OTP {
+			t.Fatal("unexpected amr")
+		}
+	})
+
+	t.Run("should reject replayed code", func(t *testing.T) {
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {code},
+		})
+		if rec.Code == http.StatusOK {
+			t.Fatal("replayed code is accepted")
+		}
+	})
+
+	t.Run("should reject wrong code", func(t *testing.T) {
+		clock.now = clock.now.Add(time.Duration(key.Params.Period) * time.Second)
+		wrong := []byte(codeOf(t, key, clock.now))
+		wrong[0] = '0' + (wrong[0]-'0'+1)%10
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {string(wrong)},
+		})
+		if rec.Code == http.StatusOK {
+			t.Fatal("wrong code is accepted")
+		}
+	})
+}
+
+// sets up and confirms, returns the key scanned from the qr
+// and the recovery codes
+func enroll(t *testing.T, a *App, clock *testClock) (nidankai.Key, []string) {
+	rec := post(t, a.SetUp, url.Values{
+		"email":  {testEmail},
+		"format": {"png"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set up failed with %d\n", rec.Code)
+	}
+	key := scanQr(t, rec.Body.Bytes())
+
+	rec = post(t, a.Confirm, url.Values{
+		"email": {testEmail},
+		"code":  {codeOf(t, key, clock.now)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm failed with %d\n", rec.Code)
+	}
+	res := RecoveryCodesResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.now = clock.now.Add(time.Duration(key.Params.Period) * time.Second)
+	return key, res.RecoveryCodes
+}
+
+func TestLockoutDuration(t *testing.T) {
+	if lockoutDuration(MAX_FAILED_ATTEMPTS-1) != 0 {
+		t.Fatal("should not lock out under the limit")
+	}
+	if lockoutDuration(MAX_FAILED_ATTEMPTS) != LOCKOUT_DURATION {
+		t.Fatal("wrong first lockout")
+	}
+	if lockoutDuration(MAX_FAILED_ATTEMPTS+2) != 4*LOCKOUT_DURATION {
+		t.Fatal("should double for each failure")
+	}
+	if lockoutDuration(MAX_FAILED_ATTEMPTS+100) != MAX_LOCKOUT_DURATION {
+		t.Fatal("should not exceed the max")
+	}
+}
+
+func TestApp_Lockout(t *testing.T) {
+	a, clock := newTestApp(t, "lockout")
+	key, _ := enroll(t, a, clock)
+	c := context.Background()
+
+	wrong := []byte(codeOf(t, key, clock.now))
+	wrong[0] = '0' + (wrong[0]-'0'+1)%10
+	for i := 1; i < MAX_FAILED_ATTEMPTS; i++ {
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {string(wrong)},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d at %d\n", rec.Code, i)
+		}
+	}
+
+	rec := post(t, a.Verify, url.Values{
+		"email": {testEmail},
+		"code":  {string(wrong)},
+	})
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("should lock out at the limit, got %d\n", rec.Code)
+	}
+	if rec.Header().Get(echo.HeaderRetryAfter) != "60" {
+		t.Fatal("unexpected retry after")
+	}
+
+	t.Run("should reject even the correct code while locked out", func(t *testing.T) {
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now)},
+		})
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+		if len(rec.Header().Get(echo.HeaderRetryAfter)) == 0 {
+			t.Fatal("should tell when to retry")
+		}
+	})
+
+	t.Run("should reset on success after the lockout", func(t *testing.T) {
+		past := time.Now().Add(-time.Second)
+		a.ent.User.Update().SetLockedUntil(past).ExecX(c)
+		a.ent.MfaQr.Update().SetLockedUntil(past).ExecX(c)
+
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now)},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("verify failed with %d\n", rec.Code)
+		}
+
+		u := a.ent.User.Query().OnlyX(c)
+		mfa := a.ent.MfaQr.Query().OnlyX(c)
+		if u.FailedAttempts != 0 || u.LockedUntil != nil || mfa.FailedAttempts != 0 || mfa.LockedUntil != nil {
+			t.Fatal("failures should be reset")
+		}
+	})
+
+	t.Run("should forget failures out of the window", func(t *testing.T) {
+		old := time.Now().Add(-FAILURE_WINDOW - time.Second)
+		a.ent.User.Update().
+			SetFailedAttempts(MAX_FAILED_ATTEMPTS - 1).
+			SetLastFailedAt(old).
+			ExecX(c)
+
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {string(wrong)},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+		if a.ent.User.Query().OnlyX(c).FailedAttempts != 1 {
+			t.Fatal("should start over")
+		}
+	})
+}
+
+func TestApp_Jwks(t *testing.T) {
+	a, _ := newTestApp(t, "jwks")
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	if err := a.Jwks(e.NewContext(req, rec)); err != nil {
+		t.Fatal(err)
+	}
+
+	set := token.JwkSet{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 1 || len(set.Keys[0].KeyId) == 0 {
+		t.Fatal("should publish the signing key")
+	}
+}
+
+func TestForwardAuthConfig(t *testing.T) {
+	t.Setenv("FORWARD_AUTH_LOGIN_URL", "https://auth.example.com/login")
+	t.Setenv("FORWARD_AUTH_ALLOWED_HOSTS", "Tool.example.com, .internal.example.com")
+	config, err := NewForwardAuthConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := []string{
+		"https://tool.example.com/path?q=1",
+		"http://tool.example.com:8080/",
+		"https://a.internal.example.com/",
+	}
+	for _, raw := range allowed {
+		if _, err := config.checkRedirect(raw); err != nil {
+			t.Fatalf("%s should be allowed\n", raw)
+		}
+	}
+
+	denied := []string{
+		"",
+		"/relative",
+		"//evil.com/",
+		"javascript:alert(1)",
+		"https://evil.com/",
+		"https://tool.example.com.evil.com/",
+		"https://eviltool.example.com/",
+		"https://internal.example.com/",
+		"https://tool.example.com@evil.com/",
+		"https://evil.com\\@tool.example.com/",
+	}
+	for _, raw := range denied {
+		if _, err := config.checkRedirect(raw); err == nil {
+			t.Fatalf("%s should be denied\n", raw)
+		}
+	}
+
+	t.Setenv("FORWARD_AUTH_ALLOWED_HOSTS", "*.example.com")
+	if _, err := NewForwardAuthConfig(); err == nil {
+		t.Fatal("should fail on wildcard")
+	}
+}
+
+func TestApp_ForwardAuth(t *testing.T) {
+	a, clock := newTestApp(t, "forwardauth")
+	key, _ := enroll(t, a, clock)
+
+	forward := func(cookies []*http.Cookie, host string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/auth/forward", nil)
+		req.Header.Set(echo.HeaderXForwardedProto, "https")
+		req.Header.Set(HEADER_FORWARDED_HOST, host)
+		req.Header.Set(HEADER_FORWARDED_URI, "/page?q=1")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		if err := a.ForwardAuth(e.NewContext(req, rec)); err != nil {
+			t.Fatal(err)
+		}
+		return rec
+	}
+
+	t.Run("should send to login without session", func(t *testing.T) {
+		rec := forward(nil, "tool.example.com")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+		expected := "https://auth.example.com/login?rd=" + url.QueryEscape("https://tool.example.com/page?q=1")
+		if rec.Header().Get(echo.HeaderLocation) != expected {
+			t.Fatal("unexpected location")
+		}
+	})
+
+	t.Run("should redirect browsers to login", func(t *testing.T) {
+		e := echo.New()
+		for _, tc := range []struct {
+			name     string
+			headers  map[string]string
+			expected int
+		}{
+			{"browser", map[string]string{
+				echo.HeaderAccept: "text/html,application/xhtml+xml,*/*;q=0.8",
+			}, http.StatusFound},
+			{"api client", map[string]string{
+				echo.HeaderAccept: "application/json",
+			}, http.StatusUnauthorized},
+			{"browser through nginx", map[string]string{
+				echo.HeaderAccept:   "text/html",
+				HEADER_ORIGINAL_URL: "https://tool.example.com/page?q=1",
+			}, http.StatusUnauthorized},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/api/auth/forward", nil)
+				req.Header.Set(echo.HeaderXForwardedProto, "https")
+				req.Header.Set(HEADER_FORWARDED_HOST, "tool.example.com")
+				req.Header.Set(HEADER_FORWARDED_URI, "/page?q=1")
+				for k, v := range tc.headers {
+					req.Header.Set(k, v)
+				}
+				rec := httptest.NewRecorder()
+				if err := a.ForwardAuth(e.NewContext(req, rec)); err != nil {
+					t.Fatal(err)
+				}
+
+				if rec.Code != tc.expected {
+					t.Fatalf("unexpected status %d\n", rec.Code)
+				}
+				expected := "https://auth.example.com/login?rd=" + url.QueryEscape("https://tool.example.com/page?q=1")
+				if rec.Header().Get(echo.HeaderLocation) != expected {
+					t.Fatal("unexpected location")
+				}
+			})
+		}
+	})
+
+	t.Run("should not redirect to unknown hosts", func(t *testing.T) {
+		rec := forward(nil, "evil.com")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+		if rec.Header().Get(echo.HeaderLocation) != "https://auth.example.com/login" {
+			t.Fatal("should not include the redirect target")
+		}
+	})
+
+	verified := post(t, a.Verify, url.Values{
+		"email": {testEmail},
+		"code":  {codeOf(t, key, clock.now)},
+	})
+	if verified.Code != http.StatusOK {
+		t.Fatalf("verify failed with %d\n", verified.Code)
+	}
+	cookies := verified.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != SESSION_COOKIE || !cookies[0].HttpOnly || !cookies[0].Secure {
+		t.Fatal("should set the session cookie")
+	}
+
+	rec := forward(cookies, "tool.example.com")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d\n", rec.Code)
+	}
+	u := a.ent.User.Query().OnlyX(context.Background())
+	if rec.Header().Get(HEADER_AUTH_USER) != u.ID.String() || rec.Header().Get(HEADER_AUTH_EMAIL) != testEmail {
+		t.Fatal("unexpected identity headers")
+	}
+
+	t.Run("should not accept tokens other than session", func(t *testing.T) {
+		res := TokenResponse{}
+		if err := json.Unmarshal(verified.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		claims, err := a.signer.Verify(res.Token, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claims.Audience != TOKEN_AUDIENCE {
+			t.Fatal("bearer token should have its own audience")
+		}
+
+		rec := forward([]*http.Cookie{{Name: SESSION_COOKIE, Value: res.Token}}, "tool.example.com")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+	})
+
+	t.Run("should reject tampered session", func(t *testing.T) {
+		tampered := *cookies[0]
+		tampered.Value += "x"
+		rec := forward([]*http.Cookie{&tampered}, "tool.example.com")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+	})
+
+	t.Run("should log out when the secret is reset", func(t *testing.T) {
+		c := context.Background()
+		a.ent.MfaQr.Update().SetActivatedAt(time.Now().Add(2 * time.Second)).ExecX(c)
+		defer a.ent.MfaQr.Update().ClearActivatedAt().ExecX(c)
+
+		rec := forward(cookies, "tool.example.com")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+	})
+
+	t.Run("should log out when mfa is left", func(t *testing.T) {
+		c := context.Background()
+		a.ent.User.Update().SetLoginMethod(user.LoginMethodPassword).ExecX(c)
+		defer a.ent.User.Update().SetLoginMethod(user.LoginMethodMfaQr).ExecX(c)
+
+		rec := forward(cookies, "tool.example.com")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+	})
+
+	t.Run("should keep the session otherwise", func(t *testing.T) {
+		rec := forward(cookies, "tool.example.com")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+	})
+}
+
+func TestApp_LockoutBurst(t *testing.T) {
+	a, clock := newTestApp(t, "lockoutburst")
+	key, _ := enroll(t, a, clock)
+
+	wrong := []byte(codeOf(t, key, clock.now))
+	wrong[0] = '0' + (wrong[0]-'0'+1)%10
+
+	codes := make(chan int, 4*MAX_FAILED_ATTEMPTS)
+	wg := sync.WaitGroup{}
+	for range cap(codes) {
+		wg.Go(func() {
+			rec := post(t, a.Verify, url.Values{
+				"email": {testEmail},
+				"code":  {string(wrong)},
+			})
+			codes <- rec.Code
+		})
+	}
+	wg.Wait()
+	close(codes)
+
+	counts := map[int]int{}
+	for code := range codes {
+		counts[code]++
+	}
+	// the attempt hitting the limit of the user and the one of the secret
+	// may differ, both are told to retry later
+	if counts[http.StatusBadRequest] > MAX_FAILED_ATTEMPTS-1 {
+		t.Fatalf("%d attempts are let through %v\n", counts[http.StatusBadRequest], counts)
+	}
+	if counts[http.StatusBadRequest]+counts[http.StatusTooManyRequests] != cap(codes) {
+		t.Fatalf("unexpected statuses %v\n", counts)
+	}
+
+	// only the attempts under the limit are checked
+	c := context.Background()
+	u := a.ent.User.Query().OnlyX(c)
+	mfa := a.ent.MfaQr.Query().OnlyX(c)
+	if u.FailedAttempts != MAX_FAILED_ATTEMPTS || mfa.FailedAttempts != MAX_FAILED_ATTEMPTS {
+		t.Fatalf("%d and %d attempts are taken\n", u.FailedAttempts, mfa.FailedAttempts)
+	}
+}
+
+func TestApp_ConfirmLockout(t *testing.T) {
+	a, clock := newTestApp(t, "confirmlockout")
+	c := context.Background()
+
+	rec := post(t, a.SetUp, url.Values{
+		"email":  {testEmail},
+		"format": {"png"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set up failed with %d\n", rec.Code)
+	}
+	key := scanQr(t, rec.Body.Bytes())
+
+	wrong := []byte(codeOf(t, key, clock.now))
+	wrong[0] = '0' + (wrong[0]-'0'+1)%10
+	for range MAX_FAILED_ATTEMPTS {
+		post(t, a.Confirm, url.Values{
+			"email": {testEmail},
+			"code":  {string(wrong)},
+		})
+	}
+
+	rec = post(t, a.Confirm, url.Values{
+		"email": {testEmail},
+		"code":  {codeOf(t, key, clock.now)},
+	})
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("should be locked out, got %d\n", rec.Code)
+	}
+
+	a.ent.User.Update().ClearLockedUntil().ExecX(c)
+	a.ent.MfaQr.Update().ClearLockedUntil().ExecX(c)
+	rec = post(t, a.Confirm, url.Values{
+		"email": {testEmail},
+		"code":  {codeOf(t, key, clock.now)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm failed with %d\n", rec.Code)
+	}
+	if a.ent.User.Query().OnlyX(c).FailedAttempts != 0 {
+		t.Fatal("attempts should be reset")
+	}
+}
+
+// counts Init, like passphrase reading a one-shot fd
+type initCountingKeystore struct {
+	envkey.EnvKey
+}
+
+var initCount = 0
+
+func (initCountingKeystore) Init() error {
+	initCount++
+	return nil
+}
+
+func init() {
+	keystore.Register("test-counting", func() keystore.Keystore {
+		return initCountingKeystore{}
+	})
+}
+
+func TestKeystores_OpenOnce(t *testing.T) {
+	t.Setenv(envKey, testKEY)
+	t.Setenv("SECRET_BACKEND", "")
+	t.Setenv("KEYSTORE", "test-counting")
+	t.Setenv("SIGNING_KEYSTORE", "")
+	initCount = 0
+
+	stores := keystores{}
+	if _, err := newEncryptor(stores); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newSigner(stores); err != nil {
+		t.Fatal(err)
+	}
+	if initCount != 1 {
+		t.Fatalf("keystore should be initialized once but %d times\n", initCount)
+	}
+
+	t.Run("should open the signing keystore if configured", func(t *testing.T) {
+		t.Setenv("SIGNING_KEYSTORE", "env")
+		if _, err := newSigner(stores); err != nil {
+			t.Fatal(err)
+		}
+		if len(stores) != 2 {
+			t.Fatal("signing keystore should be opened separately")
+		}
+	})
+}
+
+func TestApp_Recover(t *testing.T) {
+	a, clock := newTestApp(t, "recover")
+	_, codes := enroll(t, a, clock)
+
+	rec := post(t, a.Recover, url.Values{
+		"email":         {testEmail},
+		"recovery_code": {codes[0]},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("recover failed with %d\n", rec.Code)
+	}
+
+	t.Run("should issue token and session as verify", func(t *testing.T) {
+		res := TokenResponse{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := a.signer.Verify(res.Token, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != SESSION_COOKIE {
+			t.Fatal("should set session cookie")
+		}
+	})
+
+	t.Run("should reject used code", func(t *testing.T) {
+		rec := post(t, a.Recover, url.Values{
+			"email":         {testEmail},
+			"recovery_code": {codes[0]},
+		})
+		if rec.Code == http.StatusOK {
+			t.Fatal("used code is accepted")
+		}
+	})
+
+	t.Run("should lock out on wrong codes", func(t *testing.T) {
+		wrong, err := recovery.GenerateSet()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the used code above is counted as well
+		for i := 2; i < MAX_FAILED_ATTEMPTS; i++ {
+			rec := post(t, a.Recover, url.Values{
+				"email":         {testEmail},
+				"recovery_code": {wrong[0]},
+			})
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("unexpected status %d at %d\n", rec.Code, i)
+			}
+		}
+
+		rec := post(t, a.Recover, url.Values{
+			"email":         {testEmail},
+			"recovery_code": {wrong[0]},
+		})
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("should lock out at the limit, got %d\n", rec.Code)
+		}
+
+		rec = post(t, a.Recover, url.Values{
+			"email":         {testEmail},
+			"recovery_code": {codes[1]},
+		})
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("should lock out even the correct code, got %d\n", rec.Code)
+		}
+		if len(rec.Header().Get(echo.HeaderRetryAfter)) == 0 {
+			t.Fatal("should tell when to retry")
+		}
+
+		unused := a.ent.RecoveryCode.Query().
+			Where(recoverycode.UsedAtIsNil()).
+			CountX(context.Background())
+		if unused != len(codes)-1 {
+			t.Fatal("code should not be burned while locked out")
+		}
+	})
+}
+
+func TestApp_RegenerateRecoveryCodes(t *testing.T) {
+	a, clock := newTestApp(t, "regenerate")
+	key, codes := enroll(t, a, clock)
+
+	t.Run("should require the current code", func(t *testing.T) {
+		wrong := []byte(codeOf(t, key, clock.now))
+		wrong[0] = '0' + (wrong[0]-'0'+1)%10
+		rec := post(t, a.RegenerateRecoveryCodes, url.Values{
+			"email": {testEmail},
+			"code":  {string(wrong)},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+	})
+
+	rec := post(t, a.RegenerateRecoveryCodes, url.Values{
+		"email": {testEmail},
+		"code":  {codeOf(t, key, clock.now)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("regenerate failed with %d\n", rec.Code)
+	}
+	res := RecoveryCodesResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.RecoveryCodes) != len(codes) {
+		t.Fatal("should return a new set")
+	}
+
+	t.Run("should invalidate the old set", func(t *testing.T) {
+		rec := post(t, a.Recover, url.Values{
+			"email":         {testEmail},
+			"recovery_code": {codes[0]},
+		})
+		if rec.Code == http.StatusOK {
+			t.Fatal("old code is accepted")
+		}
+	})
+
+	t.Run("should accept the new set", func(t *testing.T) {
+		rec := post(t, a.Recover, url.Values{
+			"email":         {testEmail},
+			"recovery_code": {res.RecoveryCodes[0]},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("recover failed with %d\n", rec.Code)
+		}
+	})
+}
+
+func TestApp_Reenroll(t *testing.T) {
+	a, clock := newTestApp(t, "reenroll")
+	current, _ := enroll(t, a, clock)
+	c := context.Background()
+
+	rec := post(t, a.SetUp, url.Values{
+		"email":  {testEmail},
+		"format": {"png"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set up failed with %d\n", rec.Code)
+	}
+	key := scanQr(t, rec.Body.Bytes())
+
+	t.Run("should require the code of the current secret", func(t *testing.T) {
+		rec := post(t, a.Confirm, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now)},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+
+		wrong := []byte(codeOf(t, current, clock.now))
+		wrong[0] = '0' + (wrong[0]-'0'+1)%10
+		rec = post(t, a.Confirm, url.Values{
+			"email":        {testEmail},
+			"code":         {codeOf(t, key, clock.now)},
+			"current_code": {string(wrong)},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+
+		active := a.ent.MfaQr.Query().
+			Where(mfaqr.StatusEQ(mfaqr.StatusActive), mfaqr.DeletedAtIsNil()).
+			CountX(c)
+		if active != 1 {
+			t.Fatal("current secret should be kept")
+		}
+	})
+
+	rec = post(t, a.Confirm, url.Values{
+		"email":        {testEmail},
+		"code":         {codeOf(t, key, clock.now)},
+		"current_code": {codeOf(t, current, clock.now)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm failed with %d\n", rec.Code)
+	}
+
+	t.Run("should replace the current secret", func(t *testing.T) {
+		clock.now = clock.now.Add(time.Duration(key.Params.Period) * time.Second)
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, current, clock.now)},
+		})
+		if rec.Code == http.StatusOK {
+			t.Fatal("replaced secret is accepted")
+		}
+
+		rec = post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now)},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("verify failed with %d\n", rec.Code)
+		}
+	})
+}
+
+func TestApp_AbandonedSetUp(t *testing.T) {
+	a, clock := newTestApp(t, "abandoned")
+	c := context.Background()
+
+	rec := post(t, a.SetUp, url.Values{
+		"email":  {testEmail},
+		"format": {"png"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set up failed with %d\n", rec.Code)
+	}
+	key := scanQr(t, rec.Body.Bytes())
+
+	t.Run("should not change login method until confirmed", func(t *testing.T) {
+		u := a.ent.User.Query().OnlyX(c)
+		if u.LoginMethod != user.LoginMethodPassword {
+			t.Fatal("login method should be unchanged")
+		}
+
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now)},
+		})
+		if rec.Code == http.StatusOK {
+			t.Fatal("pending secret is accepted")
+		}
+	})
+
+	t.Run("should reject expired enrollment", func(t *testing.T) {
+		a.ent.MfaQr.Update().
+			SetExpiresAt(time.Now().Add(-time.Second)).
+			ExecX(c)
+
+		rec := post(t, a.Confirm, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now)},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+
+		u := a.ent.User.Query().OnlyX(c)
+		if u.LoginMethod != user.LoginMethodPassword {
+			t.Fatal("login method should be unchanged")
+		}
+		if a.ent.MfaQr.Query().OnlyX(c).Status != mfaqr.StatusPending {
+			t.Fatal("expired enrollment should not be activated")
+		}
+	})
+}
+
+func TestApp_Replay(t *testing.T) {
+	a, clock := newTestApp(t, "replay")
+	key, _ := enroll(t, a, clock)
+	c := context.Background()
+	period := time.Duration(key.Params.Period) * time.Second
+
+	t.Run("should accept concurrent replays once", func(t *testing.T) {
+		code := codeOf(t, key, clock.now)
+		wg := sync.WaitGroup{}
+		mu := sync.Mutex{}
+		accepted := 0
+		for range 10 {
+			wg.Go(func() {
+				rec := post(t, a.Verify, url.Values{
+					"email": {testEmail},
+					"code":  {code},
+				})
+				if rec.Code == http.StatusOK {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			})
+		}
+		wg.Wait()
+
+		if accepted != 1 {
+			t.Fatalf("should be accepted once but %d times\n", accepted)
+		}
+	})
+
+	a.ent.User.Update().SetFailedAttempts(0).ClearLockedUntil().ExecX(c)
+	a.ent.MfaQr.Update().SetFailedAttempts(0).ClearLockedUntil().ExecX(c)
+
+	t.Run("should reject steps before the accepted one", func(t *testing.T) {
+		a.verifier = nidankai.NewVerifier(nidankai.DefaultWindow(), clock.Now)
+		clock.now = clock.now.Add(period)
+
+		rec := post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now.Add(period))},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("code ahead should be accepted, got %d\n", rec.Code)
+		}
+
+		rec = post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {codeOf(t, key, clock.now)},
+		})
+		if rec.Code == http.StatusOK {
+			t.Fatal("code of an earlier step is accepted")
+		}
+	})
+}
+
+func hotpCodeOf(t *testing.T, key nidankai.Key, counter uint64) string {
+	nonce := [8]byte{}
+	binary.BigEndian.PutUint64(nonce[:], counter)
+	code, err := nidankai.Hotp(key.Secret, nonce, key.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%0*d", key.Params.Digits, code)
+}
+
+func TestApp_Hotp(t *testing.T) {
+	a, _ := newTestApp(t, "hotp")
+	c := context.Background()
+
+	rec := post(t, a.SetUp, url.Values{
+		"email":  {testEmail},
+		"type":   {"hotp"},
+		"format": {"png"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("set up failed with %d\n", rec.Code)
+	}
+	key := scanQr(t, rec.Body.Bytes())
+	if key.Params.Type != nidankai.TypeHotp || key.Counter != 0 {
+		t.Fatal("qr should be for hotp from counter 0")
+	}
+
+	rec = post(t, a.Confirm, url.Values{
+		"email": {testEmail},
+		"code":  {hotpCodeOf(t, key, 0)},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirm failed with %d\n", rec.Code)
+	}
+
+	verify := func(counter uint64) int {
+		return post(t, a.Verify, url.Values{
+			"email": {testEmail},
+			"code":  {hotpCodeOf(t, key, counter)},
+		}).Code
+	}
+
+	t.Run("should move the counter", func(t *testing.T) {
+		if code := verify(1); code != http.StatusOK {
+			t.Fatalf("verify failed with %d\n", code)
+		}
+		if verify(1) == http.StatusOK {
+			t.Fatal("replayed code is accepted")
+		}
+		if code := verify(2 + nidankai.QR_MFA_HOTP_LOOK_AHEAD - 1); code != http.StatusOK {
+			t.Fatalf("code within the look-ahead should be accepted, got %d\n", code)
+		}
+		if a.ent.MfaQr.Query().OnlyX(c).Counter != 2+nidankai.QR_MFA_HOTP_LOOK_AHEAD {
+			t.Fatal("counter should be next to the accepted one")
+		}
+	})
+
+	a.ent.User.Update().SetFailedAttempts(0).ExecX(c)
+	a.ent.MfaQr.Update().SetFailedAttempts(0).ExecX(c)
+	// pressed many times away from the server
+	drifted := a.ent.MfaQr.Query().OnlyX(c).Counter + 100
+
+	t.Run("should count failed resync", func(t *testing.T) {
+		rec := post(t, a.Resync, url.Values{
+			"email":     {testEmail},
+			"code":      {hotpCodeOf(t, key, drifted)},
+			"next_code": {hotpCodeOf(t, key, drifted+2)},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status %d\n", rec.Code)
+		}
+		if a.ent.User.Query().OnlyX(c).FailedAttempts != 1 {
+			t.Fatal("failed resync should be counted")
+		}
+	})
+
+	t.Run("should resync with consecutive codes", func(t *testing.T) {
+		if verify(drifted+2) == http.StatusOK {
+			t.Fatal("code beyond the look-ahead is accepted")
+		}
+
+		rec := post(t, a.Resync, url.Values{
+			"email":     {testEmail},
+			"code":      {hotpCodeOf(t, key, drifted+3)},
+			"next_code": {hotpCodeOf(t, key, drifted+4)},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("resync failed with %d\n", rec.Code)
+		}
+		if a.ent.User.Query().OnlyX(c).FailedAttempts != 0 {
+			t.Fatal("attempts should be reset")
+		}
+
+		if verify(drifted+4) == http.StatusOK {
+			t.Fatal("code used for resync is accepted")
+		}
+		if code := verify(drifted + 5); code != http.StatusOK {
+			t.Fatalf("verify failed with %d\n", code)
+		}
+	})
+}
+
+func TestApp_Route(t *testing.T) {
+	a, _ := newTestApp(t, "route")
+	e := echo.New()
+	a.Route(e, ratelimit.NewMemoryStore())
+
+	send := func(path string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(""))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for _, path := range []string{
+		"/api/mfa/qr/confirm",
+		"/api/mfa/qr/resync",
+		"/api/mfa/qr/recovery/regenerate",
+	} {
+		t.Run("should limit "+path, func(t *testing.T) {
+			for i := range 10 {
+				if code := send(path); code == http.StatusTooManyRequests {
+					t.Fatalf("limited too early at %d\n", i)
+				}
+			}
+			if code := send(path); code != http.StatusTooManyRequests {
+				t.Fatalf("should be limited once the bucket is empty, got %d\n", code)
+			}
+		})
+	}
+}
+
+func TestApp_LegacySecret(t *testing.T) {
+	a, clock := newTestApp(t, "legacysecret")
+	key, _ := enroll(t, a, clock)
+	c := context.Background()
+	period := time.Duration(key.Params.Period) * time.Second
+
+	for _, bound := range []bool{false, true} {
+		t.Run(fmt.Sprintf("should seal with the data key bound %v", bound), func(t *testing.T) {
+			// as encrypted with the master key before envelope encryption
+			mfa := a.ent.MfaQr.Query().OnlyX(c)
+			var ad []byte
+			if bound {
+				ad = secret.MfaQrContext(mfa.ID, mfa.UserID)
+			}
+			enc, err := a.encryptor.Encrypt(key.Secret, ad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a.ent.MfaQr.UpdateOne(mfa).
+				SetSecret(enc).
+				SetSecretBound(bound).
+				ClearDataKeyID().
+				ExecX(c)
+
+			for range 2 {
+				clock.now = clock.now.Add(period)
+				rec := post(t, a.Verify, url.Values{
+					"email": {testEmail},
+					"code":  {codeOf(t, key, clock.now)},
+				})
+				if rec.Code != http.StatusOK {
+					t.Fatalf("verify failed with %d\n", rec.Code)
+				}
+			}
+
+			mfa = a.ent.MfaQr.Query().OnlyX(c)
+			if mfa.DataKeyID == nil || !mfa.SecretBound {
+				t.Fatal("should be sealed with the data key")
+			}
+			sec, err := DecryptMfaQr(c, a.ent, a.encryptor, mfa)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(sec, key.Secret) {
+				t.Fatal("unexpected secret")
+			}
+		})
+	}
+}
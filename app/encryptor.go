@@ -0,0 +1,89 @@
+package app
+
+import (
+	"errors"
+	"nidan-kai/keystore"
+	"nidan-kai/secret"
+	"nidan-kai/secret/pkcs11key"
+	"nidan-kai/secret/vaulttransit"
+	"os"
+
+	// keystore backends selectable by "KEYSTORE"
+	_ "nidan-kai/keystore/envkey"
+	_ "nidan-kai/keystore/filekey"
+	_ "nidan-kai/keystore/oskeyring"
+	_ "nidan-kai/keystore/passphrase"
+)
+
+const DEFAULT_KEYSTORE = "env"
+
+const SECRET_BACKEND_KEYSTORE = "keystore"
+const SECRET_BACKEND_VAULT = "vault"
+const SECRET_BACKEND_PKCS11 = "pkcs11"
+
+// keystores by config like "file,keyring,env", each is initialized once
+// and shared, as some of them prompt or read a one-shot fd on Init
+type keystores map[string]keystore.Keystore
+
+// DEFAULT_KEYSTORE when config is empty
+func (k keystores) open(config string) (keystore.Keystore, error) {
+	if len(config) == 0 {
+		config = DEFAULT_KEYSTORE
+	}
+	if store, ok := k[config]; ok {
+		return store, nil
+	}
+
+	store, err := keystore.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Init(); err != nil {
+		return nil, err
+	}
+	k[config] = store
+
+	return store, nil
+}
+
+// picks the backend encrypting secrets by "SECRET_BACKEND",
+// secrets are encrypted locally with the keystore by default,
+// which is picked by "KEYSTORE" like "file,keyring,env"
+// trying them in order
+func NewEncryptor() (secret.Encryptor, error) {
+	return newEncryptor(keystores{})
+}
+
+func newEncryptor(stores keystores) (secret.Encryptor, error) {
+	switch os.Getenv("SECRET_BACKEND") {
+	case "", SECRET_BACKEND_KEYSTORE:
+		store, err := stores.open(os.Getenv("KEYSTORE"))
+		if err != nil {
+			return nil, err
+		}
+		// keys are cached rather than fetched on every request
+		return secret.NewSealer(store, secret.DEFAULT_SEALER_TTL), nil
+	case SECRET_BACKEND_VAULT:
+		transit, err := vaulttransit.New()
+		if err != nil {
+			return nil, err
+		}
+		if err := transit.Init(); err != nil {
+			return nil, err
+		}
+		return transit, nil
+	case SECRET_BACKEND_PKCS11:
+		// session is kept open while the process lives
+		hsm, err := pkcs11key.New()
+		if err != nil {
+			return nil, err
+		}
+		if err := hsm.Init(); err != nil {
+			hsm.Close()
+			return nil, err
+		}
+		return hsm, nil
+	default:
+		return nil, errors.New("unknown secret backend")
+	}
+}
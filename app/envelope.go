@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/datakey"
+	"nidan-kai/secret"
+)
+
+func (a *App) createDataKey(c context.Context, userId binid.BinId) (binid.BinId, []byte, error) {
+	id, err := binid.NewSequential()
+	if err != nil {
+		return binid.BinId{}, nil, err
+	}
+
+	dek, wrapped, err := secret.GenerateDataKey(a.encryptor, secret.DataKeyContext(id, userId))
+	if err != nil {
+		return binid.BinId{}, nil, err
+	}
+
+	err = a.ent.DataKey.Create().
+		SetID(id).
+		SetUserID(userId).
+		SetWrappedKey(wrapped).
+		Exec(c)
+	if err != nil {
+		clear(dek)
+		return binid.BinId{}, nil, err
+	}
+
+	return id, dek, nil
+}
+
+func (a *App) unwrapDataKey(dk *ent.DataKey) ([]byte, error) {
+	return a.encryptor.UnwrapKey(dk.WrappedKey, secret.DataKeyContext(dk.ID, dk.UserID))
+}
+
+// returns the data key of the user, created on first use
+func (a *App) dataKeyOf(c context.Context, userId binid.BinId) (binid.BinId, []byte, error) {
+	dk, err := a.ent.DataKey.Query().
+		Where(datakey.UserID(userId)).
+		Only(c)
+	if ent.IsNotFound(err) {
+		id, dek, err := a.createDataKey(c, userId)
+		if !ent.IsConstraintError(err) {
+			return id, dek, err
+		}
+
+		// created by a concurrent request
+		dk, err = a.ent.DataKey.Query().
+			Where(datakey.UserID(userId)).
+			Only(c)
+		if err != nil {
+			return binid.BinId{}, nil, err
+		}
+	} else if err != nil {
+		return binid.BinId{}, nil, err
+	}
+
+	dek, err := a.unwrapDataKey(dk)
+	if err != nil {
+		return binid.BinId{}, nil, err
+	}
+
+	return dk.ID, dek, nil
+}
+
+// seals the secret for the mfa_qrs row with the data key of the user
+func (a *App) sealSecret(
+	c context.Context,
+	secId binid.BinId,
+	userId binid.BinId,
+	sec []byte,
+) (binid.BinId, []byte, error) {
+	dataKeyId, dek, err := a.dataKeyOf(c, userId)
+	if err != nil {
+		return binid.BinId{}, nil, err
+	}
+	defer clear(dek)
+
+	enc, err := secret.Seal(sec, dek, secret.MfaQrContext(secId, userId))
+	if err != nil {
+		return binid.BinId{}, nil, err
+	}
+
+	return dataKeyId, enc, nil
+}
+
+// decrypts the secret of the row with its data key, or the master key,
+// rows encrypted before binding are decrypted without associated data
+func DecryptMfaQr(
+	c context.Context,
+	client *ent.Client,
+	encryptor secret.Encryptor,
+	mfa *ent.MfaQr,
+) ([]byte, error) {
+	ad := secret.MfaQrContext(mfa.ID, mfa.UserID)
+	if mfa.DataKeyID == nil {
+		if !mfa.SecretBound {
+			ad = nil
+		}
+		return encryptor.Decrypt(mfa.Secret, ad)
+	}
+
+	dk, err := client.DataKey.Get(c, *mfa.DataKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	dek, err := encryptor.UnwrapKey(dk.WrappedKey, secret.DataKeyContext(dk.ID, dk.UserID))
+	if err != nil {
+		return nil, err
+	}
+	defer clear(dek)
+
+	return secret.Open(mfa.Secret, dek, ad)
+}
@@ -0,0 +1,239 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/user"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const SESSION_COOKIE = "nidan_kai_session"
+
+// how long forward auth lets the user through after verification
+const SESSION_TTL = 12 * time.Hour
+
+// tells session tokens from the ones returned by verify, see TOKEN_AUDIENCE
+const SESSION_AUDIENCE = "session"
+
+const HEADER_AUTH_USER = "X-Auth-User"
+const HEADER_AUTH_EMAIL = "X-Auth-Email"
+
+// set by nginx with proxy_set_header, others send X-Forwarded-*
+const HEADER_ORIGINAL_URL = "X-Original-URL"
+const HEADER_FORWARDED_HOST = "X-Forwarded-Host"
+const HEADER_FORWARDED_URI = "X-Forwarded-Uri"
+
+var errRedirectNotAllowed = errors.New("redirect target is not allowed")
+
+type ForwardAuthConfig struct {
+	// users are sent here with the original url in "rd" when not verified
+	LoginUrl string
+	// hosts users can be sent back to, ".example.com" for subdomains
+	AllowedHosts []string
+	// to share the session between subdomains, the host only when empty
+	CookieDomain string
+}
+
+// read from "FORWARD_AUTH_LOGIN_URL", "FORWARD_AUTH_ALLOWED_HOSTS"
+// like "tool.example.com,.internal.example.com" and "SESSION_COOKIE_DOMAIN"
+func NewForwardAuthConfig() (ForwardAuthConfig, error) {
+	config := ForwardAuthConfig{
+		LoginUrl:     os.Getenv("FORWARD_AUTH_LOGIN_URL"),
+		CookieDomain: os.Getenv("SESSION_COOKIE_DOMAIN"),
+	}
+
+	if len(config.LoginUrl) > 0 {
+		u, err := url.Parse(config.LoginUrl)
+		if err != nil {
+			return config, err
+		}
+		if !u.IsAbs() {
+			return config, errors.New("login url should be absolute")
+		}
+	}
+
+	for _, raw := range strings.Split(os.Getenv("FORWARD_AUTH_ALLOWED_HOSTS"), ",") {
+		host := strings.ToLower(strings.TrimSpace(raw))
+		if len(host) == 0 {
+			continue
+		}
+		if host == "." || strings.ContainsAny(host, "/:@*") {
+			return config, errors.New("invalid allowed host")
+		}
+		config.AllowedHosts = append(config.AllowedHosts, host)
+	}
+
+	return config, nil
+}
+
+// only absolute http urls to the allowed hosts, so that
+// forward auth can't be used as an open redirect
+func (c ForwardAuthConfig) checkRedirect(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, errRedirectNotAllowed
+	}
+	if u.User != nil {
+		return nil, errRedirectNotAllowed
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if len(host) == 0 {
+		return nil, errRedirectNotAllowed
+	}
+	for _, allowed := range c.AllowedHosts {
+		if host == allowed {
+			return u, nil
+		}
+		if strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed) {
+			return u, nil
+		}
+	}
+
+	return nil, errRedirectNotAllowed
+}
+
+// the url requested to the proxy, nginx doesn't send it unless configured
+func originalUrl(req *http.Request) string {
+	if raw := req.Header.Get(HEADER_ORIGINAL_URL); len(raw) > 0 {
+		return raw
+	}
+
+	proto := req.Header.Get(echo.HeaderXForwardedProto)
+	host := req.Header.Get(HEADER_FORWARDED_HOST)
+	if len(proto) == 0 || len(host) == 0 {
+		return ""
+	}
+
+	return proto + "://" + host + req.Header.Get(HEADER_FORWARDED_URI)
+}
+
+func (a *App) setSessionCookie(ctx echo.Context, u *ent.User, now time.Time) error {
+	tok, err := a.issueToken(u, now, SESSION_AUDIENCE, SESSION_TTL)
+	if err != nil {
+		return err
+	}
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     SESSION_COOKIE,
+		Value:    tok,
+		Path:     "/",
+		Domain:   a.forwardAuth.CookieDomain,
+		MaxAge:   int(SESSION_TTL.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+// returns the user of a valid session
+func (a *App) sessionUser(ctx echo.Context) (*ent.User, error) {
+	cookie, err := ctx.Cookie(SESSION_COOKIE)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := a.signer.Verify(cookie.Value, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Issuer != a.appName || claims.Audience != SESSION_AUDIENCE {
+		return nil, errors.New("not a session token")
+	}
+
+	id, err := binid.FromUUIDString(claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	// deleted users are logged out, and so are users who left mfa
+	// or reset the secret after authentication, at second precision
+	authTime := time.Unix(claims.AuthTime+1, 0)
+	return a.ent.User.Query().
+		Select(
+			user.FieldID,
+			user.FieldEmail,
+		).
+		Where(
+			user.ID(id),
+			user.LoginMethodEQ(user.LoginMethodMfaQr),
+			user.DeletedAtIsNil(),
+			user.HasMfaQrsWith(
+				mfaqr.StatusEQ(mfaqr.StatusActive),
+				mfaqr.DeletedAtIsNil(),
+				mfaqr.Or(
+					mfaqr.ActivatedAtLT(authTime),
+					mfaqr.And(
+						mfaqr.ActivatedAtIsNil(),
+						mfaqr.CreatedAtLT(authTime),
+					),
+				),
+			),
+		).
+		Only(ctx.Request().Context())
+}
+
+// browsers send this for pages, api clients rarely do
+const MIME_TEXT_HTML = "text/html"
+
+// to be sent to the login page rather than told 401, nginx auth_request
+// fails on redirects, so its error_page follows Location of 401 instead
+func isBrowser(req *http.Request) bool {
+	if len(req.Header.Get(HEADER_ORIGINAL_URL)) > 0 {
+		return false
+	}
+	return strings.Contains(req.Header.Get(echo.HeaderAccept), MIME_TEXT_HTML)
+}
+
+// for nginx auth_request and traefik or caddy forward auth,
+// lets the request through with the identity in headers,
+// or tells where to log in, with 302 for browsers and 401 for api clients
+func (a *App) ForwardAuth(ctx echo.Context) error {
+	u, err := a.sessionUser(ctx)
+	if err == nil {
+		ctx.Response().Header().Set(HEADER_AUTH_USER, u.ID.String())
+		ctx.Response().Header().Set(HEADER_AUTH_EMAIL, u.Email)
+		return ctx.NoContent(http.StatusOK)
+	}
+	if !errors.Is(err, http.ErrNoCookie) && !ent.IsNotFound(err) {
+		ctx.Logger().Warn(err)
+	}
+
+	if len(a.forwardAuth.LoginUrl) == 0 {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	login, err := url.Parse(a.forwardAuth.LoginUrl)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	// sent to the login page without the way back rather than anywhere
+	if target, err := a.forwardAuth.checkRedirect(originalUrl(ctx.Request())); err == nil {
+		query := login.Query()
+		query.Set("rd", target.String())
+		login.RawQuery = query.Encode()
+	} else {
+		ctx.Logger().Warn(err)
+	}
+
+	if isBrowser(ctx.Request()) {
+		return ctx.Redirect(http.StatusFound, login.String())
+	}
+	ctx.Response().Header().Set(echo.HeaderLocation, login.String())
+	return ctx.NoContent(http.StatusUnauthorized)
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"net/http"
+	"nidan-kai/ent"
+	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/user"
+	"nidan-kai/nidankai"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ResyncRequest struct {
+	Email    string `form:"email" validate:"required,email,max=256"`
+	Code     string `form:"code" validate:"required,number,min=6,max=8"`
+	NextCode string `form:"next_code" validate:"required,number,min=6,max=8"`
+}
+
+// resynchronizes hotp counter with two consecutive codes,
+// for hardware tokens drifted beyond the look-ahead window
+func (a *App) Resync(ctx echo.Context) error {
+	form := ResyncRequest{}
+
+	if err := a.bind(ctx, &form); err != nil {
+		ctx.Logger().Warn(err)
+		return echo.ErrBadRequest
+	}
+
+	c := ctx.Request().Context()
+	u, err := a.findUser(c, form.Email)
+	if ent.IsNotFound(err) {
+		ctx.Logger().Warn("could not find user")
+		return echo.ErrBadRequest
+	} else if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	if u.LoginMethod != user.LoginMethodMfaQr {
+		ctx.Logger().Warn("wrong login method")
+		return echo.ErrBadRequest
+	}
+
+	mfa, err := a.findActiveMfaQr(c, u.ID)
+	if ent.IsNotFound(err) {
+		ctx.Logger().Warn("could not find active secret")
+		return echo.ErrBadRequest
+	} else if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	if mfa.Type != mfaqr.TypeHotp {
+		ctx.Logger().Warn("resync is only for hotp")
+		return echo.ErrBadRequest
+	}
+
+	now := time.Now()
+	until, err := a.takeAttempt(ctx, u, mfa, now)
+	if err != nil {
+		return err
+	}
+
+	params := paramsOf(mfa)
+	if len(form.Code) != int(params.Digits) || len(form.NextCode) != int(params.Digits) {
+		ctx.Logger().Warn("unexpected code length")
+		return failedAttempt(ctx, until, now)
+	}
+
+	code, err := strconv.Atoi(form.Code)
+	if err != nil {
+		ctx.Logger().Warn(err)
+		return failedAttempt(ctx, until, now)
+	}
+	nextCode, err := strconv.Atoi(form.NextCode)
+	if err != nil {
+		ctx.Logger().Warn(err)
+		return failedAttempt(ctx, until, now)
+	}
+
+	sec, err := a.decryptSecret(ctx, mfa)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	counter, ok, err := nidankai.Resync(
+		code,
+		nextCode,
+		sec,
+		params,
+		mfa.Counter,
+		nidankai.QR_MFA_HOTP_RESYNC_WINDOW,
+	)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+	if !ok {
+		ctx.Logger().Warn("could not resync")
+		return failedAttempt(ctx, until, now)
+	}
+
+	update := a.ent.MfaQr.Update().
+		Where(mfaqr.ID(mfa.ID))
+	n, err := consumeStep(update, mfa, counter).Save(c)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+	if n == 0 {
+		ctx.Logger().Warn("counter is already moved")
+		return failedAttempt(ctx, until, now)
+	}
+
+	if err := a.resetAttempts(c, u, mfa); err != nil {
+		ctx.Logger().Warn(err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math"
+	"nidan-kai/ent"
+	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/user"
+	"strconv"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/labstack/echo/v4"
+)
+
+// attempts within the window before locking out
+const MAX_FAILED_ATTEMPTS = 5
+
+// attempts older than this are forgotten on the next one
+const FAILURE_WINDOW = 15 * time.Minute
+
+// the first lockout, doubled for each attempt after it
+const LOCKOUT_DURATION = time.Minute
+const MAX_LOCKOUT_DURATION = 24 * time.Hour
+
+// tries to take an attempt against concurrent ones,
+// the attempt is rejected as locked out after this
+const MAX_ATTEMPT_RETRIES = 5
+
+// zero while attempts are under the limit
+func lockoutDuration(attempts uint32) time.Duration {
+	if attempts < MAX_FAILED_ATTEMPTS {
+		return 0
+	}
+
+	d := LOCKOUT_DURATION
+	for i := uint32(MAX_FAILED_ATTEMPTS); i < attempts; i++ {
+		d *= 2
+		if d >= MAX_LOCKOUT_DURATION {
+			return MAX_LOCKOUT_DURATION
+		}
+	}
+
+	return d
+}
+
+// columns of the lockout mixin, the same for users and secrets
+var lockoutFields = []string{
+	user.FieldFailedAttempts,
+	user.FieldLastFailedAt,
+	user.FieldLockedUntil,
+}
+
+// attempts of a user or secret as stored
+type attempts struct {
+	Count        uint32     `sql:"failed_attempts"`
+	LastFailedAt *time.Time `sql:"last_failed_at"`
+	LockedUntil  *time.Time `sql:"locked_until"`
+}
+
+// mutation of users and secrets, on the fields of the lockout mixin
+type lockoutMutation interface {
+	WhereP(ps ...func(*sql.Selector))
+	SetFailedAttempts(count uint32)
+	SetLastFailedAt(t time.Time)
+	SetLockedUntil(t time.Time)
+}
+
+// counts the attempt up before the code is checked, so that concurrent
+// attempts can't pass the limit all together, the one hitting the limit
+// sets the lockout and returns when it ends, or returns false with it
+// when already locked out, load scans lockoutFields of the row into
+// *[]attempts, and update saves the row with the mutation applied
+func takeAttempt(
+	now time.Time,
+	load func(v any) error,
+	update func(mutate func(m lockoutMutation)) (int, error),
+) (bool, *time.Time, error) {
+	for range MAX_ATTEMPT_RETRIES {
+		rows := []attempts{}
+		if err := load(&rows); err != nil {
+			return false, nil, err
+		}
+		if len(rows) != 1 {
+			return false, nil, errors.New("could not find the row to take attempt")
+		}
+		old := rows[0]
+		if old.LockedUntil != nil && old.LockedUntil.After(now) {
+			return false, old.LockedUntil, nil
+		}
+
+		// forgotten when neither failed nor locked out within the window
+		latest := old.LastFailedAt
+		if old.LockedUntil != nil && (latest == nil || old.LockedUntil.After(*latest)) {
+			latest = old.LockedUntil
+		}
+		count := old.Count
+		if latest == nil || latest.Before(now.Add(-FAILURE_WINDOW)) {
+			count = 0
+		}
+		count++
+
+		var until *time.Time
+		if d := lockoutDuration(count); d > 0 {
+			t := now.Add(d)
+			until = &t
+		}
+
+		// only when the row is unchanged since loaded
+		n, err := update(func(m lockoutMutation) {
+			lastFailedAt := sql.FieldIsNull(user.FieldLastFailedAt)
+			if old.LastFailedAt != nil {
+				lastFailedAt = sql.FieldEQ(user.FieldLastFailedAt, *old.LastFailedAt)
+			}
+			m.WhereP(
+				sql.FieldEQ(user.FieldFailedAttempts, old.Count),
+				lastFailedAt,
+			)
+			m.SetFailedAttempts(count)
+			m.SetLastFailedAt(now)
+			if until != nil {
+				m.SetLockedUntil(*until)
+			}
+		})
+		if err != nil {
+			return false, nil, err
+		}
+		if n == 1 {
+			return true, until, nil
+		}
+	}
+
+	// taken by a burst of others meanwhile
+	retry := now.Add(time.Second)
+	return false, &retry, nil
+}
+
+func tooManyAttempts(ctx echo.Context, until time.Time, now time.Time) error {
+	secs := int(math.Ceil(until.Sub(now).Seconds()))
+	ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(secs, 1)))
+	return echo.ErrTooManyRequests
+}
+
+// takes an attempt of the user, and of the secret unless nil,
+// before checking a code, returns the lockout set by this attempt,
+// or echo errors to be returned from handlers as they are
+func (a *App) takeAttempt(
+	ctx echo.Context,
+	u *ent.User,
+	mfa *ent.MfaQr,
+	now time.Time,
+) (*time.Time, error) {
+	c := ctx.Request().Context()
+
+	ok, until, err := takeAttempt(
+		now,
+		func(v any) error {
+			return a.ent.User.Query().Where(user.ID(u.ID)).Select(lockoutFields...).Scan(c, v)
+		},
+		func(mutate func(m lockoutMutation)) (int, error) {
+			update := a.ent.User.Update().Where(user.ID(u.ID))
+			mutate(update.Mutation())
+			return update.Save(c)
+		},
+	)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.ErrInternalServerError
+	}
+	if !ok {
+		ctx.Logger().Warn("user is locked out")
+		return nil, tooManyAttempts(ctx, *until, now)
+	}
+	if mfa == nil {
+		return until, nil
+	}
+
+	ok, mfaUntil, err := takeAttempt(
+		now,
+		func(v any) error {
+			return a.ent.MfaQr.Query().Where(mfaqr.ID(mfa.ID)).Select(lockoutFields...).Scan(c, v)
+		},
+		func(mutate func(m lockoutMutation)) (int, error) {
+			update := a.ent.MfaQr.Update().Where(mfaqr.ID(mfa.ID))
+			mutate(update.Mutation())
+			return update.Save(c)
+		},
+	)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.ErrInternalServerError
+	}
+	if !ok {
+		ctx.Logger().Warn("secret is locked out")
+		return nil, tooManyAttempts(ctx, *mfaUntil, now)
+	}
+
+	if mfaUntil != nil && (until == nil || mfaUntil.After(*until)) {
+		until = mfaUntil
+	}
+	return until, nil
+}
+
+// for a wrong code, 429 when the attempt hit the limit
+func failedAttempt(ctx echo.Context, until *time.Time, now time.Time) error {
+	if until != nil {
+		return tooManyAttempts(ctx, *until, now)
+	}
+	return echo.ErrBadRequest
+}
+
+// forgets attempts on success, of the secret as well unless nil
+func (a *App) resetAttempts(c context.Context, u *ent.User, mfa *ent.MfaQr) error {
+	err := a.ent.User.Update().
+		Where(user.ID(u.ID)).
+		SetFailedAttempts(0).
+		ClearLastFailedAt().
+		ClearLockedUntil().
+		Exec(c)
+	if err != nil || mfa == nil {
+		return err
+	}
+
+	return a.ent.MfaQr.Update().
+		Where(mfaqr.ID(mfa.ID)).
+		SetFailedAttempts(0).
+		ClearLastFailedAt().
+		ClearLockedUntil().
+		Exec(c)
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"errors"
+	"nidan-kai/ratelimit"
+	"os"
+)
+
+const RATE_LIMIT_STORE_MEMORY = "memory"
+const RATE_LIMIT_STORE_SQL = "sql"
+
+// picks the store by "RATE_LIMIT_STORE", in memory by default,
+// which has to be "sql" to share limits between server instances
+func (a *App) NewRateLimitStore() (ratelimit.Store, error) {
+	switch os.Getenv("RATE_LIMIT_STORE") {
+	case "", RATE_LIMIT_STORE_MEMORY:
+		return ratelimit.NewMemoryStore(), nil
+	case RATE_LIMIT_STORE_SQL:
+		return ratelimit.NewSqlStore(a.ent), nil
+	default:
+		return nil, errors.New("unknown rate limit store")
+	}
+}
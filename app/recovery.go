@@ -0,0 +1,214 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/recoverycode"
+	"nidan-kai/ent/user"
+	"nidan-kai/recovery"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RecoverRequest struct {
+	Email string `form:"email" validate:"required,email,max=256"`
+	Code  string `form:"recovery_code" validate:"required,max=32"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	Email string `form:"email" validate:"required,email,max=256"`
+	Code  string `form:"code" validate:"required,number,min=6,max=8"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+var errRecoveryCodeUsed = errors.New("recovery code is already used")
+
+// returns codes to be shown once and their hashes to be stored,
+// this is slow, so do this before opening transaction
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes, err := recovery.GenerateSet()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		h, err := recovery.Hash(code)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashes[i] = h
+	}
+
+	return codes, hashes, nil
+}
+
+// invalidates current codes of the user and stores new ones
+func replaceRecoveryCodes(
+	c context.Context,
+	tx *ent.Tx,
+	userId binid.BinId,
+	hashes []string,
+	now time.Time,
+) error {
+	err := tx.RecoveryCode.Update().
+		Where(
+			recoverycode.UserID(userId),
+			recoverycode.DeletedAtIsNil(),
+		).
+		SetDeletedAt(now).
+		Exec(c)
+	if err != nil {
+		return err
+	}
+
+	builders := make([]*ent.RecoveryCodeCreate, len(hashes))
+	for i, h := range hashes {
+		id, err := binid.NewSequential()
+		if err != nil {
+			return err
+		}
+
+		builders[i] = tx.RecoveryCode.Create().
+			SetID(id).
+			SetHash(h).
+			SetUserID(userId)
+	}
+
+	return tx.RecoveryCode.CreateBulk(builders...).Exec(c)
+}
+
+// accepts a recovery code instead of totp, each code can be used once,
+// the session is issued as verify does
+func (a *App) Recover(ctx echo.Context) error {
+	form := RecoverRequest{}
+
+	if err := a.bind(ctx, &form); err != nil {
+		ctx.Logger().Warn(err)
+		return echo.ErrBadRequest
+	}
+
+	if _, err := recovery.Normalize(form.Code); err != nil {
+		ctx.Logger().Warn(err)
+		return echo.ErrBadRequest
+	}
+
+	c := ctx.Request().Context()
+	u, err := a.findUser(c, form.Email)
+	if ent.IsNotFound(err) {
+		ctx.Logger().Warn("could not find user")
+		return echo.ErrBadRequest
+	} else if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	if u.LoginMethod != user.LoginMethodMfaQr {
+		ctx.Logger().Warn("wrong login method")
+		return echo.ErrBadRequest
+	}
+
+	// counted before hashing, which is slow
+	now := time.Now()
+	until, err := a.takeAttempt(ctx, u, nil, now)
+	if err != nil {
+		return err
+	}
+
+	rcs, err := a.ent.RecoveryCode.Query().
+		Select(
+			recoverycode.FieldID,
+			recoverycode.FieldHash,
+		).
+		Where(
+			recoverycode.UserID(u.ID),
+			recoverycode.UsedAtIsNil(),
+			recoverycode.DeletedAtIsNil(),
+		).
+		All(c)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	var matched *ent.RecoveryCode
+	for _, rc := range rcs {
+		ok, err := recovery.Compare(form.Code, rc.Hash)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return echo.ErrInternalServerError
+		}
+		if ok {
+			matched = rc
+			break
+		}
+	}
+	if matched == nil {
+		ctx.Logger().Warn("invalid recovery code")
+		return failedAttempt(ctx, until, now)
+	}
+
+	// burn it, the condition makes this atomic between concurrent requests
+	n, err := a.ent.RecoveryCode.Update().
+		Where(
+			recoverycode.ID(matched.ID),
+			recoverycode.UsedAtIsNil(),
+		).
+		SetUsedAt(now).
+		Save(c)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+	if n == 0 {
+		ctx.Logger().Warn(errRecoveryCodeUsed)
+		return failedAttempt(ctx, until, now)
+	}
+
+	if err := a.resetAttempts(c, u, nil); err != nil {
+		ctx.Logger().Warn(err)
+	}
+
+	return a.issueSession(ctx, u)
+}
+
+// issues a new set of recovery codes, the old set stops working
+func (a *App) RegenerateRecoveryCodes(ctx echo.Context) error {
+	form := RegenerateRecoveryCodesRequest{}
+
+	if err := a.bind(ctx, &form); err != nil {
+		ctx.Logger().Warn(err)
+		return echo.ErrBadRequest
+	}
+
+	u, err := a.authenticate(ctx, form.Email, form.Code)
+	if err != nil {
+		return err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	c := ctx.Request().Context()
+	err = a.withTx(c, func(tx *ent.Tx) error {
+		return replaceRecoveryCodes(c, tx, u.ID, hashes, time.Now())
+	})
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.JSON(http.StatusOK, RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
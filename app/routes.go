@@ -0,0 +1,27 @@
+package app
+
+import (
+	"nidan-kai/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// registers the api, endpoints taking codes are limited per client ip
+// by limits, in addition to locking out users
+func (a *App) Route(e *echo.Echo, limits ratelimit.Store) {
+	setupLimit := ratelimit.Middleware(limits, ratelimit.PerMinute(5))
+	verifyLimit := ratelimit.Middleware(limits, ratelimit.PerMinute(10))
+	// hashes up to all the codes of the user per request
+	recoverLimit := ratelimit.Middleware(limits, ratelimit.PerMinute(5))
+
+	e.POST("/api/mfa/qr/setup", a.SetUp, setupLimit)
+	e.POST("/api/mfa/qr/confirm", a.Confirm, verifyLimit)
+	e.POST("/api/mfa/qr/verify", a.Verify, verifyLimit)
+	e.POST("/api/mfa/qr/resync", a.Resync, verifyLimit)
+	e.POST("/api/mfa/qr/recover", a.Recover, recoverLimit)
+	e.POST("/api/mfa/qr/recovery/regenerate", a.RegenerateRecoveryCodes, verifyLimit)
+
+	e.GET("/.well-known/jwks.json", a.Jwks)
+	// the method of the original request may be passed as it is
+	e.Any("/api/auth/forward", a.ForwardAuth)
+}
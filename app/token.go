@@ -0,0 +1,105 @@
+package app
+
+import (
+	"net/http"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/token"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// tokens are only proof of mfa just passed, not sessions
+const TOKEN_TTL = 5 * time.Minute
+
+// services verifying tokens with jwks check this
+// not to take session tokens as the ones returned by verify
+const TOKEN_AUDIENCE = "mfa"
+
+type TokenResponse struct {
+	Token     string `json:"token"`
+	TokenType string `json:"token_type"`
+	// seconds
+	ExpiresIn int `json:"expires_in"`
+}
+
+// signing keys are derived from the keystore picked by
+// "SIGNING_KEYSTORE", or the one for secrets by "KEYSTORE",
+// so that they are available with any secret backend
+func NewSigner() (*token.Signer, error) {
+	return newSigner(keystores{})
+}
+
+func newSigner(stores keystores) (*token.Signer, error) {
+	config := os.Getenv("SIGNING_KEYSTORE")
+	if len(config) == 0 {
+		config = os.Getenv("KEYSTORE")
+	}
+	store, err := stores.open(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return token.NewSigner(store, token.DEFAULT_SIGNER_TTL), nil
+}
+
+func (a *App) issueToken(
+	u *ent.User,
+	now time.Time,
+	audience string,
+	ttl time.Duration,
+) (string, error) {
+	id, err := binid.NewSequential()
+	if err != nil {
+		return "", err
+	}
+
+	return a.signer.Sign(token.Claims{
+		Issuer:    a.appName,
+		Subject:   u.ID.String(),
+		Audience:  audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+		AuthTime:  now.Unix(),
+		Amr:       []string{token.AMR_OTP},
+		Id:        id.String(),
+	})
+}
+
+// responds with a bearer token and sets the session cookie
+// once the user is authenticated
+func (a *App) issueSession(ctx echo.Context, u *ent.User) error {
+	now := time.Now()
+	tok, err := a.issueToken(u, now, TOKEN_AUDIENCE, TOKEN_TTL)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+	if err := a.setSessionCookie(ctx, u, now); err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.JSON(http.StatusOK, TokenResponse{
+		Token:     tok,
+		TokenType: "Bearer",
+		ExpiresIn: int(TOKEN_TTL.Seconds()),
+	})
+}
+
+// publishes public keys for services verifying tokens
+func (a *App) Jwks(ctx echo.Context) error {
+	set, err := a.signer.Jwks()
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.ErrInternalServerError
+	}
+
+	// new keys are picked up by verifiers within the signer ttl
+	maxAge := int(token.DEFAULT_SIGNER_TTL.Seconds())
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "public, max-age="+strconv.Itoa(maxAge))
+	return ctx.JSON(http.StatusOK, set)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"nidan-kai/app"
+	"nidan-kai/ent"
+	"nidan-kai/ent/mfaqr"
+	"nidan-kai/keystore"
+	"nidan-kai/keystore/filekey"
+	"nidan-kai/keystore/oskeyring"
+	"nidan-kai/keystore/passphrase"
+	"nidan-kai/secret/pkcs11key"
+	"os"
+
+	"entgo.io/ent/dialect/sql"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: keytool <command> [flags]
+
+commands:
+  generate     print a new base64 key for ENV_SECRET_KEY, or write it to -out
+  write        generate a key and store it in -to file, keyring or pkcs11
+  keyring      rotate, cleanup or delete keys in the os keyring
+  passphrase   write argon2id params for a new passphrase to -params
+  fingerprint  print ids of keys in the keystore configured by KEYSTORE
+  verify       decrypt a sample of mfa_qrs rows with the configured backend
+`
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, args := os.Args[1], os.Args[2:]
+	var err error
+	switch cmd {
+	case "generate":
+		err = generate(args)
+	case "write":
+		err = write(args)
+	case "keyring":
+		err = manageKeyring(args)
+	case "passphrase":
+		err = newPassphrase(args)
+	case "fingerprint":
+		err = fingerprint(args)
+	case "verify":
+		err = verify(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func loadEnv() {
+	if err := godotenv.Load("../../.env"); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, keystore.KEY_SIZE)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// key id is not secret, safe to print and compare
+func printKeyId(key []byte) {
+	log.Printf("key id: %s\n", keystore.NewKeyId(key).String())
+}
+
+func generate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	out := fs.String("out", "", "file to write the key to, printed if empty")
+	raw := fs.Bool("raw", false, "write raw 32 bytes instead of base64")
+	fs.Parse(args)
+
+	key, err := newKey()
+	if err != nil {
+		return err
+	}
+	defer clear(key)
+
+	if len(*out) > 0 {
+		if err := filekey.WriteKey(*out, key, *raw); err != nil {
+			return err
+		}
+	} else if *raw {
+		return errors.New("raw key should be written to a file with -out")
+	} else {
+		fmt.Println(base64.StdEncoding.EncodeToString(key))
+	}
+
+	printKeyId(key)
+	return nil
+}
+
+func write(args []string) error {
+	fs := flag.NewFlagSet("write", flag.ExitOnError)
+	to := fs.String("to", "", "file, keyring or pkcs11")
+	path := fs.String("path", "", "file path for -to file")
+	raw := fs.Bool("raw", false, "write raw 32 bytes instead of base64 for -to file")
+	fs.Parse(args)
+
+	switch *to {
+	case "file":
+		if len(*path) == 0 {
+			return errors.New("-path is required for file")
+		}
+		key, err := newKey()
+		if err != nil {
+			return err
+		}
+		defer clear(key)
+
+		if err := filekey.WriteKey(*path, key, *raw); err != nil {
+			return err
+		}
+		log.Printf("wrote key to %s\n", *path)
+		printKeyId(key)
+	case "keyring":
+		loadEnv()
+		key, err := newKey()
+		if err != nil {
+			return err
+		}
+		defer clear(key)
+
+		if err := oskeyring.SetKey(key); err != nil {
+			return err
+		}
+		log.Println("wrote key to keyring")
+		printKeyId(key)
+	case "pkcs11":
+		// generated on the token, never leaves it
+		loadEnv()
+		hsm, err := pkcs11key.New()
+		if err != nil {
+			return err
+		}
+		defer hsm.Close()
+
+		if err := hsm.Provision(); err != nil {
+			return err
+		}
+		log.Println("generated key on pkcs11 token")
+	default:
+		return errors.New("-to should be file, keyring or pkcs11")
+	}
+
+	return nil
+}
+
+func manageKeyring(args []string) error {
+	fs := flag.NewFlagSet("keyring", flag.ExitOnError)
+	action := fs.String("action", "", "rotate, cleanup or delete")
+	keep := fs.Int("keep", 1, "previous keys to keep on cleanup")
+	fs.Parse(args)
+	loadEnv()
+
+	switch *action {
+	case "rotate":
+		version, err := oskeyring.Rotate()
+		if err != nil {
+			return err
+		}
+		log.Printf("rotated to version %d, re-encrypt secrets before cleanup\n", version)
+	case "cleanup":
+		removed, err := oskeyring.Cleanup(*keep)
+		if err != nil {
+			return err
+		}
+		log.Printf("removed versions %v\n", removed)
+	case "delete":
+		if err := oskeyring.Delete(); err != nil {
+			return err
+		}
+		log.Println("deleted all keys in keyring")
+	default:
+		return errors.New("-action should be rotate, cleanup or delete")
+	}
+
+	return nil
+}
+
+func newPassphrase(args []string) error {
+	cost := passphrase.DefaultCost()
+	fs := flag.NewFlagSet("passphrase", flag.ExitOnError)
+	path := fs.String("params", "", "file to write params to")
+	time := fs.Uint("time", uint(cost.Time), "argon2id iterations")
+	memory := fs.Uint("memory", uint(cost.Memory), "argon2id memory in KiB")
+	threads := fs.Uint("threads", uint(cost.Threads), "argon2id threads")
+	fs.Parse(args)
+
+	if len(*path) == 0 {
+		return errors.New("-params is required")
+	}
+	if _, err := os.Stat(*path); err == nil {
+		return errors.New("params file already exists")
+	}
+	cost.Time = uint32(*time)
+	cost.Memory = uint32(*memory)
+	cost.Threads = uint8(*threads)
+
+	pass, err := passphrase.PromptPassphrase("new passphrase: ")
+	if err != nil {
+		return err
+	}
+	defer clear(pass)
+	confirm, err := passphrase.PromptPassphrase("confirm passphrase: ")
+	if err != nil {
+		return err
+	}
+	defer clear(confirm)
+	if !bytes.Equal(pass, confirm) {
+		return errors.New("passphrases do not match")
+	}
+
+	params, err := passphrase.NewParams(pass, cost)
+	if err != nil {
+		return err
+	}
+	if err := passphrase.WriteParams(*path, params); err != nil {
+		return err
+	}
+
+	log.Printf("wrote params to %s\n", *path)
+	return nil
+}
+
+func fingerprint(args []string) error {
+	fs := flag.NewFlagSet("fingerprint", flag.ExitOnError)
+	fs.Parse(args)
+	loadEnv()
+
+	config := os.Getenv("KEYSTORE")
+	if len(config) == 0 {
+		config = app.DEFAULT_KEYSTORE
+	}
+	store, err := keystore.Parse(config)
+	if err != nil {
+		return err
+	}
+	if err := store.Init(); err != nil {
+		return err
+	}
+
+	keys, err := keystore.GetKeys(store)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		role := "previous"
+		if i == 0 {
+			role = "primary"
+		}
+		fmt.Printf("%s %s\n", keystore.NewKeyId(key).String(), role)
+		clear(key)
+	}
+
+	return nil
+}
+
+func verify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	sample := fs.Int("sample", 100, "number of latest rows to decrypt")
+	fs.Parse(args)
+	loadEnv()
+
+	if *sample <= 0 {
+		return errors.New("-sample should be positive")
+	}
+
+	mysqlUri := os.Getenv("MYSQL_URI")
+	if len(mysqlUri) == 0 {
+		return errors.New("could not found env for mysql uri")
+	}
+
+	client, err := ent.Open("mysql", mysqlUri)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	encryptor, err := app.NewEncryptor()
+	if err != nil {
+		return err
+	}
+
+	c := context.Background()
+	rows, err := client.MfaQr.Query().
+		Where(mfaqr.DeletedAtIsNil()).
+		Order(sql.OrderByField(mfaqr.FieldID, sql.OrderDesc()).ToFunc()).
+		Limit(*sample).
+		All(c)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, row := range rows {
+		sec, err := app.DecryptMfaQr(c, client, encryptor, row)
+		if err != nil {
+			log.Printf("failed to decrypt %s: %v\n", row.ID.String(), err)
+			failed++
+			continue
+		}
+		clear(sec)
+	}
+
+	log.Printf("decrypted %d of %d rows\n", len(rows)-failed, len(rows))
+	if failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"nidan-kai/app"
+	"nidan-kai/ent"
+	"nidan-kai/reencrypt"
+	"os"
+	"os/signal"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+)
+
+// re-encrypts all secrets, or re-wraps data keys with -data-keys,
+// under the primary key after rotation,
+// keep previous keys configured until this completes,
+// exits with 1 when any row failed, run again to retry them
+func main() {
+	dataKeys := flag.Bool("data-keys", false, "re-wrap data keys instead of secrets")
+	opts := reencrypt.DefaultOptions()
+	flag.StringVar(&opts.Name, "name", opts.Name, "checkpoint name")
+	flag.IntVar(&opts.BatchSize, "batch", opts.BatchSize, "rows per batch")
+	flag.BoolVar(&opts.Restart, "restart", false, "ignore checkpoint and start from the first row")
+	flag.Parse()
+
+	run := reencrypt.Run
+	if *dataKeys {
+		run = reencrypt.Rewrap
+		if opts.Name == reencrypt.DEFAULT_JOB_NAME {
+			opts.Name = reencrypt.DEFAULT_REWRAP_JOB_NAME
+		}
+	}
+
+	if err := godotenv.Load("../../.env"); err != nil {
+		log.Fatalln(err)
+	}
+
+	mysqlUri := os.Getenv("MYSQL_URI")
+	if len(mysqlUri) == 0 {
+		log.Fatalln("could not found env for mysql uri")
+	}
+
+	client, err := ent.Open("mysql", mysqlUri)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer client.Close()
+
+	encryptor, err := app.NewEncryptor()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// interrupted job resumes from the checkpoint next time
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	log.Println("re-encrypting...")
+	stats, err := run(ctx, client, encryptor, opts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Printf(
+		"done scanned: %d re-encrypted: %d skipped: %d failed: %d\n",
+		stats.Scanned,
+		stats.Reencrypted,
+		stats.Skipped,
+		stats.Failed,
+	)
+	if stats.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,198 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
+	"nidan-kai/ent/user"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// DataKey is the model entity for the DataKey schema.
+type DataKey struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID binid.BinId `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// DeletedAt holds the value of the "deleted_at" field.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// WrappedKey holds the value of the "wrapped_key" field.
+	WrappedKey []byte `json:"-"`
+	// UserID holds the value of the "user_id" field.
+	UserID binid.BinId `json:"user_id,omitempty"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the DataKeyQuery when eager-loading is set.
+	Edges        DataKeyEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// DataKeyEdges holds the relations/edges for other nodes in the graph.
+type DataKeyEdges struct {
+	// User holds the value of the user edge.
+	User *User `json:"user,omitempty"`
+	// MfaQrs holds the value of the mfa_qrs edge.
+	MfaQrs []*MfaQr `json:"mfa_qrs,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [2]bool
+}
+
+// UserOrErr returns the User value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e DataKeyEdges) UserOrErr() (*User, error) {
+	if e.User != nil {
+		return e.User, nil
+	} else if e.loadedTypes[0] {
+		return nil, &NotFoundError{label: user.Label}
+	}
+	return nil, &NotLoadedError{edge: "user"}
+}
+
+// MfaQrsOrErr returns the MfaQrs value or an error if the edge
+// was not loaded in eager-loading.
+func (e DataKeyEdges) MfaQrsOrErr() ([]*MfaQr, error) {
+	if e.loadedTypes[1] {
+		return e.MfaQrs, nil
+	}
+	return nil, &NotLoadedError{edge: "mfa_qrs"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*DataKey) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case datakey.FieldWrappedKey:
+			values[i] = new([]byte)
+		case datakey.FieldID, datakey.FieldUserID:
+			values[i] = new(binid.BinId)
+		case datakey.FieldCreatedAt, datakey.FieldUpdatedAt, datakey.FieldDeletedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the DataKey fields.
+func (_m *DataKey) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case datakey.FieldID:
+			if value, ok := values[i].(*binid.BinId); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value != nil {
+				_m.ID = *value
+			}
+		case datakey.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		case datakey.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				_m.UpdatedAt = value.Time
+			}
+		case datakey.FieldDeletedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field deleted_at", values[i])
+			} else if value.Valid {
+				_m.DeletedAt = new(time.Time)
+				*_m.DeletedAt = value.Time
+			}
+		case datakey.FieldWrappedKey:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field wrapped_key", values[i])
+			} else if value != nil {
+				_m.WrappedKey = *value
+			}
+		case datakey.FieldUserID:
+			if value, ok := values[i].(*binid.BinId); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value != nil {
+				_m.UserID = *value
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the DataKey.
+// This includes values selected through modifiers, order, etc.
+func (_m *DataKey) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// QueryUser queries the "user" edge of the DataKey entity.
+func (_m *DataKey) QueryUser() *UserQuery {
+	return NewDataKeyClient(_m.config).QueryUser(_m)
+}
+
+// QueryMfaQrs queries the "mfa_qrs" edge of the DataKey entity.
+func (_m *DataKey) QueryMfaQrs() *MfaQrQuery {
+	return NewDataKeyClient(_m.config).QueryMfaQrs(_m)
+}
+
+// Update returns a builder for updating this DataKey.
+// Note that you need to call DataKey.Unwrap() before calling this method if this DataKey
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *DataKey) Update() *DataKeyUpdateOne {
+	return NewDataKeyClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the DataKey entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *DataKey) Unwrap() *DataKey {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: DataKey is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *DataKey) String() string {
+	var builder strings.Builder
+	builder.WriteString("DataKey(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(_m.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := _m.DeletedAt; v != nil {
+		builder.WriteString("deleted_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("wrapped_key=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("user_id=")
+	builder.WriteString(fmt.Sprintf("%v", _m.UserID))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// DataKeys is a parsable slice of DataKey.
+type DataKeys []*DataKey
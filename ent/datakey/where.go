@@ -0,0 +1,333 @@
+// Code generated by ent, DO NOT EDIT.
+
+package datakey
+
+import (
+	"nidan-kai/binid"
+	"nidan-kai/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLTE(FieldID, id))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// DeletedAt applies equality check predicate on the "deleted_at" field. It's identical to DeletedAtEQ.
+func DeletedAt(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldDeletedAt, v))
+}
+
+// WrappedKey applies equality check predicate on the "wrapped_key" field. It's identical to WrappedKeyEQ.
+func WrappedKey(v []byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldWrappedKey, v))
+}
+
+// UserID applies equality check predicate on the "user_id" field. It's identical to UserIDEQ.
+func UserID(v binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldUserID, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// DeletedAtEQ applies the EQ predicate on the "deleted_at" field.
+func DeletedAtEQ(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldDeletedAt, v))
+}
+
+// DeletedAtNEQ applies the NEQ predicate on the "deleted_at" field.
+func DeletedAtNEQ(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNEQ(FieldDeletedAt, v))
+}
+
+// DeletedAtIn applies the In predicate on the "deleted_at" field.
+func DeletedAtIn(vs ...time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldIn(FieldDeletedAt, vs...))
+}
+
+// DeletedAtNotIn applies the NotIn predicate on the "deleted_at" field.
+func DeletedAtNotIn(vs ...time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNotIn(FieldDeletedAt, vs...))
+}
+
+// DeletedAtGT applies the GT predicate on the "deleted_at" field.
+func DeletedAtGT(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGT(FieldDeletedAt, v))
+}
+
+// DeletedAtGTE applies the GTE predicate on the "deleted_at" field.
+func DeletedAtGTE(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGTE(FieldDeletedAt, v))
+}
+
+// DeletedAtLT applies the LT predicate on the "deleted_at" field.
+func DeletedAtLT(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLT(FieldDeletedAt, v))
+}
+
+// DeletedAtLTE applies the LTE predicate on the "deleted_at" field.
+func DeletedAtLTE(v time.Time) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLTE(FieldDeletedAt, v))
+}
+
+// DeletedAtIsNil applies the IsNil predicate on the "deleted_at" field.
+func DeletedAtIsNil() predicate.DataKey {
+	return predicate.DataKey(sql.FieldIsNull(FieldDeletedAt))
+}
+
+// DeletedAtNotNil applies the NotNil predicate on the "deleted_at" field.
+func DeletedAtNotNil() predicate.DataKey {
+	return predicate.DataKey(sql.FieldNotNull(FieldDeletedAt))
+}
+
+// WrappedKeyEQ applies the EQ predicate on the "wrapped_key" field.
+func WrappedKeyEQ(v []byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldWrappedKey, v))
+}
+
+// WrappedKeyNEQ applies the NEQ predicate on the "wrapped_key" field.
+func WrappedKeyNEQ(v []byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNEQ(FieldWrappedKey, v))
+}
+
+// WrappedKeyIn applies the In predicate on the "wrapped_key" field.
+func WrappedKeyIn(vs ...[]byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldIn(FieldWrappedKey, vs...))
+}
+
+// WrappedKeyNotIn applies the NotIn predicate on the "wrapped_key" field.
+func WrappedKeyNotIn(vs ...[]byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNotIn(FieldWrappedKey, vs...))
+}
+
+// WrappedKeyGT applies the GT predicate on the "wrapped_key" field.
+func WrappedKeyGT(v []byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGT(FieldWrappedKey, v))
+}
+
+// WrappedKeyGTE applies the GTE predicate on the "wrapped_key" field.
+func WrappedKeyGTE(v []byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldGTE(FieldWrappedKey, v))
+}
+
+// WrappedKeyLT applies the LT predicate on the "wrapped_key" field.
+func WrappedKeyLT(v []byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLT(FieldWrappedKey, v))
+}
+
+// WrappedKeyLTE applies the LTE predicate on the "wrapped_key" field.
+func WrappedKeyLTE(v []byte) predicate.DataKey {
+	return predicate.DataKey(sql.FieldLTE(FieldWrappedKey, v))
+}
+
+// UserIDEQ applies the EQ predicate on the "user_id" field.
+func UserIDEQ(v binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldEQ(FieldUserID, v))
+}
+
+// UserIDNEQ applies the NEQ predicate on the "user_id" field.
+func UserIDNEQ(v binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNEQ(FieldUserID, v))
+}
+
+// UserIDIn applies the In predicate on the "user_id" field.
+func UserIDIn(vs ...binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldIn(FieldUserID, vs...))
+}
+
+// UserIDNotIn applies the NotIn predicate on the "user_id" field.
+func UserIDNotIn(vs ...binid.BinId) predicate.DataKey {
+	return predicate.DataKey(sql.FieldNotIn(FieldUserID, vs...))
+}
+
+// HasUser applies the HasEdge predicate on the "user" edge.
+func HasUser() predicate.DataKey {
+	return predicate.DataKey(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.O2O, true, UserTable, UserColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasUserWith applies the HasEdge predicate on the "user" edge with a given conditions (other predicates).
+func HasUserWith(preds ...predicate.User) predicate.DataKey {
+	return predicate.DataKey(func(s *sql.Selector) {
+		step := newUserStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// HasMfaQrs applies the HasEdge predicate on the "mfa_qrs" edge.
+func HasMfaQrs() predicate.DataKey {
+	return predicate.DataKey(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.O2M, false, MfaQrsTable, MfaQrsColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasMfaQrsWith applies the HasEdge predicate on the "mfa_qrs" edge with a given conditions (other predicates).
+func HasMfaQrsWith(preds ...predicate.MfaQr) predicate.DataKey {
+	return predicate.DataKey(func(s *sql.Selector) {
+		step := newMfaQrsStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.DataKey) predicate.DataKey {
+	return predicate.DataKey(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.DataKey) predicate.DataKey {
+	return predicate.DataKey(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.DataKey) predicate.DataKey {
+	return predicate.DataKey(sql.NotPredicates(p))
+}
@@ -0,0 +1,342 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
+	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/user"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// DataKeyCreate is the builder for creating a DataKey entity.
+type DataKeyCreate struct {
+	config
+	mutation *DataKeyMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *DataKeyCreate) SetCreatedAt(v time.Time) *DataKeyCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *DataKeyCreate) SetNillableCreatedAt(v *time.Time) *DataKeyCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_c *DataKeyCreate) SetUpdatedAt(v time.Time) *DataKeyCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_c *DataKeyCreate) SetNillableUpdatedAt(v *time.Time) *DataKeyCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
+	}
+	return _c
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_c *DataKeyCreate) SetDeletedAt(v time.Time) *DataKeyCreate {
+	_c.mutation.SetDeletedAt(v)
+	return _c
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_c *DataKeyCreate) SetNillableDeletedAt(v *time.Time) *DataKeyCreate {
+	if v != nil {
+		_c.SetDeletedAt(*v)
+	}
+	return _c
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (_c *DataKeyCreate) SetWrappedKey(v []byte) *DataKeyCreate {
+	_c.mutation.SetWrappedKey(v)
+	return _c
+}
+
+// SetUserID sets the "user_id" field.
+func (_c *DataKeyCreate) SetUserID(v binid.BinId) *DataKeyCreate {
+	_c.mutation.SetUserID(v)
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *DataKeyCreate) SetID(v binid.BinId) *DataKeyCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// SetUser sets the "user" edge to the User entity.
+func (_c *DataKeyCreate) SetUser(v *User) *DataKeyCreate {
+	return _c.SetUserID(v.ID)
+}
+
+// AddMfaQrIDs adds the "mfa_qrs" edge to the MfaQr entity by IDs.
+func (_c *DataKeyCreate) AddMfaQrIDs(ids ...binid.BinId) *DataKeyCreate {
+	_c.mutation.AddMfaQrIDs(ids...)
+	return _c
+}
+
+// AddMfaQrs adds the "mfa_qrs" edges to the MfaQr entity.
+func (_c *DataKeyCreate) AddMfaQrs(v ...*MfaQr) *DataKeyCreate {
+	ids := make([]binid.BinId, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddMfaQrIDs(ids...)
+}
+
+// Mutation returns the DataKeyMutation object of the builder.
+func (_c *DataKeyCreate) Mutation() *DataKeyMutation {
+	return _c.mutation
+}
+
+// Save creates the DataKey in the database.
+func (_c *DataKeyCreate) Save(ctx context.Context) (*DataKey, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *DataKeyCreate) SaveX(ctx context.Context) *DataKey {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *DataKeyCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *DataKeyCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *DataKeyCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := datakey.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		v := datakey.DefaultUpdatedAt()
+		_c.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *DataKeyCreate) check() error {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "DataKey.created_at"`)}
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "DataKey.updated_at"`)}
+	}
+	if _, ok := _c.mutation.WrappedKey(); !ok {
+		return &ValidationError{Name: "wrapped_key", err: errors.New(`ent: missing required field "DataKey.wrapped_key"`)}
+	}
+	if v, ok := _c.mutation.WrappedKey(); ok {
+		if err := datakey.WrappedKeyValidator(v); err != nil {
+			return &ValidationError{Name: "wrapped_key", err: fmt.Errorf(`ent: validator failed for field "DataKey.wrapped_key": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "DataKey.user_id"`)}
+	}
+	if len(_c.mutation.UserIDs()) == 0 {
+		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "DataKey.user"`)}
+	}
+	return nil
+}
+
+func (_c *DataKeyCreate) sqlSave(ctx context.Context) (*DataKey, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(*binid.BinId); ok {
+			_node.ID = *id
+		} else if err := _node.ID.Scan(_spec.ID.Value); err != nil {
+			return nil, err
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *DataKeyCreate) createSpec() (*DataKey, *sqlgraph.CreateSpec) {
+	var (
+		_node = &DataKey{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(datakey.Table, sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = &id
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(datakey.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.UpdatedAt(); ok {
+		_spec.SetField(datakey.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := _c.mutation.DeletedAt(); ok {
+		_spec.SetField(datakey.FieldDeletedAt, field.TypeTime, value)
+		_node.DeletedAt = &value
+	}
+	if value, ok := _c.mutation.WrappedKey(); ok {
+		_spec.SetField(datakey.FieldWrappedKey, field.TypeBytes, value)
+		_node.WrappedKey = value
+	}
+	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: true,
+			Table:   datakey.UserTable,
+			Columns: []string{datakey.UserColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(user.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.UserID = nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.MfaQrsIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   datakey.MfaQrsTable,
+			Columns: []string{datakey.MfaQrsColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(mfaqr.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// DataKeyCreateBulk is the builder for creating many DataKey entities in bulk.
+type DataKeyCreateBulk struct {
+	config
+	err      error
+	builders []*DataKeyCreate
+}
+
+// Save creates the DataKey entities in the database.
+func (_c *DataKeyCreateBulk) Save(ctx context.Context) ([]*DataKey, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*DataKey, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*DataKeyMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *DataKeyCreateBulk) SaveX(ctx context.Context) []*DataKey {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *DataKeyCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *DataKeyCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,314 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nidan-kai/ent/datakey"
+	"nidan-kai/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// DataKeyUpdate is the builder for updating DataKey entities.
+type DataKeyUpdate struct {
+	config
+	hooks    []Hook
+	mutation *DataKeyMutation
+}
+
+// Where appends a list predicates to the DataKeyUpdate builder.
+func (_u *DataKeyUpdate) Where(ps ...predicate.DataKey) *DataKeyUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *DataKeyUpdate) SetUpdatedAt(v time.Time) *DataKeyUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_u *DataKeyUpdate) SetDeletedAt(v time.Time) *DataKeyUpdate {
+	_u.mutation.SetDeletedAt(v)
+	return _u
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_u *DataKeyUpdate) SetNillableDeletedAt(v *time.Time) *DataKeyUpdate {
+	if v != nil {
+		_u.SetDeletedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (_u *DataKeyUpdate) ClearDeletedAt() *DataKeyUpdate {
+	_u.mutation.ClearDeletedAt()
+	return _u
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (_u *DataKeyUpdate) SetWrappedKey(v []byte) *DataKeyUpdate {
+	_u.mutation.SetWrappedKey(v)
+	return _u
+}
+
+// Mutation returns the DataKeyMutation object of the builder.
+func (_u *DataKeyUpdate) Mutation() *DataKeyMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *DataKeyUpdate) Save(ctx context.Context) (int, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *DataKeyUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *DataKeyUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *DataKeyUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *DataKeyUpdate) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := datakey.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *DataKeyUpdate) check() error {
+	if v, ok := _u.mutation.WrappedKey(); ok {
+		if err := datakey.WrappedKeyValidator(v); err != nil {
+			return &ValidationError{Name: "wrapped_key", err: fmt.Errorf(`ent: validator failed for field "DataKey.wrapped_key": %w`, err)}
+		}
+	}
+	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "DataKey.user"`)
+	}
+	return nil
+}
+
+func (_u *DataKeyUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(datakey.Table, datakey.Columns, sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(datakey.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.DeletedAt(); ok {
+		_spec.SetField(datakey.FieldDeletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeletedAtCleared() {
+		_spec.ClearField(datakey.FieldDeletedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.WrappedKey(); ok {
+		_spec.SetField(datakey.FieldWrappedKey, field.TypeBytes, value)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{datakey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// DataKeyUpdateOne is the builder for updating a single DataKey entity.
+type DataKeyUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *DataKeyMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *DataKeyUpdateOne) SetUpdatedAt(v time.Time) *DataKeyUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_u *DataKeyUpdateOne) SetDeletedAt(v time.Time) *DataKeyUpdateOne {
+	_u.mutation.SetDeletedAt(v)
+	return _u
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_u *DataKeyUpdateOne) SetNillableDeletedAt(v *time.Time) *DataKeyUpdateOne {
+	if v != nil {
+		_u.SetDeletedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (_u *DataKeyUpdateOne) ClearDeletedAt() *DataKeyUpdateOne {
+	_u.mutation.ClearDeletedAt()
+	return _u
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (_u *DataKeyUpdateOne) SetWrappedKey(v []byte) *DataKeyUpdateOne {
+	_u.mutation.SetWrappedKey(v)
+	return _u
+}
+
+// Mutation returns the DataKeyMutation object of the builder.
+func (_u *DataKeyUpdateOne) Mutation() *DataKeyMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the DataKeyUpdate builder.
+func (_u *DataKeyUpdateOne) Where(ps ...predicate.DataKey) *DataKeyUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *DataKeyUpdateOne) Select(field string, fields ...string) *DataKeyUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated DataKey entity.
+func (_u *DataKeyUpdateOne) Save(ctx context.Context) (*DataKey, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *DataKeyUpdateOne) SaveX(ctx context.Context) *DataKey {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *DataKeyUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *DataKeyUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *DataKeyUpdateOne) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := datakey.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *DataKeyUpdateOne) check() error {
+	if v, ok := _u.mutation.WrappedKey(); ok {
+		if err := datakey.WrappedKeyValidator(v); err != nil {
+			return &ValidationError{Name: "wrapped_key", err: fmt.Errorf(`ent: validator failed for field "DataKey.wrapped_key": %w`, err)}
+		}
+	}
+	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "DataKey.user"`)
+	}
+	return nil
+}
+
+func (_u *DataKeyUpdateOne) sqlSave(ctx context.Context) (_node *DataKey, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(datakey.Table, datakey.Columns, sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "DataKey.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, datakey.FieldID)
+		for _, f := range fields {
+			if !datakey.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != datakey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(datakey.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.DeletedAt(); ok {
+		_spec.SetField(datakey.FieldDeletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeletedAtCleared() {
+		_spec.ClearField(datakey.FieldDeletedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.WrappedKey(); ok {
+		_spec.SetField(datakey.FieldWrappedKey, field.TypeBytes, value)
+	}
+	_node = &DataKey{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{datakey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
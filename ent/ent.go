@@ -6,7 +6,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"nidan-kai/ent/datakey"
+	"nidan-kai/ent/jobcheckpoint"
 	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/ratelimitbucket"
+	"nidan-kai/ent/recoverycode"
 	"nidan-kai/ent/user"
 	"reflect"
 	"sync"
@@ -74,8 +78,12 @@ var (
 func checkColumn(t, c string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			mfaqr.Table: mfaqr.ValidColumn,
-			user.Table:  user.ValidColumn,
+			datakey.Table:         datakey.ValidColumn,
+			jobcheckpoint.Table:   jobcheckpoint.ValidColumn,
+			mfaqr.Table:           mfaqr.ValidColumn,
+			ratelimitbucket.Table: ratelimitbucket.ValidColumn,
+			recoverycode.Table:    recoverycode.ValidColumn,
+			user.Table:            user.ValidColumn,
 		})
 	})
 	return columnCheck(t, c)
@@ -0,0 +1,202 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"encoding/json"
+	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/jobcheckpoint"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// JobCheckpoint is the model entity for the JobCheckpoint schema.
+type JobCheckpoint struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID binid.BinId `json:"id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// UpdatedAt holds the value of the "updated_at" field.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	// DeletedAt holds the value of the "deleted_at" field.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Name holds the value of the "name" field.
+	Name string `json:"name,omitempty"`
+	// Cursor holds the value of the "cursor" field.
+	Cursor *binid.BinId `json:"cursor,omitempty"`
+	// Processed holds the value of the "processed" field.
+	Processed uint64 `json:"processed,omitempty"`
+	// FailedIds holds the value of the "failed_ids" field.
+	FailedIds []string `json:"failed_ids,omitempty"`
+	// CompletedAt holds the value of the "completed_at" field.
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*JobCheckpoint) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case jobcheckpoint.FieldCursor:
+			values[i] = &sql.NullScanner{S: new(binid.BinId)}
+		case jobcheckpoint.FieldFailedIds:
+			values[i] = new([]byte)
+		case jobcheckpoint.FieldID:
+			values[i] = new(binid.BinId)
+		case jobcheckpoint.FieldProcessed:
+			values[i] = new(sql.NullInt64)
+		case jobcheckpoint.FieldName:
+			values[i] = new(sql.NullString)
+		case jobcheckpoint.FieldCreatedAt, jobcheckpoint.FieldUpdatedAt, jobcheckpoint.FieldDeletedAt, jobcheckpoint.FieldCompletedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the JobCheckpoint fields.
+func (_m *JobCheckpoint) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case jobcheckpoint.FieldID:
+			if value, ok := values[i].(*binid.BinId); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value != nil {
+				_m.ID = *value
+			}
+		case jobcheckpoint.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				_m.CreatedAt = value.Time
+			}
+		case jobcheckpoint.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				_m.UpdatedAt = value.Time
+			}
+		case jobcheckpoint.FieldDeletedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field deleted_at", values[i])
+			} else if value.Valid {
+				_m.DeletedAt = new(time.Time)
+				*_m.DeletedAt = value.Time
+			}
+		case jobcheckpoint.FieldName:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field name", values[i])
+			} else if value.Valid {
+				_m.Name = value.String
+			}
+		case jobcheckpoint.FieldCursor:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field cursor", values[i])
+			} else if value.Valid {
+				_m.Cursor = new(binid.BinId)
+				*_m.Cursor = *value.S.(*binid.BinId)
+			}
+		case jobcheckpoint.FieldProcessed:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field processed", values[i])
+			} else if value.Valid {
+				_m.Processed = uint64(value.Int64)
+			}
+		case jobcheckpoint.FieldFailedIds:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field failed_ids", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.FailedIds); err != nil {
+					return fmt.Errorf("unmarshal field failed_ids: %w", err)
+				}
+			}
+		case jobcheckpoint.FieldCompletedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field completed_at", values[i])
+			} else if value.Valid {
+				_m.CompletedAt = new(time.Time)
+				*_m.CompletedAt = value.Time
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the JobCheckpoint.
+// This includes values selected through modifiers, order, etc.
+func (_m *JobCheckpoint) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this JobCheckpoint.
+// Note that you need to call JobCheckpoint.Unwrap() before calling this method if this JobCheckpoint
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *JobCheckpoint) Update() *JobCheckpointUpdateOne {
+	return NewJobCheckpointClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the JobCheckpoint entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *JobCheckpoint) Unwrap() *JobCheckpoint {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: JobCheckpoint is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *JobCheckpoint) String() string {
+	var builder strings.Builder
+	builder.WriteString("JobCheckpoint(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("created_at=")
+	builder.WriteString(_m.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(_m.UpdatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	if v := _m.DeletedAt; v != nil {
+		builder.WriteString("deleted_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("name=")
+	builder.WriteString(_m.Name)
+	builder.WriteString(", ")
+	if v := _m.Cursor; v != nil {
+		builder.WriteString("cursor=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("processed=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Processed))
+	builder.WriteString(", ")
+	builder.WriteString("failed_ids=")
+	builder.WriteString(fmt.Sprintf("%v", _m.FailedIds))
+	builder.WriteString(", ")
+	if v := _m.CompletedAt; v != nil {
+		builder.WriteString("completed_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// JobCheckpoints is a parsable slice of JobCheckpoint.
+type JobCheckpoints []*JobCheckpoint
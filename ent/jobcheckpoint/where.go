@@ -0,0 +1,451 @@
+// Code generated by ent, DO NOT EDIT.
+
+package jobcheckpoint
+
+import (
+	"nidan-kai/binid"
+	"nidan-kai/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldID, id))
+}
+
+// CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
+func CreatedAt(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// UpdatedAt applies equality check predicate on the "updated_at" field. It's identical to UpdatedAtEQ.
+func UpdatedAt(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// DeletedAt applies equality check predicate on the "deleted_at" field. It's identical to DeletedAtEQ.
+func DeletedAt(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldDeletedAt, v))
+}
+
+// Name applies equality check predicate on the "name" field. It's identical to NameEQ.
+func Name(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldName, v))
+}
+
+// Cursor applies equality check predicate on the "cursor" field. It's identical to CursorEQ.
+func Cursor(v binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldCursor, v))
+}
+
+// Processed applies equality check predicate on the "processed" field. It's identical to ProcessedEQ.
+func Processed(v uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldProcessed, v))
+}
+
+// CompletedAt applies equality check predicate on the "completed_at" field. It's identical to CompletedAtEQ.
+func CompletedAt(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldCompletedAt, v))
+}
+
+// CreatedAtEQ applies the EQ predicate on the "created_at" field.
+func CreatedAtEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtNEQ applies the NEQ predicate on the "created_at" field.
+func CreatedAtNEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldCreatedAt, v))
+}
+
+// CreatedAtIn applies the In predicate on the "created_at" field.
+func CreatedAtIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtNotIn applies the NotIn predicate on the "created_at" field.
+func CreatedAtNotIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldCreatedAt, vs...))
+}
+
+// CreatedAtGT applies the GT predicate on the "created_at" field.
+func CreatedAtGT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldCreatedAt, v))
+}
+
+// CreatedAtGTE applies the GTE predicate on the "created_at" field.
+func CreatedAtGTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldCreatedAt, v))
+}
+
+// CreatedAtLT applies the LT predicate on the "created_at" field.
+func CreatedAtLT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldCreatedAt, v))
+}
+
+// CreatedAtLTE applies the LTE predicate on the "created_at" field.
+func CreatedAtLTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldCreatedAt, v))
+}
+
+// UpdatedAtEQ applies the EQ predicate on the "updated_at" field.
+func UpdatedAtEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtNEQ applies the NEQ predicate on the "updated_at" field.
+func UpdatedAtNEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldUpdatedAt, v))
+}
+
+// UpdatedAtIn applies the In predicate on the "updated_at" field.
+func UpdatedAtIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtNotIn applies the NotIn predicate on the "updated_at" field.
+func UpdatedAtNotIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldUpdatedAt, vs...))
+}
+
+// UpdatedAtGT applies the GT predicate on the "updated_at" field.
+func UpdatedAtGT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtGTE applies the GTE predicate on the "updated_at" field.
+func UpdatedAtGTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLT applies the LT predicate on the "updated_at" field.
+func UpdatedAtLT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldUpdatedAt, v))
+}
+
+// UpdatedAtLTE applies the LTE predicate on the "updated_at" field.
+func UpdatedAtLTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldUpdatedAt, v))
+}
+
+// DeletedAtEQ applies the EQ predicate on the "deleted_at" field.
+func DeletedAtEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldDeletedAt, v))
+}
+
+// DeletedAtNEQ applies the NEQ predicate on the "deleted_at" field.
+func DeletedAtNEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldDeletedAt, v))
+}
+
+// DeletedAtIn applies the In predicate on the "deleted_at" field.
+func DeletedAtIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldDeletedAt, vs...))
+}
+
+// DeletedAtNotIn applies the NotIn predicate on the "deleted_at" field.
+func DeletedAtNotIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldDeletedAt, vs...))
+}
+
+// DeletedAtGT applies the GT predicate on the "deleted_at" field.
+func DeletedAtGT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldDeletedAt, v))
+}
+
+// DeletedAtGTE applies the GTE predicate on the "deleted_at" field.
+func DeletedAtGTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldDeletedAt, v))
+}
+
+// DeletedAtLT applies the LT predicate on the "deleted_at" field.
+func DeletedAtLT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldDeletedAt, v))
+}
+
+// DeletedAtLTE applies the LTE predicate on the "deleted_at" field.
+func DeletedAtLTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldDeletedAt, v))
+}
+
+// DeletedAtIsNil applies the IsNil predicate on the "deleted_at" field.
+func DeletedAtIsNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIsNull(FieldDeletedAt))
+}
+
+// DeletedAtNotNil applies the NotNil predicate on the "deleted_at" field.
+func DeletedAtNotNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotNull(FieldDeletedAt))
+}
+
+// NameEQ applies the EQ predicate on the "name" field.
+func NameEQ(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldName, v))
+}
+
+// NameNEQ applies the NEQ predicate on the "name" field.
+func NameNEQ(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldName, v))
+}
+
+// NameIn applies the In predicate on the "name" field.
+func NameIn(vs ...string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldName, vs...))
+}
+
+// NameNotIn applies the NotIn predicate on the "name" field.
+func NameNotIn(vs ...string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldName, vs...))
+}
+
+// NameGT applies the GT predicate on the "name" field.
+func NameGT(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldName, v))
+}
+
+// NameGTE applies the GTE predicate on the "name" field.
+func NameGTE(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldName, v))
+}
+
+// NameLT applies the LT predicate on the "name" field.
+func NameLT(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldName, v))
+}
+
+// NameLTE applies the LTE predicate on the "name" field.
+func NameLTE(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldName, v))
+}
+
+// NameContains applies the Contains predicate on the "name" field.
+func NameContains(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldContains(FieldName, v))
+}
+
+// NameHasPrefix applies the HasPrefix predicate on the "name" field.
+func NameHasPrefix(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldHasPrefix(FieldName, v))
+}
+
+// NameHasSuffix applies the HasSuffix predicate on the "name" field.
+func NameHasSuffix(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldHasSuffix(FieldName, v))
+}
+
+// NameEqualFold applies the EqualFold predicate on the "name" field.
+func NameEqualFold(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEqualFold(FieldName, v))
+}
+
+// NameContainsFold applies the ContainsFold predicate on the "name" field.
+func NameContainsFold(v string) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldContainsFold(FieldName, v))
+}
+
+// CursorEQ applies the EQ predicate on the "cursor" field.
+func CursorEQ(v binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldCursor, v))
+}
+
+// CursorNEQ applies the NEQ predicate on the "cursor" field.
+func CursorNEQ(v binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldCursor, v))
+}
+
+// CursorIn applies the In predicate on the "cursor" field.
+func CursorIn(vs ...binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldCursor, vs...))
+}
+
+// CursorNotIn applies the NotIn predicate on the "cursor" field.
+func CursorNotIn(vs ...binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldCursor, vs...))
+}
+
+// CursorGT applies the GT predicate on the "cursor" field.
+func CursorGT(v binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldCursor, v))
+}
+
+// CursorGTE applies the GTE predicate on the "cursor" field.
+func CursorGTE(v binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldCursor, v))
+}
+
+// CursorLT applies the LT predicate on the "cursor" field.
+func CursorLT(v binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldCursor, v))
+}
+
+// CursorLTE applies the LTE predicate on the "cursor" field.
+func CursorLTE(v binid.BinId) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldCursor, v))
+}
+
+// CursorIsNil applies the IsNil predicate on the "cursor" field.
+func CursorIsNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIsNull(FieldCursor))
+}
+
+// CursorNotNil applies the NotNil predicate on the "cursor" field.
+func CursorNotNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotNull(FieldCursor))
+}
+
+// ProcessedEQ applies the EQ predicate on the "processed" field.
+func ProcessedEQ(v uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldProcessed, v))
+}
+
+// ProcessedNEQ applies the NEQ predicate on the "processed" field.
+func ProcessedNEQ(v uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldProcessed, v))
+}
+
+// ProcessedIn applies the In predicate on the "processed" field.
+func ProcessedIn(vs ...uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldProcessed, vs...))
+}
+
+// ProcessedNotIn applies the NotIn predicate on the "processed" field.
+func ProcessedNotIn(vs ...uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldProcessed, vs...))
+}
+
+// ProcessedGT applies the GT predicate on the "processed" field.
+func ProcessedGT(v uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldProcessed, v))
+}
+
+// ProcessedGTE applies the GTE predicate on the "processed" field.
+func ProcessedGTE(v uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldProcessed, v))
+}
+
+// ProcessedLT applies the LT predicate on the "processed" field.
+func ProcessedLT(v uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldProcessed, v))
+}
+
+// ProcessedLTE applies the LTE predicate on the "processed" field.
+func ProcessedLTE(v uint64) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldProcessed, v))
+}
+
+// FailedIdsIsNil applies the IsNil predicate on the "failed_ids" field.
+func FailedIdsIsNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIsNull(FieldFailedIds))
+}
+
+// FailedIdsNotNil applies the NotNil predicate on the "failed_ids" field.
+func FailedIdsNotNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotNull(FieldFailedIds))
+}
+
+// CompletedAtEQ applies the EQ predicate on the "completed_at" field.
+func CompletedAtEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldEQ(FieldCompletedAt, v))
+}
+
+// CompletedAtNEQ applies the NEQ predicate on the "completed_at" field.
+func CompletedAtNEQ(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNEQ(FieldCompletedAt, v))
+}
+
+// CompletedAtIn applies the In predicate on the "completed_at" field.
+func CompletedAtIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIn(FieldCompletedAt, vs...))
+}
+
+// CompletedAtNotIn applies the NotIn predicate on the "completed_at" field.
+func CompletedAtNotIn(vs ...time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotIn(FieldCompletedAt, vs...))
+}
+
+// CompletedAtGT applies the GT predicate on the "completed_at" field.
+func CompletedAtGT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGT(FieldCompletedAt, v))
+}
+
+// CompletedAtGTE applies the GTE predicate on the "completed_at" field.
+func CompletedAtGTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldGTE(FieldCompletedAt, v))
+}
+
+// CompletedAtLT applies the LT predicate on the "completed_at" field.
+func CompletedAtLT(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLT(FieldCompletedAt, v))
+}
+
+// CompletedAtLTE applies the LTE predicate on the "completed_at" field.
+func CompletedAtLTE(v time.Time) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldLTE(FieldCompletedAt, v))
+}
+
+// CompletedAtIsNil applies the IsNil predicate on the "completed_at" field.
+func CompletedAtIsNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldIsNull(FieldCompletedAt))
+}
+
+// CompletedAtNotNil applies the NotNil predicate on the "completed_at" field.
+func CompletedAtNotNil() predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.FieldNotNull(FieldCompletedAt))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.JobCheckpoint) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.JobCheckpoint) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.JobCheckpoint) predicate.JobCheckpoint {
+	return predicate.JobCheckpoint(sql.NotPredicates(p))
+}
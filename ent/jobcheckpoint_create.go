@@ -0,0 +1,346 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/jobcheckpoint"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// JobCheckpointCreate is the builder for creating a JobCheckpoint entity.
+type JobCheckpointCreate struct {
+	config
+	mutation *JobCheckpointMutation
+	hooks    []Hook
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (_c *JobCheckpointCreate) SetCreatedAt(v time.Time) *JobCheckpointCreate {
+	_c.mutation.SetCreatedAt(v)
+	return _c
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (_c *JobCheckpointCreate) SetNillableCreatedAt(v *time.Time) *JobCheckpointCreate {
+	if v != nil {
+		_c.SetCreatedAt(*v)
+	}
+	return _c
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_c *JobCheckpointCreate) SetUpdatedAt(v time.Time) *JobCheckpointCreate {
+	_c.mutation.SetUpdatedAt(v)
+	return _c
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (_c *JobCheckpointCreate) SetNillableUpdatedAt(v *time.Time) *JobCheckpointCreate {
+	if v != nil {
+		_c.SetUpdatedAt(*v)
+	}
+	return _c
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_c *JobCheckpointCreate) SetDeletedAt(v time.Time) *JobCheckpointCreate {
+	_c.mutation.SetDeletedAt(v)
+	return _c
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_c *JobCheckpointCreate) SetNillableDeletedAt(v *time.Time) *JobCheckpointCreate {
+	if v != nil {
+		_c.SetDeletedAt(*v)
+	}
+	return _c
+}
+
+// SetName sets the "name" field.
+func (_c *JobCheckpointCreate) SetName(v string) *JobCheckpointCreate {
+	_c.mutation.SetName(v)
+	return _c
+}
+
+// SetCursor sets the "cursor" field.
+func (_c *JobCheckpointCreate) SetCursor(v binid.BinId) *JobCheckpointCreate {
+	_c.mutation.SetCursor(v)
+	return _c
+}
+
+// SetNillableCursor sets the "cursor" field if the given value is not nil.
+func (_c *JobCheckpointCreate) SetNillableCursor(v *binid.BinId) *JobCheckpointCreate {
+	if v != nil {
+		_c.SetCursor(*v)
+	}
+	return _c
+}
+
+// SetProcessed sets the "processed" field.
+func (_c *JobCheckpointCreate) SetProcessed(v uint64) *JobCheckpointCreate {
+	_c.mutation.SetProcessed(v)
+	return _c
+}
+
+// SetNillableProcessed sets the "processed" field if the given value is not nil.
+func (_c *JobCheckpointCreate) SetNillableProcessed(v *uint64) *JobCheckpointCreate {
+	if v != nil {
+		_c.SetProcessed(*v)
+	}
+	return _c
+}
+
+// SetFailedIds sets the "failed_ids" field.
+func (_c *JobCheckpointCreate) SetFailedIds(v []string) *JobCheckpointCreate {
+	_c.mutation.SetFailedIds(v)
+	return _c
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (_c *JobCheckpointCreate) SetCompletedAt(v time.Time) *JobCheckpointCreate {
+	_c.mutation.SetCompletedAt(v)
+	return _c
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (_c *JobCheckpointCreate) SetNillableCompletedAt(v *time.Time) *JobCheckpointCreate {
+	if v != nil {
+		_c.SetCompletedAt(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *JobCheckpointCreate) SetID(v binid.BinId) *JobCheckpointCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// Mutation returns the JobCheckpointMutation object of the builder.
+func (_c *JobCheckpointCreate) Mutation() *JobCheckpointMutation {
+	return _c.mutation
+}
+
+// Save creates the JobCheckpoint in the database.
+func (_c *JobCheckpointCreate) Save(ctx context.Context) (*JobCheckpoint, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *JobCheckpointCreate) SaveX(ctx context.Context) *JobCheckpoint {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *JobCheckpointCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *JobCheckpointCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *JobCheckpointCreate) defaults() {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		v := jobcheckpoint.DefaultCreatedAt()
+		_c.mutation.SetCreatedAt(v)
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		v := jobcheckpoint.DefaultUpdatedAt()
+		_c.mutation.SetUpdatedAt(v)
+	}
+	if _, ok := _c.mutation.Processed(); !ok {
+		v := jobcheckpoint.DefaultProcessed
+		_c.mutation.SetProcessed(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *JobCheckpointCreate) check() error {
+	if _, ok := _c.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`ent: missing required field "JobCheckpoint.created_at"`)}
+	}
+	if _, ok := _c.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "JobCheckpoint.updated_at"`)}
+	}
+	if _, ok := _c.mutation.Name(); !ok {
+		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "JobCheckpoint.name"`)}
+	}
+	if v, ok := _c.mutation.Name(); ok {
+		if err := jobcheckpoint.NameValidator(v); err != nil {
+			return &ValidationError{Name: "name", err: fmt.Errorf(`ent: validator failed for field "JobCheckpoint.name": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Processed(); !ok {
+		return &ValidationError{Name: "processed", err: errors.New(`ent: missing required field "JobCheckpoint.processed"`)}
+	}
+	return nil
+}
+
+func (_c *JobCheckpointCreate) sqlSave(ctx context.Context) (*JobCheckpoint, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(*binid.BinId); ok {
+			_node.ID = *id
+		} else if err := _node.ID.Scan(_spec.ID.Value); err != nil {
+			return nil, err
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *JobCheckpointCreate) createSpec() (*JobCheckpoint, *sqlgraph.CreateSpec) {
+	var (
+		_node = &JobCheckpoint{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(jobcheckpoint.Table, sqlgraph.NewFieldSpec(jobcheckpoint.FieldID, field.TypeUUID))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = &id
+	}
+	if value, ok := _c.mutation.CreatedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := _c.mutation.UpdatedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	if value, ok := _c.mutation.DeletedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldDeletedAt, field.TypeTime, value)
+		_node.DeletedAt = &value
+	}
+	if value, ok := _c.mutation.Name(); ok {
+		_spec.SetField(jobcheckpoint.FieldName, field.TypeString, value)
+		_node.Name = value
+	}
+	if value, ok := _c.mutation.Cursor(); ok {
+		_spec.SetField(jobcheckpoint.FieldCursor, field.TypeUUID, value)
+		_node.Cursor = &value
+	}
+	if value, ok := _c.mutation.Processed(); ok {
+		_spec.SetField(jobcheckpoint.FieldProcessed, field.TypeUint64, value)
+		_node.Processed = value
+	}
+	if value, ok := _c.mutation.FailedIds(); ok {
+		_spec.SetField(jobcheckpoint.FieldFailedIds, field.TypeJSON, value)
+		_node.FailedIds = value
+	}
+	if value, ok := _c.mutation.CompletedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldCompletedAt, field.TypeTime, value)
+		_node.CompletedAt = &value
+	}
+	return _node, _spec
+}
+
+// JobCheckpointCreateBulk is the builder for creating many JobCheckpoint entities in bulk.
+type JobCheckpointCreateBulk struct {
+	config
+	err      error
+	builders []*JobCheckpointCreate
+}
+
+// Save creates the JobCheckpoint entities in the database.
+func (_c *JobCheckpointCreateBulk) Save(ctx context.Context) ([]*JobCheckpoint, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*JobCheckpoint, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*JobCheckpointMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *JobCheckpointCreateBulk) SaveX(ctx context.Context) []*JobCheckpoint {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *JobCheckpointCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *JobCheckpointCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
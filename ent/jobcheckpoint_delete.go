@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"nidan-kai/ent/jobcheckpoint"
+	"nidan-kai/ent/predicate"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// JobCheckpointDelete is the builder for deleting a JobCheckpoint entity.
+type JobCheckpointDelete struct {
+	config
+	hooks    []Hook
+	mutation *JobCheckpointMutation
+}
+
+// Where appends a list predicates to the JobCheckpointDelete builder.
+func (_d *JobCheckpointDelete) Where(ps ...predicate.JobCheckpoint) *JobCheckpointDelete {
+	_d.mutation.Where(ps...)
+	return _d
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (_d *JobCheckpointDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, _d.sqlExec, _d.mutation, _d.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_d *JobCheckpointDelete) ExecX(ctx context.Context) int {
+	n, err := _d.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (_d *JobCheckpointDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(jobcheckpoint.Table, sqlgraph.NewFieldSpec(jobcheckpoint.FieldID, field.TypeUUID))
+	if ps := _d.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, _d.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	_d.mutation.done = true
+	return affected, err
+}
+
+// JobCheckpointDeleteOne is the builder for deleting a single JobCheckpoint entity.
+type JobCheckpointDeleteOne struct {
+	_d *JobCheckpointDelete
+}
+
+// Where appends a list predicates to the JobCheckpointDelete builder.
+func (_d *JobCheckpointDeleteOne) Where(ps ...predicate.JobCheckpoint) *JobCheckpointDeleteOne {
+	_d._d.mutation.Where(ps...)
+	return _d
+}
+
+// Exec executes the deletion query.
+func (_d *JobCheckpointDeleteOne) Exec(ctx context.Context) error {
+	n, err := _d._d.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{jobcheckpoint.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_d *JobCheckpointDeleteOne) ExecX(ctx context.Context) {
+	if err := _d.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,482 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/jobcheckpoint"
+	"nidan-kai/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+)
+
+// JobCheckpointUpdate is the builder for updating JobCheckpoint entities.
+type JobCheckpointUpdate struct {
+	config
+	hooks    []Hook
+	mutation *JobCheckpointMutation
+}
+
+// Where appends a list predicates to the JobCheckpointUpdate builder.
+func (_u *JobCheckpointUpdate) Where(ps ...predicate.JobCheckpoint) *JobCheckpointUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *JobCheckpointUpdate) SetUpdatedAt(v time.Time) *JobCheckpointUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_u *JobCheckpointUpdate) SetDeletedAt(v time.Time) *JobCheckpointUpdate {
+	_u.mutation.SetDeletedAt(v)
+	return _u
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_u *JobCheckpointUpdate) SetNillableDeletedAt(v *time.Time) *JobCheckpointUpdate {
+	if v != nil {
+		_u.SetDeletedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (_u *JobCheckpointUpdate) ClearDeletedAt() *JobCheckpointUpdate {
+	_u.mutation.ClearDeletedAt()
+	return _u
+}
+
+// SetCursor sets the "cursor" field.
+func (_u *JobCheckpointUpdate) SetCursor(v binid.BinId) *JobCheckpointUpdate {
+	_u.mutation.SetCursor(v)
+	return _u
+}
+
+// SetNillableCursor sets the "cursor" field if the given value is not nil.
+func (_u *JobCheckpointUpdate) SetNillableCursor(v *binid.BinId) *JobCheckpointUpdate {
+	if v != nil {
+		_u.SetCursor(*v)
+	}
+	return _u
+}
+
+// ClearCursor clears the value of the "cursor" field.
+func (_u *JobCheckpointUpdate) ClearCursor() *JobCheckpointUpdate {
+	_u.mutation.ClearCursor()
+	return _u
+}
+
+// SetProcessed sets the "processed" field.
+func (_u *JobCheckpointUpdate) SetProcessed(v uint64) *JobCheckpointUpdate {
+	_u.mutation.ResetProcessed()
+	_u.mutation.SetProcessed(v)
+	return _u
+}
+
+// SetNillableProcessed sets the "processed" field if the given value is not nil.
+func (_u *JobCheckpointUpdate) SetNillableProcessed(v *uint64) *JobCheckpointUpdate {
+	if v != nil {
+		_u.SetProcessed(*v)
+	}
+	return _u
+}
+
+// AddProcessed adds value to the "processed" field.
+func (_u *JobCheckpointUpdate) AddProcessed(v int64) *JobCheckpointUpdate {
+	_u.mutation.AddProcessed(v)
+	return _u
+}
+
+// SetFailedIds sets the "failed_ids" field.
+func (_u *JobCheckpointUpdate) SetFailedIds(v []string) *JobCheckpointUpdate {
+	_u.mutation.SetFailedIds(v)
+	return _u
+}
+
+// AppendFailedIds appends value to the "failed_ids" field.
+func (_u *JobCheckpointUpdate) AppendFailedIds(v []string) *JobCheckpointUpdate {
+	_u.mutation.AppendFailedIds(v)
+	return _u
+}
+
+// ClearFailedIds clears the value of the "failed_ids" field.
+func (_u *JobCheckpointUpdate) ClearFailedIds() *JobCheckpointUpdate {
+	_u.mutation.ClearFailedIds()
+	return _u
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (_u *JobCheckpointUpdate) SetCompletedAt(v time.Time) *JobCheckpointUpdate {
+	_u.mutation.SetCompletedAt(v)
+	return _u
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (_u *JobCheckpointUpdate) SetNillableCompletedAt(v *time.Time) *JobCheckpointUpdate {
+	if v != nil {
+		_u.SetCompletedAt(*v)
+	}
+	return _u
+}
+
+// ClearCompletedAt clears the value of the "completed_at" field.
+func (_u *JobCheckpointUpdate) ClearCompletedAt() *JobCheckpointUpdate {
+	_u.mutation.ClearCompletedAt()
+	return _u
+}
+
+// Mutation returns the JobCheckpointMutation object of the builder.
+func (_u *JobCheckpointUpdate) Mutation() *JobCheckpointMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *JobCheckpointUpdate) Save(ctx context.Context) (int, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *JobCheckpointUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *JobCheckpointUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *JobCheckpointUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *JobCheckpointUpdate) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := jobcheckpoint.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+func (_u *JobCheckpointUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(jobcheckpoint.Table, jobcheckpoint.Columns, sqlgraph.NewFieldSpec(jobcheckpoint.FieldID, field.TypeUUID))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.DeletedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldDeletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeletedAtCleared() {
+		_spec.ClearField(jobcheckpoint.FieldDeletedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Cursor(); ok {
+		_spec.SetField(jobcheckpoint.FieldCursor, field.TypeUUID, value)
+	}
+	if _u.mutation.CursorCleared() {
+		_spec.ClearField(jobcheckpoint.FieldCursor, field.TypeUUID)
+	}
+	if value, ok := _u.mutation.Processed(); ok {
+		_spec.SetField(jobcheckpoint.FieldProcessed, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.AddedProcessed(); ok {
+		_spec.AddField(jobcheckpoint.FieldProcessed, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.FailedIds(); ok {
+		_spec.SetField(jobcheckpoint.FieldFailedIds, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedFailedIds(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, jobcheckpoint.FieldFailedIds, value)
+		})
+	}
+	if _u.mutation.FailedIdsCleared() {
+		_spec.ClearField(jobcheckpoint.FieldFailedIds, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.CompletedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldCompletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.CompletedAtCleared() {
+		_spec.ClearField(jobcheckpoint.FieldCompletedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{jobcheckpoint.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// JobCheckpointUpdateOne is the builder for updating a single JobCheckpoint entity.
+type JobCheckpointUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *JobCheckpointMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *JobCheckpointUpdateOne) SetUpdatedAt(v time.Time) *JobCheckpointUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_u *JobCheckpointUpdateOne) SetDeletedAt(v time.Time) *JobCheckpointUpdateOne {
+	_u.mutation.SetDeletedAt(v)
+	return _u
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_u *JobCheckpointUpdateOne) SetNillableDeletedAt(v *time.Time) *JobCheckpointUpdateOne {
+	if v != nil {
+		_u.SetDeletedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (_u *JobCheckpointUpdateOne) ClearDeletedAt() *JobCheckpointUpdateOne {
+	_u.mutation.ClearDeletedAt()
+	return _u
+}
+
+// SetCursor sets the "cursor" field.
+func (_u *JobCheckpointUpdateOne) SetCursor(v binid.BinId) *JobCheckpointUpdateOne {
+	_u.mutation.SetCursor(v)
+	return _u
+}
+
+// SetNillableCursor sets the "cursor" field if the given value is not nil.
+func (_u *JobCheckpointUpdateOne) SetNillableCursor(v *binid.BinId) *JobCheckpointUpdateOne {
+	if v != nil {
+		_u.SetCursor(*v)
+	}
+	return _u
+}
+
+// ClearCursor clears the value of the "cursor" field.
+func (_u *JobCheckpointUpdateOne) ClearCursor() *JobCheckpointUpdateOne {
+	_u.mutation.ClearCursor()
+	return _u
+}
+
+// SetProcessed sets the "processed" field.
+func (_u *JobCheckpointUpdateOne) SetProcessed(v uint64) *JobCheckpointUpdateOne {
+	_u.mutation.ResetProcessed()
+	_u.mutation.SetProcessed(v)
+	return _u
+}
+
+// SetNillableProcessed sets the "processed" field if the given value is not nil.
+func (_u *JobCheckpointUpdateOne) SetNillableProcessed(v *uint64) *JobCheckpointUpdateOne {
+	if v != nil {
+		_u.SetProcessed(*v)
+	}
+	return _u
+}
+
+// AddProcessed adds value to the "processed" field.
+func (_u *JobCheckpointUpdateOne) AddProcessed(v int64) *JobCheckpointUpdateOne {
+	_u.mutation.AddProcessed(v)
+	return _u
+}
+
+// SetFailedIds sets the "failed_ids" field.
+func (_u *JobCheckpointUpdateOne) SetFailedIds(v []string) *JobCheckpointUpdateOne {
+	_u.mutation.SetFailedIds(v)
+	return _u
+}
+
+// AppendFailedIds appends value to the "failed_ids" field.
+func (_u *JobCheckpointUpdateOne) AppendFailedIds(v []string) *JobCheckpointUpdateOne {
+	_u.mutation.AppendFailedIds(v)
+	return _u
+}
+
+// ClearFailedIds clears the value of the "failed_ids" field.
+func (_u *JobCheckpointUpdateOne) ClearFailedIds() *JobCheckpointUpdateOne {
+	_u.mutation.ClearFailedIds()
+	return _u
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (_u *JobCheckpointUpdateOne) SetCompletedAt(v time.Time) *JobCheckpointUpdateOne {
+	_u.mutation.SetCompletedAt(v)
+	return _u
+}
+
+// SetNillableCompletedAt sets the "completed_at" field if the given value is not nil.
+func (_u *JobCheckpointUpdateOne) SetNillableCompletedAt(v *time.Time) *JobCheckpointUpdateOne {
+	if v != nil {
+		_u.SetCompletedAt(*v)
+	}
+	return _u
+}
+
+// ClearCompletedAt clears the value of the "completed_at" field.
+func (_u *JobCheckpointUpdateOne) ClearCompletedAt() *JobCheckpointUpdateOne {
+	_u.mutation.ClearCompletedAt()
+	return _u
+}
+
+// Mutation returns the JobCheckpointMutation object of the builder.
+func (_u *JobCheckpointUpdateOne) Mutation() *JobCheckpointMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the JobCheckpointUpdate builder.
+func (_u *JobCheckpointUpdateOne) Where(ps ...predicate.JobCheckpoint) *JobCheckpointUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *JobCheckpointUpdateOne) Select(field string, fields ...string) *JobCheckpointUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated JobCheckpoint entity.
+func (_u *JobCheckpointUpdateOne) Save(ctx context.Context) (*JobCheckpoint, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *JobCheckpointUpdateOne) SaveX(ctx context.Context) *JobCheckpoint {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *JobCheckpointUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *JobCheckpointUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *JobCheckpointUpdateOne) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := jobcheckpoint.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+func (_u *JobCheckpointUpdateOne) sqlSave(ctx context.Context) (_node *JobCheckpoint, err error) {
+	_spec := sqlgraph.NewUpdateSpec(jobcheckpoint.Table, jobcheckpoint.Columns, sqlgraph.NewFieldSpec(jobcheckpoint.FieldID, field.TypeUUID))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "JobCheckpoint.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, jobcheckpoint.FieldID)
+		for _, f := range fields {
+			if !jobcheckpoint.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != jobcheckpoint.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.DeletedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldDeletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeletedAtCleared() {
+		_spec.ClearField(jobcheckpoint.FieldDeletedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Cursor(); ok {
+		_spec.SetField(jobcheckpoint.FieldCursor, field.TypeUUID, value)
+	}
+	if _u.mutation.CursorCleared() {
+		_spec.ClearField(jobcheckpoint.FieldCursor, field.TypeUUID)
+	}
+	if value, ok := _u.mutation.Processed(); ok {
+		_spec.SetField(jobcheckpoint.FieldProcessed, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.AddedProcessed(); ok {
+		_spec.AddField(jobcheckpoint.FieldProcessed, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.FailedIds(); ok {
+		_spec.SetField(jobcheckpoint.FieldFailedIds, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedFailedIds(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, jobcheckpoint.FieldFailedIds, value)
+		})
+	}
+	if _u.mutation.FailedIdsCleared() {
+		_spec.ClearField(jobcheckpoint.FieldFailedIds, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.CompletedAt(); ok {
+		_spec.SetField(jobcheckpoint.FieldCompletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.CompletedAtCleared() {
+		_spec.ClearField(jobcheckpoint.FieldCompletedAt, field.TypeTime)
+	}
+	_node = &JobCheckpoint{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{jobcheckpoint.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
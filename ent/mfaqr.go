@@ -5,6 +5,7 @@ package ent
 import (
 	"fmt"
 	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
 	"nidan-kai/ent/mfaqr"
 	"nidan-kai/ent/user"
 	"strings"
@@ -25,10 +26,38 @@ type MfaQr struct {
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 	// DeletedAt holds the value of the "deleted_at" field.
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// FailedAttempts holds the value of the "failed_attempts" field.
+	FailedAttempts uint32 `json:"failed_attempts,omitempty"`
+	// LastFailedAt holds the value of the "last_failed_at" field.
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty"`
+	// LockedUntil holds the value of the "locked_until" field.
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
 	// Secret holds the value of the "secret" field.
 	Secret []byte `json:"secret,omitempty"`
 	// UserID holds the value of the "user_id" field.
 	UserID binid.BinId `json:"user_id,omitempty"`
+	// DataKeyID holds the value of the "data_key_id" field.
+	DataKeyID *binid.BinId `json:"data_key_id,omitempty"`
+	// SecretBound holds the value of the "secret_bound" field.
+	SecretBound bool `json:"secret_bound,omitempty"`
+	// Type holds the value of the "type" field.
+	Type mfaqr.Type `json:"type,omitempty"`
+	// Algorithm holds the value of the "algorithm" field.
+	Algorithm mfaqr.Algorithm `json:"algorithm,omitempty"`
+	// Digits holds the value of the "digits" field.
+	Digits uint8 `json:"digits,omitempty"`
+	// Period holds the value of the "period" field.
+	Period uint32 `json:"period,omitempty"`
+	// Counter holds the value of the "counter" field.
+	Counter uint64 `json:"counter,omitempty"`
+	// LastStep holds the value of the "last_step" field.
+	LastStep uint64 `json:"last_step,omitempty"`
+	// Status holds the value of the "status" field.
+	Status mfaqr.Status `json:"status,omitempty"`
+	// ExpiresAt holds the value of the "expires_at" field.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// ActivatedAt holds the value of the "activated_at" field.
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the MfaQrQuery when eager-loading is set.
 	Edges        MfaQrEdges `json:"edges"`
@@ -39,9 +68,11 @@ type MfaQr struct {
 type MfaQrEdges struct {
 	// User holds the value of the user edge.
 	User *User `json:"user,omitempty"`
+	// DataKey holds the value of the data_key edge.
+	DataKey *DataKey `json:"data_key,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [2]bool
 }
 
 // UserOrErr returns the User value or an error if the edge
@@ -55,16 +86,35 @@ func (e MfaQrEdges) UserOrErr() (*User, error) {
 	return nil, &NotLoadedError{edge: "user"}
 }
 
+// DataKeyOrErr returns the DataKey value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e MfaQrEdges) DataKeyOrErr() (*DataKey, error) {
+	if e.DataKey != nil {
+		return e.DataKey, nil
+	} else if e.loadedTypes[1] {
+		return nil, &NotFoundError{label: datakey.Label}
+	}
+	return nil, &NotLoadedError{edge: "data_key"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*MfaQr) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case mfaqr.FieldDataKeyID:
+			values[i] = &sql.NullScanner{S: new(binid.BinId)}
 		case mfaqr.FieldSecret:
 			values[i] = new([]byte)
 		case mfaqr.FieldID, mfaqr.FieldUserID:
 			values[i] = new(binid.BinId)
-		case mfaqr.FieldCreatedAt, mfaqr.FieldUpdatedAt, mfaqr.FieldDeletedAt:
+		case mfaqr.FieldSecretBound:
+			values[i] = new(sql.NullBool)
+		case mfaqr.FieldFailedAttempts, mfaqr.FieldDigits, mfaqr.FieldPeriod, mfaqr.FieldCounter, mfaqr.FieldLastStep:
+			values[i] = new(sql.NullInt64)
+		case mfaqr.FieldType, mfaqr.FieldAlgorithm, mfaqr.FieldStatus:
+			values[i] = new(sql.NullString)
+		case mfaqr.FieldCreatedAt, mfaqr.FieldUpdatedAt, mfaqr.FieldDeletedAt, mfaqr.FieldLastFailedAt, mfaqr.FieldLockedUntil, mfaqr.FieldExpiresAt, mfaqr.FieldActivatedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -106,6 +156,26 @@ func (_m *MfaQr) assignValues(columns []string, values []any) error {
 				_m.DeletedAt = new(time.Time)
 				*_m.DeletedAt = value.Time
 			}
+		case mfaqr.FieldFailedAttempts:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field failed_attempts", values[i])
+			} else if value.Valid {
+				_m.FailedAttempts = uint32(value.Int64)
+			}
+		case mfaqr.FieldLastFailedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field last_failed_at", values[i])
+			} else if value.Valid {
+				_m.LastFailedAt = new(time.Time)
+				*_m.LastFailedAt = value.Time
+			}
+		case mfaqr.FieldLockedUntil:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field locked_until", values[i])
+			} else if value.Valid {
+				_m.LockedUntil = new(time.Time)
+				*_m.LockedUntil = value.Time
+			}
 		case mfaqr.FieldSecret:
 			if value, ok := values[i].(*[]byte); !ok {
 				return fmt.Errorf("unexpected type %T for field secret", values[i])
@@ -118,6 +188,75 @@ func (_m *MfaQr) assignValues(columns []string, values []any) error {
 			} else if value != nil {
 				_m.UserID = *value
 			}
+		case mfaqr.FieldDataKeyID:
+			if value, ok := values[i].(*sql.NullScanner); !ok {
+				return fmt.Errorf("unexpected type %T for field data_key_id", values[i])
+			} else if value.Valid {
+				_m.DataKeyID = new(binid.BinId)
+				*_m.DataKeyID = *value.S.(*binid.BinId)
+			}
+		case mfaqr.FieldSecretBound:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field secret_bound", values[i])
+			} else if value.Valid {
+				_m.SecretBound = value.Bool
+			}
+		case mfaqr.FieldType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field type", values[i])
+			} else if value.Valid {
+				_m.Type = mfaqr.Type(value.String)
+			}
+		case mfaqr.FieldAlgorithm:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field algorithm", values[i])
+			} else if value.Valid {
+				_m.Algorithm = mfaqr.Algorithm(value.String)
+			}
+		case mfaqr.FieldDigits:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field digits", values[i])
+			} else if value.Valid {
+				_m.Digits = uint8(value.Int64)
+			}
+		case mfaqr.FieldPeriod:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field period", values[i])
+			} else if value.Valid {
+				_m.Period = uint32(value.Int64)
+			}
+		case mfaqr.FieldCounter:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field counter", values[i])
+			} else if value.Valid {
+				_m.Counter = uint64(value.Int64)
+			}
+		case mfaqr.FieldLastStep:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field last_step", values[i])
+			} else if value.Valid {
+				_m.LastStep = uint64(value.Int64)
+			}
+		case mfaqr.FieldStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field status", values[i])
+			} else if value.Valid {
+				_m.Status = mfaqr.Status(value.String)
+			}
+		case mfaqr.FieldExpiresAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field expires_at", values[i])
+			} else if value.Valid {
+				_m.ExpiresAt = new(time.Time)
+				*_m.ExpiresAt = value.Time
+			}
+		case mfaqr.FieldActivatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field activated_at", values[i])
+			} else if value.Valid {
+				_m.ActivatedAt = new(time.Time)
+				*_m.ActivatedAt = value.Time
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -136,6 +275,11 @@ func (_m *MfaQr) QueryUser() *UserQuery {
 	return NewMfaQrClient(_m.config).QueryUser(_m)
 }
 
+// QueryDataKey queries the "data_key" edge of the MfaQr entity.
+func (_m *MfaQr) QueryDataKey() *DataKeyQuery {
+	return NewMfaQrClient(_m.config).QueryDataKey(_m)
+}
+
 // Update returns a builder for updating this MfaQr.
 // Note that you need to call MfaQr.Unwrap() before calling this method if this MfaQr
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -170,11 +314,63 @@ func (_m *MfaQr) String() string {
 		builder.WriteString(v.Format(time.ANSIC))
 	}
 	builder.WriteString(", ")
+	builder.WriteString("failed_attempts=")
+	builder.WriteString(fmt.Sprintf("%v", _m.FailedAttempts))
+	builder.WriteString(", ")
+	if v := _m.LastFailedAt; v != nil {
+		builder.WriteString("last_failed_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := _m.LockedUntil; v != nil {
+		builder.WriteString("locked_until=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("secret=")
 	builder.WriteString(fmt.Sprintf("%v", _m.Secret))
 	builder.WriteString(", ")
 	builder.WriteString("user_id=")
 	builder.WriteString(fmt.Sprintf("%v", _m.UserID))
+	builder.WriteString(", ")
+	if v := _m.DataKeyID; v != nil {
+		builder.WriteString("data_key_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("secret_bound=")
+	builder.WriteString(fmt.Sprintf("%v", _m.SecretBound))
+	builder.WriteString(", ")
+	builder.WriteString("type=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Type))
+	builder.WriteString(", ")
+	builder.WriteString("algorithm=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Algorithm))
+	builder.WriteString(", ")
+	builder.WriteString("digits=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Digits))
+	builder.WriteString(", ")
+	builder.WriteString("period=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Period))
+	builder.WriteString(", ")
+	builder.WriteString("counter=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Counter))
+	builder.WriteString(", ")
+	builder.WriteString("last_step=")
+	builder.WriteString(fmt.Sprintf("%v", _m.LastStep))
+	builder.WriteString(", ")
+	builder.WriteString("status=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Status))
+	builder.WriteString(", ")
+	if v := _m.ExpiresAt; v != nil {
+		builder.WriteString("expires_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := _m.ActivatedAt; v != nil {
+		builder.WriteString("activated_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -3,6 +3,7 @@
 package mfaqr
 
 import (
+	"fmt"
 	"time"
 
 	"entgo.io/ent/dialect/sql"
@@ -20,12 +21,42 @@ const (
 	FieldUpdatedAt = "updated_at"
 	// FieldDeletedAt holds the string denoting the deleted_at field in the database.
 	FieldDeletedAt = "deleted_at"
+	// FieldFailedAttempts holds the string denoting the failed_attempts field in the database.
+	FieldFailedAttempts = "failed_attempts"
+	// FieldLastFailedAt holds the string denoting the last_failed_at field in the database.
+	FieldLastFailedAt = "last_failed_at"
+	// FieldLockedUntil holds the string denoting the locked_until field in the database.
+	FieldLockedUntil = "locked_until"
 	// FieldSecret holds the string denoting the secret field in the database.
 	FieldSecret = "secret"
 	// FieldUserID holds the string denoting the user_id field in the database.
 	FieldUserID = "user_id"
+	// FieldDataKeyID holds the string denoting the data_key_id field in the database.
+	FieldDataKeyID = "data_key_id"
+	// FieldSecretBound holds the string denoting the secret_bound field in the database.
+	FieldSecretBound = "secret_bound"
+	// FieldType holds the string denoting the type field in the database.
+	FieldType = "type"
+	// FieldAlgorithm holds the string denoting the algorithm field in the database.
+	FieldAlgorithm = "algorithm"
+	// FieldDigits holds the string denoting the digits field in the database.
+	FieldDigits = "digits"
+	// FieldPeriod holds the string denoting the period field in the database.
+	FieldPeriod = "period"
+	// FieldCounter holds the string denoting the counter field in the database.
+	FieldCounter = "counter"
+	// FieldLastStep holds the string denoting the last_step field in the database.
+	FieldLastStep = "last_step"
+	// FieldStatus holds the string denoting the status field in the database.
+	FieldStatus = "status"
+	// FieldExpiresAt holds the string denoting the expires_at field in the database.
+	FieldExpiresAt = "expires_at"
+	// FieldActivatedAt holds the string denoting the activated_at field in the database.
+	FieldActivatedAt = "activated_at"
 	// EdgeUser holds the string denoting the user edge name in mutations.
 	EdgeUser = "user"
+	// EdgeDataKey holds the string denoting the data_key edge name in mutations.
+	EdgeDataKey = "data_key"
 	// Table holds the table name of the mfaqr in the database.
 	Table = "mfa_qrs"
 	// UserTable is the table that holds the user relation/edge.
@@ -35,6 +66,13 @@ const (
 	UserInverseTable = "users"
 	// UserColumn is the table column denoting the user relation/edge.
 	UserColumn = "user_id"
+	// DataKeyTable is the table that holds the data_key relation/edge.
+	DataKeyTable = "mfa_qrs"
+	// DataKeyInverseTable is the table name for the DataKey entity.
+	// It exists in this package in order to avoid circular dependency with the "datakey" package.
+	DataKeyInverseTable = "data_keys"
+	// DataKeyColumn is the table column denoting the data_key relation/edge.
+	DataKeyColumn = "data_key_id"
 )
 
 // Columns holds all SQL columns for mfaqr fields.
@@ -43,8 +81,22 @@ var Columns = []string{
 	FieldCreatedAt,
 	FieldUpdatedAt,
 	FieldDeletedAt,
+	FieldFailedAttempts,
+	FieldLastFailedAt,
+	FieldLockedUntil,
 	FieldSecret,
 	FieldUserID,
+	FieldDataKeyID,
+	FieldSecretBound,
+	FieldType,
+	FieldAlgorithm,
+	FieldDigits,
+	FieldPeriod,
+	FieldCounter,
+	FieldLastStep,
+	FieldStatus,
+	FieldExpiresAt,
+	FieldActivatedAt,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -64,10 +116,105 @@ var (
 	DefaultUpdatedAt func() time.Time
 	// UpdateDefaultUpdatedAt holds the default value on update for the "updated_at" field.
 	UpdateDefaultUpdatedAt func() time.Time
+	// DefaultFailedAttempts holds the default value on creation for the "failed_attempts" field.
+	DefaultFailedAttempts uint32
 	// SecretValidator is a validator for the "secret" field. It is called by the builders before save.
 	SecretValidator func([]byte) error
+	// DefaultSecretBound holds the default value on creation for the "secret_bound" field.
+	DefaultSecretBound bool
+	// DefaultDigits holds the default value on creation for the "digits" field.
+	DefaultDigits uint8
+	// DigitsValidator is a validator for the "digits" field. It is called by the builders before save.
+	DigitsValidator func(uint8) error
+	// DefaultPeriod holds the default value on creation for the "period" field.
+	DefaultPeriod uint32
+	// PeriodValidator is a validator for the "period" field. It is called by the builders before save.
+	PeriodValidator func(uint32) error
+	// DefaultCounter holds the default value on creation for the "counter" field.
+	DefaultCounter uint64
+	// DefaultLastStep holds the default value on creation for the "last_step" field.
+	DefaultLastStep uint64
 )
 
+// Type defines the type for the "type" enum field.
+type Type string
+
+// TypeTotp is the default value of the Type enum.
+const DefaultType = TypeTotp
+
+// Type values.
+const (
+	TypeTotp Type = "totp"
+	TypeHotp Type = "hotp"
+)
+
+func (_type Type) String() string {
+	return string(_type)
+}
+
+// TypeValidator is a validator for the "type" field enum values. It is called by the builders before save.
+func TypeValidator(_type Type) error {
+	switch _type {
+	case TypeTotp, TypeHotp:
+		return nil
+	default:
+		return fmt.Errorf("mfaqr: invalid enum value for type field: %q", _type)
+	}
+}
+
+// Algorithm defines the type for the "algorithm" enum field.
+type Algorithm string
+
+// AlgorithmSHA1 is the default value of the Algorithm enum.
+const DefaultAlgorithm = AlgorithmSHA1
+
+// Algorithm values.
+const (
+	AlgorithmSHA1   Algorithm = "SHA1"
+	AlgorithmSHA256 Algorithm = "SHA256"
+	AlgorithmSHA512 Algorithm = "SHA512"
+)
+
+func (a Algorithm) String() string {
+	return string(a)
+}
+
+// AlgorithmValidator is a validator for the "algorithm" field enum values. It is called by the builders before save.
+func AlgorithmValidator(a Algorithm) error {
+	switch a {
+	case AlgorithmSHA1, AlgorithmSHA256, AlgorithmSHA512:
+		return nil
+	default:
+		return fmt.Errorf("mfaqr: invalid enum value for algorithm field: %q", a)
+	}
+}
+
+// Status defines the type for the "status" enum field.
+type Status string
+
+// StatusActive is the default value of the Status enum.
+const DefaultStatus = StatusActive
+
+// Status values.
+const (
+	StatusPending Status = "pending"
+	StatusActive  Status = "active"
+)
+
+func (s Status) String() string {
+	return string(s)
+}
+
+// StatusValidator is a validator for the "status" field enum values. It is called by the builders before save.
+func StatusValidator(s Status) error {
+	switch s {
+	case StatusPending, StatusActive:
+		return nil
+	default:
+		return fmt.Errorf("mfaqr: invalid enum value for status field: %q", s)
+	}
+}
+
 // OrderOption defines the ordering options for the MfaQr queries.
 type OrderOption func(*sql.Selector)
 
@@ -91,17 +238,94 @@ func ByDeletedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldDeletedAt, opts...).ToFunc()
 }
 
+// ByFailedAttempts orders the results by the failed_attempts field.
+func ByFailedAttempts(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFailedAttempts, opts...).ToFunc()
+}
+
+// ByLastFailedAt orders the results by the last_failed_at field.
+func ByLastFailedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLastFailedAt, opts...).ToFunc()
+}
+
+// ByLockedUntil orders the results by the locked_until field.
+func ByLockedUntil(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLockedUntil, opts...).ToFunc()
+}
+
 // ByUserID orders the results by the user_id field.
 func ByUserID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldUserID, opts...).ToFunc()
 }
 
+// ByDataKeyID orders the results by the data_key_id field.
+func ByDataKeyID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDataKeyID, opts...).ToFunc()
+}
+
+// BySecretBound orders the results by the secret_bound field.
+func BySecretBound(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSecretBound, opts...).ToFunc()
+}
+
+// ByType orders the results by the type field.
+func ByType(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldType, opts...).ToFunc()
+}
+
+// ByAlgorithm orders the results by the algorithm field.
+func ByAlgorithm(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAlgorithm, opts...).ToFunc()
+}
+
+// ByDigits orders the results by the digits field.
+func ByDigits(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDigits, opts...).ToFunc()
+}
+
+// ByPeriod orders the results by the period field.
+func ByPeriod(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPeriod, opts...).ToFunc()
+}
+
+// ByCounter orders the results by the counter field.
+func ByCounter(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCounter, opts...).ToFunc()
+}
+
+// ByLastStep orders the results by the last_step field.
+func ByLastStep(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLastStep, opts...).ToFunc()
+}
+
+// ByStatus orders the results by the status field.
+func ByStatus(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldStatus, opts...).ToFunc()
+}
+
+// ByExpiresAt orders the results by the expires_at field.
+func ByExpiresAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldExpiresAt, opts...).ToFunc()
+}
+
+// ByActivatedAt orders the results by the activated_at field.
+func ByActivatedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldActivatedAt, opts...).ToFunc()
+}
+
 // ByUserField orders the results by user field.
 func ByUserField(field string, opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
 		sqlgraph.OrderByNeighborTerms(s, newUserStep(), sql.OrderByField(field, opts...))
 	}
 }
+
+// ByDataKeyField orders the results by data_key field.
+func ByDataKeyField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newDataKeyStep(), sql.OrderByField(field, opts...))
+	}
+}
 func newUserStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
@@ -109,3 +333,10 @@ func newUserStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.M2O, true, UserTable, UserColumn),
 	)
 }
+func newDataKeyStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(DataKeyInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, true, DataKeyTable, DataKeyColumn),
+	)
+}
@@ -71,6 +71,21 @@ func DeletedAt(v time.Time) predicate.MfaQr {
 	return predicate.MfaQr(sql.FieldEQ(FieldDeletedAt, v))
 }
 
+// FailedAttempts applies equality check predicate on the "failed_attempts" field. It's identical to FailedAttemptsEQ.
+func FailedAttempts(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldFailedAttempts, v))
+}
+
+// LastFailedAt applies equality check predicate on the "last_failed_at" field. It's identical to LastFailedAtEQ.
+func LastFailedAt(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldLastFailedAt, v))
+}
+
+// LockedUntil applies equality check predicate on the "locked_until" field. It's identical to LockedUntilEQ.
+func LockedUntil(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldLockedUntil, v))
+}
+
 // Secret applies equality check predicate on the "secret" field. It's identical to SecretEQ.
 func Secret(v []byte) predicate.MfaQr {
 	return predicate.MfaQr(sql.FieldEQ(FieldSecret, v))
@@ -81,6 +96,46 @@ func UserID(v binid.BinId) predicate.MfaQr {
 	return predicate.MfaQr(sql.FieldEQ(FieldUserID, v))
 }
 
+// DataKeyID applies equality check predicate on the "data_key_id" field. It's identical to DataKeyIDEQ.
+func DataKeyID(v binid.BinId) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldDataKeyID, v))
+}
+
+// SecretBound applies equality check predicate on the "secret_bound" field. It's identical to SecretBoundEQ.
+func SecretBound(v bool) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldSecretBound, v))
+}
+
+// Digits applies equality check predicate on the "digits" field. It's identical to DigitsEQ.
+func Digits(v uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldDigits, v))
+}
+
+// Period applies equality check predicate on the "period" field. It's identical to PeriodEQ.
+func Period(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldPeriod, v))
+}
+
+// Counter applies equality check predicate on the "counter" field. It's identical to CounterEQ.
+func Counter(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldCounter, v))
+}
+
+// LastStep applies equality check predicate on the "last_step" field. It's identical to LastStepEQ.
+func LastStep(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldLastStep, v))
+}
+
+// ExpiresAt applies equality check predicate on the "expires_at" field. It's identical to ExpiresAtEQ.
+func ExpiresAt(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// ActivatedAt applies equality check predicate on the "activated_at" field. It's identical to ActivatedAtEQ.
+func ActivatedAt(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldActivatedAt, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.MfaQr {
 	return predicate.MfaQr(sql.FieldEQ(FieldCreatedAt, v))
@@ -211,6 +266,146 @@ func DeletedAtNotNil() predicate.MfaQr {
 	return predicate.MfaQr(sql.FieldNotNull(FieldDeletedAt))
 }
 
+// FailedAttemptsEQ applies the EQ predicate on the "failed_attempts" field.
+func FailedAttemptsEQ(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldFailedAttempts, v))
+}
+
+// FailedAttemptsNEQ applies the NEQ predicate on the "failed_attempts" field.
+func FailedAttemptsNEQ(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldFailedAttempts, v))
+}
+
+// FailedAttemptsIn applies the In predicate on the "failed_attempts" field.
+func FailedAttemptsIn(vs ...uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldFailedAttempts, vs...))
+}
+
+// FailedAttemptsNotIn applies the NotIn predicate on the "failed_attempts" field.
+func FailedAttemptsNotIn(vs ...uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldFailedAttempts, vs...))
+}
+
+// FailedAttemptsGT applies the GT predicate on the "failed_attempts" field.
+func FailedAttemptsGT(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldFailedAttempts, v))
+}
+
+// FailedAttemptsGTE applies the GTE predicate on the "failed_attempts" field.
+func FailedAttemptsGTE(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldFailedAttempts, v))
+}
+
+// FailedAttemptsLT applies the LT predicate on the "failed_attempts" field.
+func FailedAttemptsLT(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldFailedAttempts, v))
+}
+
+// FailedAttemptsLTE applies the LTE predicate on the "failed_attempts" field.
+func FailedAttemptsLTE(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldFailedAttempts, v))
+}
+
+// LastFailedAtEQ applies the EQ predicate on the "last_failed_at" field.
+func LastFailedAtEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldLastFailedAt, v))
+}
+
+// LastFailedAtNEQ applies the NEQ predicate on the "last_failed_at" field.
+func LastFailedAtNEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldLastFailedAt, v))
+}
+
+// LastFailedAtIn applies the In predicate on the "last_failed_at" field.
+func LastFailedAtIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldLastFailedAt, vs...))
+}
+
+// LastFailedAtNotIn applies the NotIn predicate on the "last_failed_at" field.
+func LastFailedAtNotIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldLastFailedAt, vs...))
+}
+
+// LastFailedAtGT applies the GT predicate on the "last_failed_at" field.
+func LastFailedAtGT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldLastFailedAt, v))
+}
+
+// LastFailedAtGTE applies the GTE predicate on the "last_failed_at" field.
+func LastFailedAtGTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldLastFailedAt, v))
+}
+
+// LastFailedAtLT applies the LT predicate on the "last_failed_at" field.
+func LastFailedAtLT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldLastFailedAt, v))
+}
+
+// LastFailedAtLTE applies the LTE predicate on the "last_failed_at" field.
+func LastFailedAtLTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldLastFailedAt, v))
+}
+
+// LastFailedAtIsNil applies the IsNil predicate on the "last_failed_at" field.
+func LastFailedAtIsNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIsNull(FieldLastFailedAt))
+}
+
+// LastFailedAtNotNil applies the NotNil predicate on the "last_failed_at" field.
+func LastFailedAtNotNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotNull(FieldLastFailedAt))
+}
+
+// LockedUntilEQ applies the EQ predicate on the "locked_until" field.
+func LockedUntilEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldLockedUntil, v))
+}
+
+// LockedUntilNEQ applies the NEQ predicate on the "locked_until" field.
+func LockedUntilNEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldLockedUntil, v))
+}
+
+// LockedUntilIn applies the In predicate on the "locked_until" field.
+func LockedUntilIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldLockedUntil, vs...))
+}
+
+// LockedUntilNotIn applies the NotIn predicate on the "locked_until" field.
+func LockedUntilNotIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldLockedUntil, vs...))
+}
+
+// LockedUntilGT applies the GT predicate on the "locked_until" field.
+func LockedUntilGT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldLockedUntil, v))
+}
+
+// LockedUntilGTE applies the GTE predicate on the "locked_until" field.
+func LockedUntilGTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldLockedUntil, v))
+}
+
+// LockedUntilLT applies the LT predicate on the "locked_until" field.
+func LockedUntilLT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldLockedUntil, v))
+}
+
+// LockedUntilLTE applies the LTE predicate on the "locked_until" field.
+func LockedUntilLTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldLockedUntil, v))
+}
+
+// LockedUntilIsNil applies the IsNil predicate on the "locked_until" field.
+func LockedUntilIsNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIsNull(FieldLockedUntil))
+}
+
+// LockedUntilNotNil applies the NotNil predicate on the "locked_until" field.
+func LockedUntilNotNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotNull(FieldLockedUntil))
+}
+
 // SecretEQ applies the EQ predicate on the "secret" field.
 func SecretEQ(v []byte) predicate.MfaQr {
 	return predicate.MfaQr(sql.FieldEQ(FieldSecret, v))
@@ -271,6 +466,366 @@ func UserIDNotIn(vs ...binid.BinId) predicate.MfaQr {
 	return predicate.MfaQr(sql.FieldNotIn(FieldUserID, vs...))
 }
 
+// DataKeyIDEQ applies the EQ predicate on the "data_key_id" field.
+func DataKeyIDEQ(v binid.BinId) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldDataKeyID, v))
+}
+
+// DataKeyIDNEQ applies the NEQ predicate on the "data_key_id" field.
+func DataKeyIDNEQ(v binid.BinId) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldDataKeyID, v))
+}
+
+// DataKeyIDIn applies the In predicate on the "data_key_id" field.
+func DataKeyIDIn(vs ...binid.BinId) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldDataKeyID, vs...))
+}
+
+// DataKeyIDNotIn applies the NotIn predicate on the "data_key_id" field.
+func DataKeyIDNotIn(vs ...binid.BinId) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldDataKeyID, vs...))
+}
+
+// DataKeyIDIsNil applies the IsNil predicate on the "data_key_id" field.
+func DataKeyIDIsNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIsNull(FieldDataKeyID))
+}
+
+// DataKeyIDNotNil applies the NotNil predicate on the "data_key_id" field.
+func DataKeyIDNotNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotNull(FieldDataKeyID))
+}
+
+// SecretBoundEQ applies the EQ predicate on the "secret_bound" field.
+func SecretBoundEQ(v bool) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldSecretBound, v))
+}
+
+// SecretBoundNEQ applies the NEQ predicate on the "secret_bound" field.
+func SecretBoundNEQ(v bool) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldSecretBound, v))
+}
+
+// TypeEQ applies the EQ predicate on the "type" field.
+func TypeEQ(v Type) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldType, v))
+}
+
+// TypeNEQ applies the NEQ predicate on the "type" field.
+func TypeNEQ(v Type) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldType, v))
+}
+
+// TypeIn applies the In predicate on the "type" field.
+func TypeIn(vs ...Type) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldType, vs...))
+}
+
+// TypeNotIn applies the NotIn predicate on the "type" field.
+func TypeNotIn(vs ...Type) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldType, vs...))
+}
+
+// AlgorithmEQ applies the EQ predicate on the "algorithm" field.
+func AlgorithmEQ(v Algorithm) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldAlgorithm, v))
+}
+
+// AlgorithmNEQ applies the NEQ predicate on the "algorithm" field.
+func AlgorithmNEQ(v Algorithm) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldAlgorithm, v))
+}
+
+// AlgorithmIn applies the In predicate on the "algorithm" field.
+func AlgorithmIn(vs ...Algorithm) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldAlgorithm, vs...))
+}
+
+// AlgorithmNotIn applies the NotIn predicate on the "algorithm" field.
+func AlgorithmNotIn(vs ...Algorithm) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldAlgorithm, vs...))
+}
+
+// DigitsEQ applies the EQ predicate on the "digits" field.
+func DigitsEQ(v uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldDigits, v))
+}
+
+// DigitsNEQ applies the NEQ predicate on the "digits" field.
+func DigitsNEQ(v uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldDigits, v))
+}
+
+// DigitsIn applies the In predicate on the "digits" field.
+func DigitsIn(vs ...uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldDigits, vs...))
+}
+
+// DigitsNotIn applies the NotIn predicate on the "digits" field.
+func DigitsNotIn(vs ...uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldDigits, vs...))
+}
+
+// DigitsGT applies the GT predicate on the "digits" field.
+func DigitsGT(v uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldDigits, v))
+}
+
+// DigitsGTE applies the GTE predicate on the "digits" field.
+func DigitsGTE(v uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldDigits, v))
+}
+
+// DigitsLT applies the LT predicate on the "digits" field.
+func DigitsLT(v uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldDigits, v))
+}
+
+// DigitsLTE applies the LTE predicate on the "digits" field.
+func DigitsLTE(v uint8) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldDigits, v))
+}
+
+// PeriodEQ applies the EQ predicate on the "period" field.
+func PeriodEQ(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldPeriod, v))
+}
+
+// PeriodNEQ applies the NEQ predicate on the "period" field.
+func PeriodNEQ(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldPeriod, v))
+}
+
+// PeriodIn applies the In predicate on the "period" field.
+func PeriodIn(vs ...uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldPeriod, vs...))
+}
+
+// PeriodNotIn applies the NotIn predicate on the "period" field.
+func PeriodNotIn(vs ...uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldPeriod, vs...))
+}
+
+// PeriodGT applies the GT predicate on the "period" field.
+func PeriodGT(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldPeriod, v))
+}
+
+// PeriodGTE applies the GTE predicate on the "period" field.
+func PeriodGTE(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldPeriod, v))
+}
+
+// PeriodLT applies the LT predicate on the "period" field.
+func PeriodLT(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldPeriod, v))
+}
+
+// PeriodLTE applies the LTE predicate on the "period" field.
+func PeriodLTE(v uint32) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldPeriod, v))
+}
+
+// CounterEQ applies the EQ predicate on the "counter" field.
+func CounterEQ(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldCounter, v))
+}
+
+// CounterNEQ applies the NEQ predicate on the "counter" field.
+func CounterNEQ(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldCounter, v))
+}
+
+// CounterIn applies the In predicate on the "counter" field.
+func CounterIn(vs ...uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldCounter, vs...))
+}
+
+// CounterNotIn applies the NotIn predicate on the "counter" field.
+func CounterNotIn(vs ...uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldCounter, vs...))
+}
+
+// CounterGT applies the GT predicate on the "counter" field.
+func CounterGT(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldCounter, v))
+}
+
+// CounterGTE applies the GTE predicate on the "counter" field.
+func CounterGTE(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldCounter, v))
+}
+
+// CounterLT applies the LT predicate on the "counter" field.
+func CounterLT(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldCounter, v))
+}
+
+// CounterLTE applies the LTE predicate on the "counter" field.
+func CounterLTE(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldCounter, v))
+}
+
+// LastStepEQ applies the EQ predicate on the "last_step" field.
+func LastStepEQ(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldLastStep, v))
+}
+
+// LastStepNEQ applies the NEQ predicate on the "last_step" field.
+func LastStepNEQ(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldLastStep, v))
+}
+
+// LastStepIn applies the In predicate on the "last_step" field.
+func LastStepIn(vs ...uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldLastStep, vs...))
+}
+
+// LastStepNotIn applies the NotIn predicate on the "last_step" field.
+func LastStepNotIn(vs ...uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldLastStep, vs...))
+}
+
+// LastStepGT applies the GT predicate on the "last_step" field.
+func LastStepGT(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldLastStep, v))
+}
+
+// LastStepGTE applies the GTE predicate on the "last_step" field.
+func LastStepGTE(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldLastStep, v))
+}
+
+// LastStepLT applies the LT predicate on the "last_step" field.
+func LastStepLT(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldLastStep, v))
+}
+
+// LastStepLTE applies the LTE predicate on the "last_step" field.
+func LastStepLTE(v uint64) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldLastStep, v))
+}
+
+// StatusEQ applies the EQ predicate on the "status" field.
+func StatusEQ(v Status) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldStatus, v))
+}
+
+// StatusNEQ applies the NEQ predicate on the "status" field.
+func StatusNEQ(v Status) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldStatus, v))
+}
+
+// StatusIn applies the In predicate on the "status" field.
+func StatusIn(vs ...Status) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldStatus, vs...))
+}
+
+// StatusNotIn applies the NotIn predicate on the "status" field.
+func StatusNotIn(vs ...Status) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldStatus, vs...))
+}
+
+// ExpiresAtEQ applies the EQ predicate on the "expires_at" field.
+func ExpiresAtEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtNEQ applies the NEQ predicate on the "expires_at" field.
+func ExpiresAtNEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldExpiresAt, v))
+}
+
+// ExpiresAtIn applies the In predicate on the "expires_at" field.
+func ExpiresAtIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtNotIn applies the NotIn predicate on the "expires_at" field.
+func ExpiresAtNotIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldExpiresAt, vs...))
+}
+
+// ExpiresAtGT applies the GT predicate on the "expires_at" field.
+func ExpiresAtGT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldExpiresAt, v))
+}
+
+// ExpiresAtGTE applies the GTE predicate on the "expires_at" field.
+func ExpiresAtGTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldExpiresAt, v))
+}
+
+// ExpiresAtLT applies the LT predicate on the "expires_at" field.
+func ExpiresAtLT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldExpiresAt, v))
+}
+
+// ExpiresAtLTE applies the LTE predicate on the "expires_at" field.
+func ExpiresAtLTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldExpiresAt, v))
+}
+
+// ExpiresAtIsNil applies the IsNil predicate on the "expires_at" field.
+func ExpiresAtIsNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIsNull(FieldExpiresAt))
+}
+
+// ExpiresAtNotNil applies the NotNil predicate on the "expires_at" field.
+func ExpiresAtNotNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotNull(FieldExpiresAt))
+}
+
+// ActivatedAtEQ applies the EQ predicate on the "activated_at" field.
+func ActivatedAtEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldEQ(FieldActivatedAt, v))
+}
+
+// ActivatedAtNEQ applies the NEQ predicate on the "activated_at" field.
+func ActivatedAtNEQ(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNEQ(FieldActivatedAt, v))
+}
+
+// ActivatedAtIn applies the In predicate on the "activated_at" field.
+func ActivatedAtIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIn(FieldActivatedAt, vs...))
+}
+
+// ActivatedAtNotIn applies the NotIn predicate on the "activated_at" field.
+func ActivatedAtNotIn(vs ...time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotIn(FieldActivatedAt, vs...))
+}
+
+// ActivatedAtGT applies the GT predicate on the "activated_at" field.
+func ActivatedAtGT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGT(FieldActivatedAt, v))
+}
+
+// ActivatedAtGTE applies the GTE predicate on the "activated_at" field.
+func ActivatedAtGTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldGTE(FieldActivatedAt, v))
+}
+
+// ActivatedAtLT applies the LT predicate on the "activated_at" field.
+func ActivatedAtLT(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLT(FieldActivatedAt, v))
+}
+
+// ActivatedAtLTE applies the LTE predicate on the "activated_at" field.
+func ActivatedAtLTE(v time.Time) predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldLTE(FieldActivatedAt, v))
+}
+
+// ActivatedAtIsNil applies the IsNil predicate on the "activated_at" field.
+func ActivatedAtIsNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldIsNull(FieldActivatedAt))
+}
+
+// ActivatedAtNotNil applies the NotNil predicate on the "activated_at" field.
+func ActivatedAtNotNil() predicate.MfaQr {
+	return predicate.MfaQr(sql.FieldNotNull(FieldActivatedAt))
+}
+
 // HasUser applies the HasEdge predicate on the "user" edge.
 func HasUser() predicate.MfaQr {
 	return predicate.MfaQr(func(s *sql.Selector) {
@@ -294,6 +849,29 @@ func HasUserWith(preds ...predicate.User) predicate.MfaQr {
 	})
 }
 
+// HasDataKey applies the HasEdge predicate on the "data_key" edge.
+func HasDataKey() predicate.MfaQr {
+	return predicate.MfaQr(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, DataKeyTable, DataKeyColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasDataKeyWith applies the HasEdge predicate on the "data_key" edge with a given conditions (other predicates).
+func HasDataKeyWith(preds ...predicate.DataKey) predicate.MfaQr {
+	return predicate.MfaQr(func(s *sql.Selector) {
+		step := newDataKeyStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.MfaQr) predicate.MfaQr {
 	return predicate.MfaQr(sql.AndPredicates(predicates...))
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
 	"nidan-kai/ent/mfaqr"
 	"nidan-kai/ent/user"
 	"time"
@@ -64,6 +65,48 @@ func (_c *MfaQrCreate) SetNillableDeletedAt(v *time.Time) *MfaQrCreate {
 	return _c
 }
 
+// SetFailedAttempts sets the "failed_attempts" field.
+func (_c *MfaQrCreate) SetFailedAttempts(v uint32) *MfaQrCreate {
+	_c.mutation.SetFailedAttempts(v)
+	return _c
+}
+
+// SetNillableFailedAttempts sets the "failed_attempts" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableFailedAttempts(v *uint32) *MfaQrCreate {
+	if v != nil {
+		_c.SetFailedAttempts(*v)
+	}
+	return _c
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (_c *MfaQrCreate) SetLastFailedAt(v time.Time) *MfaQrCreate {
+	_c.mutation.SetLastFailedAt(v)
+	return _c
+}
+
+// SetNillableLastFailedAt sets the "last_failed_at" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableLastFailedAt(v *time.Time) *MfaQrCreate {
+	if v != nil {
+		_c.SetLastFailedAt(*v)
+	}
+	return _c
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (_c *MfaQrCreate) SetLockedUntil(v time.Time) *MfaQrCreate {
+	_c.mutation.SetLockedUntil(v)
+	return _c
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableLockedUntil(v *time.Time) *MfaQrCreate {
+	if v != nil {
+		_c.SetLockedUntil(*v)
+	}
+	return _c
+}
+
 // SetSecret sets the "secret" field.
 func (_c *MfaQrCreate) SetSecret(v []byte) *MfaQrCreate {
 	_c.mutation.SetSecret(v)
@@ -76,6 +119,160 @@ func (_c *MfaQrCreate) SetUserID(v binid.BinId) *MfaQrCreate {
 	return _c
 }
 
+// SetDataKeyID sets the "data_key_id" field.
+func (_c *MfaQrCreate) SetDataKeyID(v binid.BinId) *MfaQrCreate {
+	_c.mutation.SetDataKeyID(v)
+	return _c
+}
+
+// SetNillableDataKeyID sets the "data_key_id" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableDataKeyID(v *binid.BinId) *MfaQrCreate {
+	if v != nil {
+		_c.SetDataKeyID(*v)
+	}
+	return _c
+}
+
+// SetSecretBound sets the "secret_bound" field.
+func (_c *MfaQrCreate) SetSecretBound(v bool) *MfaQrCreate {
+	_c.mutation.SetSecretBound(v)
+	return _c
+}
+
+// SetNillableSecretBound sets the "secret_bound" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableSecretBound(v *bool) *MfaQrCreate {
+	if v != nil {
+		_c.SetSecretBound(*v)
+	}
+	return _c
+}
+
+// SetType sets the "type" field.
+func (_c *MfaQrCreate) SetType(v mfaqr.Type) *MfaQrCreate {
+	_c.mutation.SetType(v)
+	return _c
+}
+
+// SetNillableType sets the "type" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableType(v *mfaqr.Type) *MfaQrCreate {
+	if v != nil {
+		_c.SetType(*v)
+	}
+	return _c
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (_c *MfaQrCreate) SetAlgorithm(v mfaqr.Algorithm) *MfaQrCreate {
+	_c.mutation.SetAlgorithm(v)
+	return _c
+}
+
+// SetNillableAlgorithm sets the "algorithm" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableAlgorithm(v *mfaqr.Algorithm) *MfaQrCreate {
+	if v != nil {
+		_c.SetAlgorithm(*v)
+	}
+	return _c
+}
+
+// SetDigits sets the "digits" field.
+func (_c *MfaQrCreate) SetDigits(v uint8) *MfaQrCreate {
+	_c.mutation.SetDigits(v)
+	return _c
+}
+
+// SetNillableDigits sets the "digits" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableDigits(v *uint8) *MfaQrCreate {
+	if v != nil {
+		_c.SetDigits(*v)
+	}
+	return _c
+}
+
+// SetPeriod sets the "period" field.
+func (_c *MfaQrCreate) SetPeriod(v uint32) *MfaQrCreate {
+	_c.mutation.SetPeriod(v)
+	return _c
+}
+
+// SetNillablePeriod sets the "period" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillablePeriod(v *uint32) *MfaQrCreate {
+	if v != nil {
+		_c.SetPeriod(*v)
+	}
+	return _c
+}
+
+// SetCounter sets the "counter" field.
+func (_c *MfaQrCreate) SetCounter(v uint64) *MfaQrCreate {
+	_c.mutation.SetCounter(v)
+	return _c
+}
+
+// SetNillableCounter sets the "counter" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableCounter(v *uint64) *MfaQrCreate {
+	if v != nil {
+		_c.SetCounter(*v)
+	}
+	return _c
+}
+
+// SetLastStep sets the "last_step" field.
+func (_c *MfaQrCreate) SetLastStep(v uint64) *MfaQrCreate {
+	_c.mutation.SetLastStep(v)
+	return _c
+}
+
+// SetNillableLastStep sets the "last_step" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableLastStep(v *uint64) *MfaQrCreate {
+	if v != nil {
+		_c.SetLastStep(*v)
+	}
+	return _c
+}
+
+// SetStatus sets the "status" field.
+func (_c *MfaQrCreate) SetStatus(v mfaqr.Status) *MfaQrCreate {
+	_c.mutation.SetStatus(v)
+	return _c
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableStatus(v *mfaqr.Status) *MfaQrCreate {
+	if v != nil {
+		_c.SetStatus(*v)
+	}
+	return _c
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_c *MfaQrCreate) SetExpiresAt(v time.Time) *MfaQrCreate {
+	_c.mutation.SetExpiresAt(v)
+	return _c
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableExpiresAt(v *time.Time) *MfaQrCreate {
+	if v != nil {
+		_c.SetExpiresAt(*v)
+	}
+	return _c
+}
+
+// SetActivatedAt sets the "activated_at" field.
+func (_c *MfaQrCreate) SetActivatedAt(v time.Time) *MfaQrCreate {
+	_c.mutation.SetActivatedAt(v)
+	return _c
+}
+
+// SetNillableActivatedAt sets the "activated_at" field if the given value is not nil.
+func (_c *MfaQrCreate) SetNillableActivatedAt(v *time.Time) *MfaQrCreate {
+	if v != nil {
+		_c.SetActivatedAt(*v)
+	}
+	return _c
+}
+
 // SetID sets the "id" field.
 func (_c *MfaQrCreate) SetID(v binid.BinId) *MfaQrCreate {
 	_c.mutation.SetID(v)
@@ -87,6 +284,11 @@ func (_c *MfaQrCreate) SetUser(v *User) *MfaQrCreate {
 	return _c.SetUserID(v.ID)
 }
 
+// SetDataKey sets the "data_key" edge to the DataKey entity.
+func (_c *MfaQrCreate) SetDataKey(v *DataKey) *MfaQrCreate {
+	return _c.SetDataKeyID(v.ID)
+}
+
 // Mutation returns the MfaQrMutation object of the builder.
 func (_c *MfaQrCreate) Mutation() *MfaQrMutation {
 	return _c.mutation
@@ -130,6 +332,42 @@ func (_c *MfaQrCreate) defaults() {
 		v := mfaqr.DefaultUpdatedAt()
 		_c.mutation.SetUpdatedAt(v)
 	}
+	if _, ok := _c.mutation.FailedAttempts(); !ok {
+		v := mfaqr.DefaultFailedAttempts
+		_c.mutation.SetFailedAttempts(v)
+	}
+	if _, ok := _c.mutation.SecretBound(); !ok {
+		v := mfaqr.DefaultSecretBound
+		_c.mutation.SetSecretBound(v)
+	}
+	if _, ok := _c.mutation.GetType(); !ok {
+		v := mfaqr.DefaultType
+		_c.mutation.SetType(v)
+	}
+	if _, ok := _c.mutation.Algorithm(); !ok {
+		v := mfaqr.DefaultAlgorithm
+		_c.mutation.SetAlgorithm(v)
+	}
+	if _, ok := _c.mutation.Digits(); !ok {
+		v := mfaqr.DefaultDigits
+		_c.mutation.SetDigits(v)
+	}
+	if _, ok := _c.mutation.Period(); !ok {
+		v := mfaqr.DefaultPeriod
+		_c.mutation.SetPeriod(v)
+	}
+	if _, ok := _c.mutation.Counter(); !ok {
+		v := mfaqr.DefaultCounter
+		_c.mutation.SetCounter(v)
+	}
+	if _, ok := _c.mutation.LastStep(); !ok {
+		v := mfaqr.DefaultLastStep
+		_c.mutation.SetLastStep(v)
+	}
+	if _, ok := _c.mutation.Status(); !ok {
+		v := mfaqr.DefaultStatus
+		_c.mutation.SetStatus(v)
+	}
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -140,6 +378,9 @@ func (_c *MfaQrCreate) check() error {
 	if _, ok := _c.mutation.UpdatedAt(); !ok {
 		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "MfaQr.updated_at"`)}
 	}
+	if _, ok := _c.mutation.FailedAttempts(); !ok {
+		return &ValidationError{Name: "failed_attempts", err: errors.New(`ent: missing required field "MfaQr.failed_attempts"`)}
+	}
 	if _, ok := _c.mutation.Secret(); !ok {
 		return &ValidationError{Name: "secret", err: errors.New(`ent: missing required field "MfaQr.secret"`)}
 	}
@@ -151,6 +392,55 @@ func (_c *MfaQrCreate) check() error {
 	if _, ok := _c.mutation.UserID(); !ok {
 		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "MfaQr.user_id"`)}
 	}
+	if _, ok := _c.mutation.SecretBound(); !ok {
+		return &ValidationError{Name: "secret_bound", err: errors.New(`ent: missing required field "MfaQr.secret_bound"`)}
+	}
+	if _, ok := _c.mutation.GetType(); !ok {
+		return &ValidationError{Name: "type", err: errors.New(`ent: missing required field "MfaQr.type"`)}
+	}
+	if v, ok := _c.mutation.GetType(); ok {
+		if err := mfaqr.TypeValidator(v); err != nil {
+			return &ValidationError{Name: "type", err: fmt.Errorf(`ent: validator failed for field "MfaQr.type": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Algorithm(); !ok {
+		return &ValidationError{Name: "algorithm", err: errors.New(`ent: missing required field "MfaQr.algorithm"`)}
+	}
+	if v, ok := _c.mutation.Algorithm(); ok {
+		if err := mfaqr.AlgorithmValidator(v); err != nil {
+			return &ValidationError{Name: "algorithm", err: fmt.Errorf(`ent: validator failed for field "MfaQr.algorithm": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Digits(); !ok {
+		return &ValidationError{Name: "digits", err: errors.New(`ent: missing required field "MfaQr.digits"`)}
+	}
+	if v, ok := _c.mutation.Digits(); ok {
+		if err := mfaqr.DigitsValidator(v); err != nil {
+			return &ValidationError{Name: "digits", err: fmt.Errorf(`ent: validator failed for field "MfaQr.digits": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Period(); !ok {
+		return &ValidationError{Name: "period", err: errors.New(`ent: missing required field "MfaQr.period"`)}
+	}
+	if v, ok := _c.mutation.Period(); ok {
+		if err := mfaqr.PeriodValidator(v); err != nil {
+			return &ValidationError{Name: "period", err: fmt.Errorf(`ent: validator failed for field "MfaQr.period": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Counter(); !ok {
+		return &ValidationError{Name: "counter", err: errors.New(`ent: missing required field "MfaQr.counter"`)}
+	}
+	if _, ok := _c.mutation.LastStep(); !ok {
+		return &ValidationError{Name: "last_step", err: errors.New(`ent: missing required field "MfaQr.last_step"`)}
+	}
+	if _, ok := _c.mutation.Status(); !ok {
+		return &ValidationError{Name: "status", err: errors.New(`ent: missing required field "MfaQr.status"`)}
+	}
+	if v, ok := _c.mutation.Status(); ok {
+		if err := mfaqr.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "MfaQr.status": %w`, err)}
+		}
+	}
 	if len(_c.mutation.UserIDs()) == 0 {
 		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "MfaQr.user"`)}
 	}
@@ -201,10 +491,62 @@ func (_c *MfaQrCreate) createSpec() (*MfaQr, *sqlgraph.CreateSpec) {
 		_spec.SetField(mfaqr.FieldDeletedAt, field.TypeTime, value)
 		_node.DeletedAt = &value
 	}
+	if value, ok := _c.mutation.FailedAttempts(); ok {
+		_spec.SetField(mfaqr.FieldFailedAttempts, field.TypeUint32, value)
+		_node.FailedAttempts = value
+	}
+	if value, ok := _c.mutation.LastFailedAt(); ok {
+		_spec.SetField(mfaqr.FieldLastFailedAt, field.TypeTime, value)
+		_node.LastFailedAt = &value
+	}
+	if value, ok := _c.mutation.LockedUntil(); ok {
+		_spec.SetField(mfaqr.FieldLockedUntil, field.TypeTime, value)
+		_node.LockedUntil = &value
+	}
 	if value, ok := _c.mutation.Secret(); ok {
 		_spec.SetField(mfaqr.FieldSecret, field.TypeBytes, value)
 		_node.Secret = value
 	}
+	if value, ok := _c.mutation.SecretBound(); ok {
+		_spec.SetField(mfaqr.FieldSecretBound, field.TypeBool, value)
+		_node.SecretBound = value
+	}
+	if value, ok := _c.mutation.GetType(); ok {
+		_spec.SetField(mfaqr.FieldType, field.TypeEnum, value)
+		_node.Type = value
+	}
+	if value, ok := _c.mutation.Algorithm(); ok {
+		_spec.SetField(mfaqr.FieldAlgorithm, field.TypeEnum, value)
+		_node.Algorithm = value
+	}
+	if value, ok := _c.mutation.Digits(); ok {
+		_spec.SetField(mfaqr.FieldDigits, field.TypeUint8, value)
+		_node.Digits = value
+	}
+	if value, ok := _c.mutation.Period(); ok {
+		_spec.SetField(mfaqr.FieldPeriod, field.TypeUint32, value)
+		_node.Period = value
+	}
+	if value, ok := _c.mutation.Counter(); ok {
+		_spec.SetField(mfaqr.FieldCounter, field.TypeUint64, value)
+		_node.Counter = value
+	}
+	if value, ok := _c.mutation.LastStep(); ok {
+		_spec.SetField(mfaqr.FieldLastStep, field.TypeUint64, value)
+		_node.LastStep = value
+	}
+	if value, ok := _c.mutation.Status(); ok {
+		_spec.SetField(mfaqr.FieldStatus, field.TypeEnum, value)
+		_node.Status = value
+	}
+	if value, ok := _c.mutation.ExpiresAt(); ok {
+		_spec.SetField(mfaqr.FieldExpiresAt, field.TypeTime, value)
+		_node.ExpiresAt = &value
+	}
+	if value, ok := _c.mutation.ActivatedAt(); ok {
+		_spec.SetField(mfaqr.FieldActivatedAt, field.TypeTime, value)
+		_node.ActivatedAt = &value
+	}
 	if nodes := _c.mutation.UserIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -222,6 +564,23 @@ func (_c *MfaQrCreate) createSpec() (*MfaQr, *sqlgraph.CreateSpec) {
 		_node.UserID = nodes[0]
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := _c.mutation.DataKeyIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   mfaqr.DataKeyTable,
+			Columns: []string{mfaqr.DataKeyColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.DataKeyID = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
 	"nidan-kai/ent/mfaqr"
 	"nidan-kai/ent/predicate"
 	"nidan-kai/ent/user"
@@ -20,11 +21,12 @@ import (
 // MfaQrQuery is the builder for querying MfaQr entities.
 type MfaQrQuery struct {
 	config
-	ctx        *QueryContext
-	order      []mfaqr.OrderOption
-	inters     []Interceptor
-	predicates []predicate.MfaQr
-	withUser   *UserQuery
+	ctx         *QueryContext
+	order       []mfaqr.OrderOption
+	inters      []Interceptor
+	predicates  []predicate.MfaQr
+	withUser    *UserQuery
+	withDataKey *DataKeyQuery
 	// intermediate query (i.e. traversal path).
 	sql  *sql.Selector
 	path func(context.Context) (*sql.Selector, error)
@@ -83,6 +85,28 @@ func (_q *MfaQrQuery) QueryUser() *UserQuery {
 	return query
 }
 
+// QueryDataKey chains the current query on the "data_key" edge.
+func (_q *MfaQrQuery) QueryDataKey() *DataKeyQuery {
+	query := (&DataKeyClient{config: _q.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := _q.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := _q.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(mfaqr.Table, mfaqr.FieldID, selector),
+			sqlgraph.To(datakey.Table, datakey.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, mfaqr.DataKeyTable, mfaqr.DataKeyColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(_q.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
 // First returns the first MfaQr entity from the query.
 // Returns a *NotFoundError when no MfaQr was found.
 func (_q *MfaQrQuery) First(ctx context.Context) (*MfaQr, error) {
@@ -270,12 +294,13 @@ func (_q *MfaQrQuery) Clone() *MfaQrQuery {
 		return nil
 	}
 	return &MfaQrQuery{
-		config:     _q.config,
-		ctx:        _q.ctx.Clone(),
-		order:      append([]mfaqr.OrderOption{}, _q.order...),
-		inters:     append([]Interceptor{}, _q.inters...),
-		predicates: append([]predicate.MfaQr{}, _q.predicates...),
-		withUser:   _q.withUser.Clone(),
+		config:      _q.config,
+		ctx:         _q.ctx.Clone(),
+		order:       append([]mfaqr.OrderOption{}, _q.order...),
+		inters:      append([]Interceptor{}, _q.inters...),
+		predicates:  append([]predicate.MfaQr{}, _q.predicates...),
+		withUser:    _q.withUser.Clone(),
+		withDataKey: _q.withDataKey.Clone(),
 		// clone intermediate query.
 		sql:  _q.sql.Clone(),
 		path: _q.path,
@@ -293,6 +318,17 @@ func (_q *MfaQrQuery) WithUser(opts ...func(*UserQuery)) *MfaQrQuery {
 	return _q
 }
 
+// WithDataKey tells the query-builder to eager-load the nodes that are connected to
+// the "data_key" edge. The optional arguments are used to configure the query builder of the edge.
+func (_q *MfaQrQuery) WithDataKey(opts ...func(*DataKeyQuery)) *MfaQrQuery {
+	query := (&DataKeyClient{config: _q.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	_q.withDataKey = query
+	return _q
+}
+
 // GroupBy is used to group vertices by one or more fields/columns.
 // It is often used with aggregate functions, like: count, max, mean, min, sum.
 //
@@ -371,8 +407,9 @@ func (_q *MfaQrQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*MfaQr,
 	var (
 		nodes       = []*MfaQr{}
 		_spec       = _q.querySpec()
-		loadedTypes = [1]bool{
+		loadedTypes = [2]bool{
 			_q.withUser != nil,
+			_q.withDataKey != nil,
 		}
 	)
 	_spec.ScanValues = func(columns []string) ([]any, error) {
@@ -399,6 +436,12 @@ func (_q *MfaQrQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*MfaQr,
 			return nil, err
 		}
 	}
+	if query := _q.withDataKey; query != nil {
+		if err := _q.loadDataKey(ctx, query, nodes, nil,
+			func(n *MfaQr, e *DataKey) { n.Edges.DataKey = e }); err != nil {
+			return nil, err
+		}
+	}
 	return nodes, nil
 }
 
@@ -431,6 +474,38 @@ func (_q *MfaQrQuery) loadUser(ctx context.Context, query *UserQuery, nodes []*M
 	}
 	return nil
 }
+func (_q *MfaQrQuery) loadDataKey(ctx context.Context, query *DataKeyQuery, nodes []*MfaQr, init func(*MfaQr), assign func(*MfaQr, *DataKey)) error {
+	ids := make([]binid.BinId, 0, len(nodes))
+	nodeids := make(map[binid.BinId][]*MfaQr)
+	for i := range nodes {
+		if nodes[i].DataKeyID == nil {
+			continue
+		}
+		fk := *nodes[i].DataKeyID
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(datakey.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "data_key_id" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
 
 func (_q *MfaQrQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := _q.querySpec()
@@ -460,6 +535,9 @@ func (_q *MfaQrQuery) querySpec() *sqlgraph.QuerySpec {
 		if _q.withUser != nil {
 			_spec.Node.AddColumnOnce(mfaqr.FieldUserID)
 		}
+		if _q.withDataKey != nil {
+			_spec.Node.AddColumnOnce(mfaqr.FieldDataKeyID)
+		}
 	}
 	if ps := _q.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
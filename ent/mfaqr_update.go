@@ -6,6 +6,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
 	"nidan-kai/ent/mfaqr"
 	"nidan-kai/ent/predicate"
 	"time"
@@ -54,11 +56,219 @@ func (_u *MfaQrUpdate) ClearDeletedAt() *MfaQrUpdate {
 	return _u
 }
 
+// SetFailedAttempts sets the "failed_attempts" field.
+func (_u *MfaQrUpdate) SetFailedAttempts(v uint32) *MfaQrUpdate {
+	_u.mutation.ResetFailedAttempts()
+	_u.mutation.SetFailedAttempts(v)
+	return _u
+}
+
+// SetNillableFailedAttempts sets the "failed_attempts" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableFailedAttempts(v *uint32) *MfaQrUpdate {
+	if v != nil {
+		_u.SetFailedAttempts(*v)
+	}
+	return _u
+}
+
+// AddFailedAttempts adds value to the "failed_attempts" field.
+func (_u *MfaQrUpdate) AddFailedAttempts(v int32) *MfaQrUpdate {
+	_u.mutation.AddFailedAttempts(v)
+	return _u
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (_u *MfaQrUpdate) SetLastFailedAt(v time.Time) *MfaQrUpdate {
+	_u.mutation.SetLastFailedAt(v)
+	return _u
+}
+
+// SetNillableLastFailedAt sets the "last_failed_at" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableLastFailedAt(v *time.Time) *MfaQrUpdate {
+	if v != nil {
+		_u.SetLastFailedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastFailedAt clears the value of the "last_failed_at" field.
+func (_u *MfaQrUpdate) ClearLastFailedAt() *MfaQrUpdate {
+	_u.mutation.ClearLastFailedAt()
+	return _u
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (_u *MfaQrUpdate) SetLockedUntil(v time.Time) *MfaQrUpdate {
+	_u.mutation.SetLockedUntil(v)
+	return _u
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableLockedUntil(v *time.Time) *MfaQrUpdate {
+	if v != nil {
+		_u.SetLockedUntil(*v)
+	}
+	return _u
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (_u *MfaQrUpdate) ClearLockedUntil() *MfaQrUpdate {
+	_u.mutation.ClearLockedUntil()
+	return _u
+}
+
+// SetSecret sets the "secret" field.
+func (_u *MfaQrUpdate) SetSecret(v []byte) *MfaQrUpdate {
+	_u.mutation.SetSecret(v)
+	return _u
+}
+
+// SetDataKeyID sets the "data_key_id" field.
+func (_u *MfaQrUpdate) SetDataKeyID(v binid.BinId) *MfaQrUpdate {
+	_u.mutation.SetDataKeyID(v)
+	return _u
+}
+
+// SetNillableDataKeyID sets the "data_key_id" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableDataKeyID(v *binid.BinId) *MfaQrUpdate {
+	if v != nil {
+		_u.SetDataKeyID(*v)
+	}
+	return _u
+}
+
+// ClearDataKeyID clears the value of the "data_key_id" field.
+func (_u *MfaQrUpdate) ClearDataKeyID() *MfaQrUpdate {
+	_u.mutation.ClearDataKeyID()
+	return _u
+}
+
+// SetSecretBound sets the "secret_bound" field.
+func (_u *MfaQrUpdate) SetSecretBound(v bool) *MfaQrUpdate {
+	_u.mutation.SetSecretBound(v)
+	return _u
+}
+
+// SetNillableSecretBound sets the "secret_bound" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableSecretBound(v *bool) *MfaQrUpdate {
+	if v != nil {
+		_u.SetSecretBound(*v)
+	}
+	return _u
+}
+
+// SetCounter sets the "counter" field.
+func (_u *MfaQrUpdate) SetCounter(v uint64) *MfaQrUpdate {
+	_u.mutation.ResetCounter()
+	_u.mutation.SetCounter(v)
+	return _u
+}
+
+// SetNillableCounter sets the "counter" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableCounter(v *uint64) *MfaQrUpdate {
+	if v != nil {
+		_u.SetCounter(*v)
+	}
+	return _u
+}
+
+// AddCounter adds value to the "counter" field.
+func (_u *MfaQrUpdate) AddCounter(v int64) *MfaQrUpdate {
+	_u.mutation.AddCounter(v)
+	return _u
+}
+
+// SetLastStep sets the "last_step" field.
+func (_u *MfaQrUpdate) SetLastStep(v uint64) *MfaQrUpdate {
+	_u.mutation.ResetLastStep()
+	_u.mutation.SetLastStep(v)
+	return _u
+}
+
+// SetNillableLastStep sets the "last_step" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableLastStep(v *uint64) *MfaQrUpdate {
+	if v != nil {
+		_u.SetLastStep(*v)
+	}
+	return _u
+}
+
+// AddLastStep adds value to the "last_step" field.
+func (_u *MfaQrUpdate) AddLastStep(v int64) *MfaQrUpdate {
+	_u.mutation.AddLastStep(v)
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *MfaQrUpdate) SetStatus(v mfaqr.Status) *MfaQrUpdate {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableStatus(v *mfaqr.Status) *MfaQrUpdate {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *MfaQrUpdate) SetExpiresAt(v time.Time) *MfaQrUpdate {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableExpiresAt(v *time.Time) *MfaQrUpdate {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (_u *MfaQrUpdate) ClearExpiresAt() *MfaQrUpdate {
+	_u.mutation.ClearExpiresAt()
+	return _u
+}
+
+// SetActivatedAt sets the "activated_at" field.
+func (_u *MfaQrUpdate) SetActivatedAt(v time.Time) *MfaQrUpdate {
+	_u.mutation.SetActivatedAt(v)
+	return _u
+}
+
+// SetNillableActivatedAt sets the "activated_at" field if the given value is not nil.
+func (_u *MfaQrUpdate) SetNillableActivatedAt(v *time.Time) *MfaQrUpdate {
+	if v != nil {
+		_u.SetActivatedAt(*v)
+	}
+	return _u
+}
+
+// ClearActivatedAt clears the value of the "activated_at" field.
+func (_u *MfaQrUpdate) ClearActivatedAt() *MfaQrUpdate {
+	_u.mutation.ClearActivatedAt()
+	return _u
+}
+
+// SetDataKey sets the "data_key" edge to the DataKey entity.
+func (_u *MfaQrUpdate) SetDataKey(v *DataKey) *MfaQrUpdate {
+	return _u.SetDataKeyID(v.ID)
+}
+
 // Mutation returns the MfaQrMutation object of the builder.
 func (_u *MfaQrUpdate) Mutation() *MfaQrMutation {
 	return _u.mutation
 }
 
+// ClearDataKey clears the "data_key" edge to the DataKey entity.
+func (_u *MfaQrUpdate) ClearDataKey() *MfaQrUpdate {
+	_u.mutation.ClearDataKey()
+	return _u
+}
+
 // Save executes the query and returns the number of nodes affected by the update operation.
 func (_u *MfaQrUpdate) Save(ctx context.Context) (int, error) {
 	_u.defaults()
@@ -97,6 +307,16 @@ func (_u *MfaQrUpdate) defaults() {
 
 // check runs all checks and user-defined validators on the builder.
 func (_u *MfaQrUpdate) check() error {
+	if v, ok := _u.mutation.Secret(); ok {
+		if err := mfaqr.SecretValidator(v); err != nil {
+			return &ValidationError{Name: "secret", err: fmt.Errorf(`ent: validator failed for field "MfaQr.secret": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Status(); ok {
+		if err := mfaqr.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "MfaQr.status": %w`, err)}
+		}
+	}
 	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "MfaQr.user"`)
 	}
@@ -124,6 +344,86 @@ func (_u *MfaQrUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.DeletedAtCleared() {
 		_spec.ClearField(mfaqr.FieldDeletedAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.FailedAttempts(); ok {
+		_spec.SetField(mfaqr.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.AddedFailedAttempts(); ok {
+		_spec.AddField(mfaqr.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.LastFailedAt(); ok {
+		_spec.SetField(mfaqr.FieldLastFailedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastFailedAtCleared() {
+		_spec.ClearField(mfaqr.FieldLastFailedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LockedUntil(); ok {
+		_spec.SetField(mfaqr.FieldLockedUntil, field.TypeTime, value)
+	}
+	if _u.mutation.LockedUntilCleared() {
+		_spec.ClearField(mfaqr.FieldLockedUntil, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Secret(); ok {
+		_spec.SetField(mfaqr.FieldSecret, field.TypeBytes, value)
+	}
+	if value, ok := _u.mutation.SecretBound(); ok {
+		_spec.SetField(mfaqr.FieldSecretBound, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.Counter(); ok {
+		_spec.SetField(mfaqr.FieldCounter, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.AddedCounter(); ok {
+		_spec.AddField(mfaqr.FieldCounter, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.LastStep(); ok {
+		_spec.SetField(mfaqr.FieldLastStep, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.AddedLastStep(); ok {
+		_spec.AddField(mfaqr.FieldLastStep, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(mfaqr.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(mfaqr.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.ExpiresAtCleared() {
+		_spec.ClearField(mfaqr.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.ActivatedAt(); ok {
+		_spec.SetField(mfaqr.FieldActivatedAt, field.TypeTime, value)
+	}
+	if _u.mutation.ActivatedAtCleared() {
+		_spec.ClearField(mfaqr.FieldActivatedAt, field.TypeTime)
+	}
+	if _u.mutation.DataKeyCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   mfaqr.DataKeyTable,
+			Columns: []string{mfaqr.DataKeyColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.DataKeyIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   mfaqr.DataKeyTable,
+			Columns: []string{mfaqr.DataKeyColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{mfaqr.Label}
@@ -170,11 +470,219 @@ func (_u *MfaQrUpdateOne) ClearDeletedAt() *MfaQrUpdateOne {
 	return _u
 }
 
+// SetFailedAttempts sets the "failed_attempts" field.
+func (_u *MfaQrUpdateOne) SetFailedAttempts(v uint32) *MfaQrUpdateOne {
+	_u.mutation.ResetFailedAttempts()
+	_u.mutation.SetFailedAttempts(v)
+	return _u
+}
+
+// SetNillableFailedAttempts sets the "failed_attempts" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableFailedAttempts(v *uint32) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetFailedAttempts(*v)
+	}
+	return _u
+}
+
+// AddFailedAttempts adds value to the "failed_attempts" field.
+func (_u *MfaQrUpdateOne) AddFailedAttempts(v int32) *MfaQrUpdateOne {
+	_u.mutation.AddFailedAttempts(v)
+	return _u
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (_u *MfaQrUpdateOne) SetLastFailedAt(v time.Time) *MfaQrUpdateOne {
+	_u.mutation.SetLastFailedAt(v)
+	return _u
+}
+
+// SetNillableLastFailedAt sets the "last_failed_at" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableLastFailedAt(v *time.Time) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetLastFailedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastFailedAt clears the value of the "last_failed_at" field.
+func (_u *MfaQrUpdateOne) ClearLastFailedAt() *MfaQrUpdateOne {
+	_u.mutation.ClearLastFailedAt()
+	return _u
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (_u *MfaQrUpdateOne) SetLockedUntil(v time.Time) *MfaQrUpdateOne {
+	_u.mutation.SetLockedUntil(v)
+	return _u
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableLockedUntil(v *time.Time) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetLockedUntil(*v)
+	}
+	return _u
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (_u *MfaQrUpdateOne) ClearLockedUntil() *MfaQrUpdateOne {
+	_u.mutation.ClearLockedUntil()
+	return _u
+}
+
+// SetSecret sets the "secret" field.
+func (_u *MfaQrUpdateOne) SetSecret(v []byte) *MfaQrUpdateOne {
+	_u.mutation.SetSecret(v)
+	return _u
+}
+
+// SetDataKeyID sets the "data_key_id" field.
+func (_u *MfaQrUpdateOne) SetDataKeyID(v binid.BinId) *MfaQrUpdateOne {
+	_u.mutation.SetDataKeyID(v)
+	return _u
+}
+
+// SetNillableDataKeyID sets the "data_key_id" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableDataKeyID(v *binid.BinId) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetDataKeyID(*v)
+	}
+	return _u
+}
+
+// ClearDataKeyID clears the value of the "data_key_id" field.
+func (_u *MfaQrUpdateOne) ClearDataKeyID() *MfaQrUpdateOne {
+	_u.mutation.ClearDataKeyID()
+	return _u
+}
+
+// SetSecretBound sets the "secret_bound" field.
+func (_u *MfaQrUpdateOne) SetSecretBound(v bool) *MfaQrUpdateOne {
+	_u.mutation.SetSecretBound(v)
+	return _u
+}
+
+// SetNillableSecretBound sets the "secret_bound" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableSecretBound(v *bool) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetSecretBound(*v)
+	}
+	return _u
+}
+
+// SetCounter sets the "counter" field.
+func (_u *MfaQrUpdateOne) SetCounter(v uint64) *MfaQrUpdateOne {
+	_u.mutation.ResetCounter()
+	_u.mutation.SetCounter(v)
+	return _u
+}
+
+// SetNillableCounter sets the "counter" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableCounter(v *uint64) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetCounter(*v)
+	}
+	return _u
+}
+
+// AddCounter adds value to the "counter" field.
+func (_u *MfaQrUpdateOne) AddCounter(v int64) *MfaQrUpdateOne {
+	_u.mutation.AddCounter(v)
+	return _u
+}
+
+// SetLastStep sets the "last_step" field.
+func (_u *MfaQrUpdateOne) SetLastStep(v uint64) *MfaQrUpdateOne {
+	_u.mutation.ResetLastStep()
+	_u.mutation.SetLastStep(v)
+	return _u
+}
+
+// SetNillableLastStep sets the "last_step" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableLastStep(v *uint64) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetLastStep(*v)
+	}
+	return _u
+}
+
+// AddLastStep adds value to the "last_step" field.
+func (_u *MfaQrUpdateOne) AddLastStep(v int64) *MfaQrUpdateOne {
+	_u.mutation.AddLastStep(v)
+	return _u
+}
+
+// SetStatus sets the "status" field.
+func (_u *MfaQrUpdateOne) SetStatus(v mfaqr.Status) *MfaQrUpdateOne {
+	_u.mutation.SetStatus(v)
+	return _u
+}
+
+// SetNillableStatus sets the "status" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableStatus(v *mfaqr.Status) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetStatus(*v)
+	}
+	return _u
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (_u *MfaQrUpdateOne) SetExpiresAt(v time.Time) *MfaQrUpdateOne {
+	_u.mutation.SetExpiresAt(v)
+	return _u
+}
+
+// SetNillableExpiresAt sets the "expires_at" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableExpiresAt(v *time.Time) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetExpiresAt(*v)
+	}
+	return _u
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (_u *MfaQrUpdateOne) ClearExpiresAt() *MfaQrUpdateOne {
+	_u.mutation.ClearExpiresAt()
+	return _u
+}
+
+// SetActivatedAt sets the "activated_at" field.
+func (_u *MfaQrUpdateOne) SetActivatedAt(v time.Time) *MfaQrUpdateOne {
+	_u.mutation.SetActivatedAt(v)
+	return _u
+}
+
+// SetNillableActivatedAt sets the "activated_at" field if the given value is not nil.
+func (_u *MfaQrUpdateOne) SetNillableActivatedAt(v *time.Time) *MfaQrUpdateOne {
+	if v != nil {
+		_u.SetActivatedAt(*v)
+	}
+	return _u
+}
+
+// ClearActivatedAt clears the value of the "activated_at" field.
+func (_u *MfaQrUpdateOne) ClearActivatedAt() *MfaQrUpdateOne {
+	_u.mutation.ClearActivatedAt()
+	return _u
+}
+
+// SetDataKey sets the "data_key" edge to the DataKey entity.
+func (_u *MfaQrUpdateOne) SetDataKey(v *DataKey) *MfaQrUpdateOne {
+	return _u.SetDataKeyID(v.ID)
+}
+
 // Mutation returns the MfaQrMutation object of the builder.
 func (_u *MfaQrUpdateOne) Mutation() *MfaQrMutation {
 	return _u.mutation
 }
 
+// ClearDataKey clears the "data_key" edge to the DataKey entity.
+func (_u *MfaQrUpdateOne) ClearDataKey() *MfaQrUpdateOne {
+	_u.mutation.ClearDataKey()
+	return _u
+}
+
 // Where appends a list predicates to the MfaQrUpdate builder.
 func (_u *MfaQrUpdateOne) Where(ps ...predicate.MfaQr) *MfaQrUpdateOne {
 	_u.mutation.Where(ps...)
@@ -226,6 +734,16 @@ func (_u *MfaQrUpdateOne) defaults() {
 
 // check runs all checks and user-defined validators on the builder.
 func (_u *MfaQrUpdateOne) check() error {
+	if v, ok := _u.mutation.Secret(); ok {
+		if err := mfaqr.SecretValidator(v); err != nil {
+			return &ValidationError{Name: "secret", err: fmt.Errorf(`ent: validator failed for field "MfaQr.secret": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Status(); ok {
+		if err := mfaqr.StatusValidator(v); err != nil {
+			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "MfaQr.status": %w`, err)}
+		}
+	}
 	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "MfaQr.user"`)
 	}
@@ -270,6 +788,86 @@ func (_u *MfaQrUpdateOne) sqlSave(ctx context.Context) (_node *MfaQr, err error)
 	if _u.mutation.DeletedAtCleared() {
 		_spec.ClearField(mfaqr.FieldDeletedAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.FailedAttempts(); ok {
+		_spec.SetField(mfaqr.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.AddedFailedAttempts(); ok {
+		_spec.AddField(mfaqr.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.LastFailedAt(); ok {
+		_spec.SetField(mfaqr.FieldLastFailedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastFailedAtCleared() {
+		_spec.ClearField(mfaqr.FieldLastFailedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LockedUntil(); ok {
+		_spec.SetField(mfaqr.FieldLockedUntil, field.TypeTime, value)
+	}
+	if _u.mutation.LockedUntilCleared() {
+		_spec.ClearField(mfaqr.FieldLockedUntil, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Secret(); ok {
+		_spec.SetField(mfaqr.FieldSecret, field.TypeBytes, value)
+	}
+	if value, ok := _u.mutation.SecretBound(); ok {
+		_spec.SetField(mfaqr.FieldSecretBound, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.Counter(); ok {
+		_spec.SetField(mfaqr.FieldCounter, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.AddedCounter(); ok {
+		_spec.AddField(mfaqr.FieldCounter, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.LastStep(); ok {
+		_spec.SetField(mfaqr.FieldLastStep, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.AddedLastStep(); ok {
+		_spec.AddField(mfaqr.FieldLastStep, field.TypeUint64, value)
+	}
+	if value, ok := _u.mutation.Status(); ok {
+		_spec.SetField(mfaqr.FieldStatus, field.TypeEnum, value)
+	}
+	if value, ok := _u.mutation.ExpiresAt(); ok {
+		_spec.SetField(mfaqr.FieldExpiresAt, field.TypeTime, value)
+	}
+	if _u.mutation.ExpiresAtCleared() {
+		_spec.ClearField(mfaqr.FieldExpiresAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.ActivatedAt(); ok {
+		_spec.SetField(mfaqr.FieldActivatedAt, field.TypeTime, value)
+	}
+	if _u.mutation.ActivatedAtCleared() {
+		_spec.ClearField(mfaqr.FieldActivatedAt, field.TypeTime)
+	}
+	if _u.mutation.DataKeyCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   mfaqr.DataKeyTable,
+			Columns: []string{mfaqr.DataKeyColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := _u.mutation.DataKeyIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   mfaqr.DataKeyTable,
+			Columns: []string{mfaqr.DataKeyColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
 	_node = &MfaQr{config: _u.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
@@ -9,13 +9,75 @@ import (
 )
 
 var (
+	// DataKeysColumns holds the columns for the "data_keys" table.
+	DataKeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Unique: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "deleted_at", Type: field.TypeTime, Nullable: true},
+		{Name: "wrapped_key", Type: field.TypeBytes, Size: 256, SchemaType: map[string]string{"mysql": "varbinary(256)"}},
+		{Name: "user_id", Type: field.TypeUUID, Unique: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
+	}
+	// DataKeysTable holds the schema information for the "data_keys" table.
+	DataKeysTable = &schema.Table{
+		Name:       "data_keys",
+		Columns:    DataKeysColumns,
+		PrimaryKey: []*schema.Column{DataKeysColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "data_keys_users_data_key",
+				Columns:    []*schema.Column{DataKeysColumns[5]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "datakey_user_id",
+				Unique:  true,
+				Columns: []*schema.Column{DataKeysColumns[5]},
+			},
+		},
+	}
+	// JobCheckpointsColumns holds the columns for the "job_checkpoints" table.
+	JobCheckpointsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Unique: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "deleted_at", Type: field.TypeTime, Nullable: true},
+		{Name: "name", Type: field.TypeString, Unique: true, Size: 64},
+		{Name: "cursor", Type: field.TypeUUID, Nullable: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
+		{Name: "processed", Type: field.TypeUint64, Default: 0},
+		{Name: "failed_ids", Type: field.TypeJSON, Nullable: true},
+		{Name: "completed_at", Type: field.TypeTime, Nullable: true},
+	}
+	// JobCheckpointsTable holds the schema information for the "job_checkpoints" table.
+	JobCheckpointsTable = &schema.Table{
+		Name:       "job_checkpoints",
+		Columns:    JobCheckpointsColumns,
+		PrimaryKey: []*schema.Column{JobCheckpointsColumns[0]},
+	}
 	// MfaQrsColumns holds the columns for the "mfa_qrs" table.
 	MfaQrsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID, Unique: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
 		{Name: "created_at", Type: field.TypeTime},
 		{Name: "updated_at", Type: field.TypeTime},
 		{Name: "deleted_at", Type: field.TypeTime, Nullable: true},
+		{Name: "failed_attempts", Type: field.TypeUint32, Default: 0},
+		{Name: "last_failed_at", Type: field.TypeTime, Nullable: true},
+		{Name: "locked_until", Type: field.TypeTime, Nullable: true},
 		{Name: "secret", Type: field.TypeBytes, Size: 256, SchemaType: map[string]string{"mysql": "varbinary(256)"}},
+		{Name: "secret_bound", Type: field.TypeBool, Default: false},
+		{Name: "type", Type: field.TypeEnum, Enums: []string{"totp", "hotp"}, Default: "totp"},
+		{Name: "algorithm", Type: field.TypeEnum, Enums: []string{"SHA1", "SHA256", "SHA512"}, Default: "SHA1"},
+		{Name: "digits", Type: field.TypeUint8, Default: 6},
+		{Name: "period", Type: field.TypeUint32, Default: 30},
+		{Name: "counter", Type: field.TypeUint64, Default: 0},
+		{Name: "last_step", Type: field.TypeUint64, Default: 0},
+		{Name: "status", Type: field.TypeEnum, Enums: []string{"pending", "active"}, Default: "active"},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true},
+		{Name: "activated_at", Type: field.TypeTime, Nullable: true},
+		{Name: "data_key_id", Type: field.TypeUUID, Nullable: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
 		{Name: "user_id", Type: field.TypeUUID, SchemaType: map[string]string{"mysql": "binary(16)"}},
 	}
 	// MfaQrsTable holds the schema information for the "mfa_qrs" table.
@@ -24,9 +86,15 @@ var (
 		Columns:    MfaQrsColumns,
 		PrimaryKey: []*schema.Column{MfaQrsColumns[0]},
 		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "mfa_qrs_data_keys_mfa_qrs",
+				Columns:    []*schema.Column{MfaQrsColumns[18]},
+				RefColumns: []*schema.Column{DataKeysColumns[0]},
+				OnDelete:   schema.SetNull,
+			},
 			{
 				Symbol:     "mfa_qrs_users_mfa_qrs",
-				Columns:    []*schema.Column{MfaQrsColumns[5]},
+				Columns:    []*schema.Column{MfaQrsColumns[19]},
 				RefColumns: []*schema.Column{UsersColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
@@ -35,7 +103,7 @@ var (
 			{
 				Name:    "mfaqr_user_id_created_at",
 				Unique:  false,
-				Columns: []*schema.Column{MfaQrsColumns[5], MfaQrsColumns[1]},
+				Columns: []*schema.Column{MfaQrsColumns[19], MfaQrsColumns[1]},
 				Annotation: &entsql.IndexAnnotation{
 					DescColumns: map[string]bool{
 						MfaQrsColumns[1].Name: true,
@@ -44,12 +112,61 @@ var (
 			},
 		},
 	}
+	// RateLimitBucketsColumns holds the columns for the "rate_limit_buckets" table.
+	RateLimitBucketsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Unique: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
+		{Name: "key", Type: field.TypeString, Unique: true, Size: 256},
+		{Name: "tokens", Type: field.TypeFloat64},
+		{Name: "refilled_at", Type: field.TypeTime, SchemaType: map[string]string{"mysql": "datetime(6)"}},
+		{Name: "full_at", Type: field.TypeTime, SchemaType: map[string]string{"mysql": "datetime(6)"}},
+		{Name: "version", Type: field.TypeUint64, Default: 0},
+	}
+	// RateLimitBucketsTable holds the schema information for the "rate_limit_buckets" table.
+	RateLimitBucketsTable = &schema.Table{
+		Name:       "rate_limit_buckets",
+		Columns:    RateLimitBucketsColumns,
+		PrimaryKey: []*schema.Column{RateLimitBucketsColumns[0]},
+	}
+	// RecoveryCodesColumns holds the columns for the "recovery_codes" table.
+	RecoveryCodesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID, Unique: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
+		{Name: "created_at", Type: field.TypeTime},
+		{Name: "updated_at", Type: field.TypeTime},
+		{Name: "deleted_at", Type: field.TypeTime, Nullable: true},
+		{Name: "hash", Type: field.TypeString, Size: 256},
+		{Name: "used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_id", Type: field.TypeUUID, SchemaType: map[string]string{"mysql": "binary(16)"}},
+	}
+	// RecoveryCodesTable holds the schema information for the "recovery_codes" table.
+	RecoveryCodesTable = &schema.Table{
+		Name:       "recovery_codes",
+		Columns:    RecoveryCodesColumns,
+		PrimaryKey: []*schema.Column{RecoveryCodesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "recovery_codes_users_recovery_codes",
+				Columns:    []*schema.Column{RecoveryCodesColumns[6]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "recoverycode_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{RecoveryCodesColumns[6]},
+			},
+		},
+	}
 	// UsersColumns holds the columns for the "users" table.
 	UsersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID, Unique: true, SchemaType: map[string]string{"mysql": "binary(16)"}},
 		{Name: "created_at", Type: field.TypeTime},
 		{Name: "updated_at", Type: field.TypeTime},
 		{Name: "deleted_at", Type: field.TypeTime, Nullable: true},
+		{Name: "failed_attempts", Type: field.TypeUint32, Default: 0},
+		{Name: "last_failed_at", Type: field.TypeTime, Nullable: true},
+		{Name: "locked_until", Type: field.TypeTime, Nullable: true},
 		{Name: "name", Type: field.TypeString, Size: 256},
 		{Name: "email", Type: field.TypeString, Unique: true, Size: 256},
 		{Name: "login_method", Type: field.TypeEnum, Enums: []string{"password", "mfa-qr", "passkey"}, Default: "password"},
@@ -63,17 +180,24 @@ var (
 			{
 				Name:    "user_email",
 				Unique:  true,
-				Columns: []*schema.Column{UsersColumns[5]},
+				Columns: []*schema.Column{UsersColumns[8]},
 			},
 		},
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		DataKeysTable,
+		JobCheckpointsTable,
 		MfaQrsTable,
+		RateLimitBucketsTable,
+		RecoveryCodesTable,
 		UsersTable,
 	}
 )
 
 func init() {
-	MfaQrsTable.ForeignKeys[0].RefTable = UsersTable
+	DataKeysTable.ForeignKeys[0].RefTable = UsersTable
+	MfaQrsTable.ForeignKeys[0].RefTable = DataKeysTable
+	MfaQrsTable.ForeignKeys[1].RefTable = UsersTable
+	RecoveryCodesTable.ForeignKeys[0].RefTable = UsersTable
 }
@@ -7,8 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
+	"nidan-kai/ent/jobcheckpoint"
 	"nidan-kai/ent/mfaqr"
 	"nidan-kai/ent/predicate"
+	"nidan-kai/ent/ratelimitbucket"
+	"nidan-kai/ent/recoverycode"
 	"nidan-kai/ent/user"
 	"sync"
 	"time"
@@ -26,12 +30,3873 @@ const (
 	OpUpdateOne = ent.OpUpdateOne
 
 	// Node types.
-	TypeMfaQr = "MfaQr"
-	TypeUser  = "User"
+	TypeDataKey         = "DataKey"
+	TypeJobCheckpoint   = "JobCheckpoint"
+	TypeMfaQr           = "MfaQr"
+	TypeRateLimitBucket = "RateLimitBucket"
+	TypeRecoveryCode    = "RecoveryCode"
+	TypeUser            = "User"
 )
 
+// DataKeyMutation represents an operation that mutates the DataKey nodes in the graph.
+type DataKeyMutation struct {
+	config
+	op             Op
+	typ            string
+	id             *binid.BinId
+	created_at     *time.Time
+	updated_at     *time.Time
+	deleted_at     *time.Time
+	wrapped_key    *[]byte
+	clearedFields  map[string]struct{}
+	user           *binid.BinId
+	cleareduser    bool
+	mfa_qrs        map[binid.BinId]struct{}
+	removedmfa_qrs map[binid.BinId]struct{}
+	clearedmfa_qrs bool
+	done           bool
+	oldValue       func(context.Context) (*DataKey, error)
+	predicates     []predicate.DataKey
+}
+
+var _ ent.Mutation = (*DataKeyMutation)(nil)
+
+// datakeyOption allows management of the mutation configuration using functional options.
+type datakeyOption func(*DataKeyMutation)
+
+// newDataKeyMutation creates new mutation for the DataKey entity.
+func newDataKeyMutation(c config, op Op, opts ...datakeyOption) *DataKeyMutation {
+	m := &DataKeyMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeDataKey,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withDataKeyID sets the ID field of the mutation.
+func withDataKeyID(id binid.BinId) datakeyOption {
+	return func(m *DataKeyMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *DataKey
+		)
+		m.oldValue = func(ctx context.Context) (*DataKey, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().DataKey.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withDataKey sets the old DataKey of the mutation.
+func withDataKey(node *DataKey) datakeyOption {
+	return func(m *DataKeyMutation) {
+		m.oldValue = func(context.Context) (*DataKey, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m DataKeyMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m DataKeyMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of DataKey entities.
+func (m *DataKeyMutation) SetID(id binid.BinId) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *DataKeyMutation) ID() (id binid.BinId, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *DataKeyMutation) IDs(ctx context.Context) ([]binid.BinId, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []binid.BinId{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().DataKey.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *DataKeyMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *DataKeyMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the DataKey entity.
+// If the DataKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DataKeyMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *DataKeyMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (m *DataKeyMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
+}
+
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *DataKeyMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUpdatedAt returns the old "updated_at" field's value of the DataKey entity.
+// If the DataKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DataKeyMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+	}
+	return oldValue.UpdatedAt, nil
+}
+
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *DataKeyMutation) ResetUpdatedAt() {
+	m.updated_at = nil
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (m *DataKeyMutation) SetDeletedAt(t time.Time) {
+	m.deleted_at = &t
+}
+
+// DeletedAt returns the value of the "deleted_at" field in the mutation.
+func (m *DataKeyMutation) DeletedAt() (r time.Time, exists bool) {
+	v := m.deleted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDeletedAt returns the old "deleted_at" field's value of the DataKey entity.
+// If the DataKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DataKeyMutation) OldDeletedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDeletedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDeletedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDeletedAt: %w", err)
+	}
+	return oldValue.DeletedAt, nil
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (m *DataKeyMutation) ClearDeletedAt() {
+	m.deleted_at = nil
+	m.clearedFields[datakey.FieldDeletedAt] = struct{}{}
+}
+
+// DeletedAtCleared returns if the "deleted_at" field was cleared in this mutation.
+func (m *DataKeyMutation) DeletedAtCleared() bool {
+	_, ok := m.clearedFields[datakey.FieldDeletedAt]
+	return ok
+}
+
+// ResetDeletedAt resets all changes to the "deleted_at" field.
+func (m *DataKeyMutation) ResetDeletedAt() {
+	m.deleted_at = nil
+	delete(m.clearedFields, datakey.FieldDeletedAt)
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (m *DataKeyMutation) SetWrappedKey(b []byte) {
+	m.wrapped_key = &b
+}
+
+// WrappedKey returns the value of the "wrapped_key" field in the mutation.
+func (m *DataKeyMutation) WrappedKey() (r []byte, exists bool) {
+	v := m.wrapped_key
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldWrappedKey returns the old "wrapped_key" field's value of the DataKey entity.
+// If the DataKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DataKeyMutation) OldWrappedKey(ctx context.Context) (v []byte, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldWrappedKey is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldWrappedKey requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldWrappedKey: %w", err)
+	}
+	return oldValue.WrappedKey, nil
+}
+
+// ResetWrappedKey resets all changes to the "wrapped_key" field.
+func (m *DataKeyMutation) ResetWrappedKey() {
+	m.wrapped_key = nil
+}
+
+// SetUserID sets the "user_id" field.
+func (m *DataKeyMutation) SetUserID(bi binid.BinId) {
+	m.user = &bi
+}
+
+// UserID returns the value of the "user_id" field in the mutation.
+func (m *DataKeyMutation) UserID() (r binid.BinId, exists bool) {
+	v := m.user
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUserID returns the old "user_id" field's value of the DataKey entity.
+// If the DataKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *DataKeyMutation) OldUserID(ctx context.Context) (v binid.BinId, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUserID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUserID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUserID: %w", err)
+	}
+	return oldValue.UserID, nil
+}
+
+// ResetUserID resets all changes to the "user_id" field.
+func (m *DataKeyMutation) ResetUserID() {
+	m.user = nil
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (m *DataKeyMutation) ClearUser() {
+	m.cleareduser = true
+	m.clearedFields[datakey.FieldUserID] = struct{}{}
+}
+
+// UserCleared reports if the "user" edge to the User entity was cleared.
+func (m *DataKeyMutation) UserCleared() bool {
+	return m.cleareduser
+}
+
+// UserIDs returns the "user" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// UserID instead. It exists only for internal usage by the builders.
+func (m *DataKeyMutation) UserIDs() (ids []binid.BinId) {
+	if id := m.user; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetUser resets all changes to the "user" edge.
+func (m *DataKeyMutation) ResetUser() {
+	m.user = nil
+	m.cleareduser = false
+}
+
+// AddMfaQrIDs adds the "mfa_qrs" edge to the MfaQr entity by ids.
+func (m *DataKeyMutation) AddMfaQrIDs(ids ...binid.BinId) {
+	if m.mfa_qrs == nil {
+		m.mfa_qrs = make(map[binid.BinId]struct{})
+	}
+	for i := range ids {
+		m.mfa_qrs[ids[i]] = struct{}{}
+	}
+}
+
+// ClearMfaQrs clears the "mfa_qrs" edge to the MfaQr entity.
+func (m *DataKeyMutation) ClearMfaQrs() {
+	m.clearedmfa_qrs = true
+}
+
+// MfaQrsCleared reports if the "mfa_qrs" edge to the MfaQr entity was cleared.
+func (m *DataKeyMutation) MfaQrsCleared() bool {
+	return m.clearedmfa_qrs
+}
+
+// RemoveMfaQrIDs removes the "mfa_qrs" edge to the MfaQr entity by IDs.
+func (m *DataKeyMutation) RemoveMfaQrIDs(ids ...binid.BinId) {
+	if m.removedmfa_qrs == nil {
+		m.removedmfa_qrs = make(map[binid.BinId]struct{})
+	}
+	for i := range ids {
+		delete(m.mfa_qrs, ids[i])
+		m.removedmfa_qrs[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedMfaQrs returns the removed IDs of the "mfa_qrs" edge to the MfaQr entity.
+func (m *DataKeyMutation) RemovedMfaQrsIDs() (ids []binid.BinId) {
+	for id := range m.removedmfa_qrs {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// MfaQrsIDs returns the "mfa_qrs" edge IDs in the mutation.
+func (m *DataKeyMutation) MfaQrsIDs() (ids []binid.BinId) {
+	for id := range m.mfa_qrs {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetMfaQrs resets all changes to the "mfa_qrs" edge.
+func (m *DataKeyMutation) ResetMfaQrs() {
+	m.mfa_qrs = nil
+	m.clearedmfa_qrs = false
+	m.removedmfa_qrs = nil
+}
+
+// Where appends a list predicates to the DataKeyMutation builder.
+func (m *DataKeyMutation) Where(ps ...predicate.DataKey) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the DataKeyMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *DataKeyMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.DataKey, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *DataKeyMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *DataKeyMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (DataKey).
+func (m *DataKeyMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *DataKeyMutation) Fields() []string {
+	fields := make([]string, 0, 5)
+	if m.created_at != nil {
+		fields = append(fields, datakey.FieldCreatedAt)
+	}
+	if m.updated_at != nil {
+		fields = append(fields, datakey.FieldUpdatedAt)
+	}
+	if m.deleted_at != nil {
+		fields = append(fields, datakey.FieldDeletedAt)
+	}
+	if m.wrapped_key != nil {
+		fields = append(fields, datakey.FieldWrappedKey)
+	}
+	if m.user != nil {
+		fields = append(fields, datakey.FieldUserID)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *DataKeyMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case datakey.FieldCreatedAt:
+		return m.CreatedAt()
+	case datakey.FieldUpdatedAt:
+		return m.UpdatedAt()
+	case datakey.FieldDeletedAt:
+		return m.DeletedAt()
+	case datakey.FieldWrappedKey:
+		return m.WrappedKey()
+	case datakey.FieldUserID:
+		return m.UserID()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *DataKeyMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case datakey.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case datakey.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
+	case datakey.FieldDeletedAt:
+		return m.OldDeletedAt(ctx)
+	case datakey.FieldWrappedKey:
+		return m.OldWrappedKey(ctx)
+	case datakey.FieldUserID:
+		return m.OldUserID(ctx)
+	}
+	return nil, fmt.Errorf("unknown DataKey field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *DataKeyMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case datakey.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	case datakey.FieldUpdatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUpdatedAt(v)
+		return nil
+	case datakey.FieldDeletedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDeletedAt(v)
+		return nil
+	case datakey.FieldWrappedKey:
+		v, ok := value.([]byte)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetWrappedKey(v)
+		return nil
+	case datakey.FieldUserID:
+		v, ok := value.(binid.BinId)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUserID(v)
+		return nil
+	}
+	return fmt.Errorf("unknown DataKey field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *DataKeyMutation) AddedFields() []string {
+	return nil
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *DataKeyMutation) AddedField(name string) (ent.Value, bool) {
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *DataKeyMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown DataKey numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *DataKeyMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(datakey.FieldDeletedAt) {
+		fields = append(fields, datakey.FieldDeletedAt)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *DataKeyMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *DataKeyMutation) ClearField(name string) error {
+	switch name {
+	case datakey.FieldDeletedAt:
+		m.ClearDeletedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown DataKey nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *DataKeyMutation) ResetField(name string) error {
+	switch name {
+	case datakey.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case datakey.FieldUpdatedAt:
+		m.ResetUpdatedAt()
+		return nil
+	case datakey.FieldDeletedAt:
+		m.ResetDeletedAt()
+		return nil
+	case datakey.FieldWrappedKey:
+		m.ResetWrappedKey()
+		return nil
+	case datakey.FieldUserID:
+		m.ResetUserID()
+		return nil
+	}
+	return fmt.Errorf("unknown DataKey field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *DataKeyMutation) AddedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.user != nil {
+		edges = append(edges, datakey.EdgeUser)
+	}
+	if m.mfa_qrs != nil {
+		edges = append(edges, datakey.EdgeMfaQrs)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *DataKeyMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case datakey.EdgeUser:
+		if id := m.user; id != nil {
+			return []ent.Value{*id}
+		}
+	case datakey.EdgeMfaQrs:
+		ids := make([]ent.Value, 0, len(m.mfa_qrs))
+		for id := range m.mfa_qrs {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *DataKeyMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.removedmfa_qrs != nil {
+		edges = append(edges, datakey.EdgeMfaQrs)
+	}
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *DataKeyMutation) RemovedIDs(name string) []ent.Value {
+	switch name {
+	case datakey.EdgeMfaQrs:
+		ids := make([]ent.Value, 0, len(m.removedmfa_qrs))
+		for id := range m.removedmfa_qrs {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *DataKeyMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.cleareduser {
+		edges = append(edges, datakey.EdgeUser)
+	}
+	if m.clearedmfa_qrs {
+		edges = append(edges, datakey.EdgeMfaQrs)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *DataKeyMutation) EdgeCleared(name string) bool {
+	switch name {
+	case datakey.EdgeUser:
+		return m.cleareduser
+	case datakey.EdgeMfaQrs:
+		return m.clearedmfa_qrs
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *DataKeyMutation) ClearEdge(name string) error {
+	switch name {
+	case datakey.EdgeUser:
+		m.ClearUser()
+		return nil
+	}
+	return fmt.Errorf("unknown DataKey unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *DataKeyMutation) ResetEdge(name string) error {
+	switch name {
+	case datakey.EdgeUser:
+		m.ResetUser()
+		return nil
+	case datakey.EdgeMfaQrs:
+		m.ResetMfaQrs()
+		return nil
+	}
+	return fmt.Errorf("unknown DataKey edge %s", name)
+}
+
+// JobCheckpointMutation represents an operation that mutates the JobCheckpoint nodes in the graph.
+type JobCheckpointMutation struct {
+	config
+	op               Op
+	typ              string
+	id               *binid.BinId
+	created_at       *time.Time
+	updated_at       *time.Time
+	deleted_at       *time.Time
+	name             *string
+	cursor           *binid.BinId
+	processed        *uint64
+	addprocessed     *int64
+	failed_ids       *[]string
+	appendfailed_ids []string
+	completed_at     *time.Time
+	clearedFields    map[string]struct{}
+	done             bool
+	oldValue         func(context.Context) (*JobCheckpoint, error)
+	predicates       []predicate.JobCheckpoint
+}
+
+var _ ent.Mutation = (*JobCheckpointMutation)(nil)
+
+// jobcheckpointOption allows management of the mutation configuration using functional options.
+type jobcheckpointOption func(*JobCheckpointMutation)
+
+// newJobCheckpointMutation creates new mutation for the JobCheckpoint entity.
+func newJobCheckpointMutation(c config, op Op, opts ...jobcheckpointOption) *JobCheckpointMutation {
+	m := &JobCheckpointMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeJobCheckpoint,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withJobCheckpointID sets the ID field of the mutation.
+func withJobCheckpointID(id binid.BinId) jobcheckpointOption {
+	return func(m *JobCheckpointMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *JobCheckpoint
+		)
+		m.oldValue = func(ctx context.Context) (*JobCheckpoint, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().JobCheckpoint.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withJobCheckpoint sets the old JobCheckpoint of the mutation.
+func withJobCheckpoint(node *JobCheckpoint) jobcheckpointOption {
+	return func(m *JobCheckpointMutation) {
+		m.oldValue = func(context.Context) (*JobCheckpoint, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m JobCheckpointMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m JobCheckpointMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of JobCheckpoint entities.
+func (m *JobCheckpointMutation) SetID(id binid.BinId) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *JobCheckpointMutation) ID() (id binid.BinId, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *JobCheckpointMutation) IDs(ctx context.Context) ([]binid.BinId, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []binid.BinId{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().JobCheckpoint.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *JobCheckpointMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *JobCheckpointMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *JobCheckpointMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (m *JobCheckpointMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
+}
+
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *JobCheckpointMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUpdatedAt returns the old "updated_at" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+	}
+	return oldValue.UpdatedAt, nil
+}
+
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *JobCheckpointMutation) ResetUpdatedAt() {
+	m.updated_at = nil
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (m *JobCheckpointMutation) SetDeletedAt(t time.Time) {
+	m.deleted_at = &t
+}
+
+// DeletedAt returns the value of the "deleted_at" field in the mutation.
+func (m *JobCheckpointMutation) DeletedAt() (r time.Time, exists bool) {
+	v := m.deleted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDeletedAt returns the old "deleted_at" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldDeletedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDeletedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDeletedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDeletedAt: %w", err)
+	}
+	return oldValue.DeletedAt, nil
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (m *JobCheckpointMutation) ClearDeletedAt() {
+	m.deleted_at = nil
+	m.clearedFields[jobcheckpoint.FieldDeletedAt] = struct{}{}
+}
+
+// DeletedAtCleared returns if the "deleted_at" field was cleared in this mutation.
+func (m *JobCheckpointMutation) DeletedAtCleared() bool {
+	_, ok := m.clearedFields[jobcheckpoint.FieldDeletedAt]
+	return ok
+}
+
+// ResetDeletedAt resets all changes to the "deleted_at" field.
+func (m *JobCheckpointMutation) ResetDeletedAt() {
+	m.deleted_at = nil
+	delete(m.clearedFields, jobcheckpoint.FieldDeletedAt)
+}
+
+// SetName sets the "name" field.
+func (m *JobCheckpointMutation) SetName(s string) {
+	m.name = &s
+}
+
+// Name returns the value of the "name" field in the mutation.
+func (m *JobCheckpointMutation) Name() (r string, exists bool) {
+	v := m.name
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldName returns the old "name" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldName(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldName is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldName requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldName: %w", err)
+	}
+	return oldValue.Name, nil
+}
+
+// ResetName resets all changes to the "name" field.
+func (m *JobCheckpointMutation) ResetName() {
+	m.name = nil
+}
+
+// SetCursor sets the "cursor" field.
+func (m *JobCheckpointMutation) SetCursor(bi binid.BinId) {
+	m.cursor = &bi
+}
+
+// Cursor returns the value of the "cursor" field in the mutation.
+func (m *JobCheckpointMutation) Cursor() (r binid.BinId, exists bool) {
+	v := m.cursor
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCursor returns the old "cursor" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldCursor(ctx context.Context) (v *binid.BinId, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCursor is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCursor requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCursor: %w", err)
+	}
+	return oldValue.Cursor, nil
+}
+
+// ClearCursor clears the value of the "cursor" field.
+func (m *JobCheckpointMutation) ClearCursor() {
+	m.cursor = nil
+	m.clearedFields[jobcheckpoint.FieldCursor] = struct{}{}
+}
+
+// CursorCleared returns if the "cursor" field was cleared in this mutation.
+func (m *JobCheckpointMutation) CursorCleared() bool {
+	_, ok := m.clearedFields[jobcheckpoint.FieldCursor]
+	return ok
+}
+
+// ResetCursor resets all changes to the "cursor" field.
+func (m *JobCheckpointMutation) ResetCursor() {
+	m.cursor = nil
+	delete(m.clearedFields, jobcheckpoint.FieldCursor)
+}
+
+// SetProcessed sets the "processed" field.
+func (m *JobCheckpointMutation) SetProcessed(u uint64) {
+	m.processed = &u
+	m.addprocessed = nil
+}
+
+// Processed returns the value of the "processed" field in the mutation.
+func (m *JobCheckpointMutation) Processed() (r uint64, exists bool) {
+	v := m.processed
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldProcessed returns the old "processed" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldProcessed(ctx context.Context) (v uint64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldProcessed is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldProcessed requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldProcessed: %w", err)
+	}
+	return oldValue.Processed, nil
+}
+
+// AddProcessed adds u to the "processed" field.
+func (m *JobCheckpointMutation) AddProcessed(u int64) {
+	if m.addprocessed != nil {
+		*m.addprocessed += u
+	} else {
+		m.addprocessed = &u
+	}
+}
+
+// AddedProcessed returns the value that was added to the "processed" field in this mutation.
+func (m *JobCheckpointMutation) AddedProcessed() (r int64, exists bool) {
+	v := m.addprocessed
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetProcessed resets all changes to the "processed" field.
+func (m *JobCheckpointMutation) ResetProcessed() {
+	m.processed = nil
+	m.addprocessed = nil
+}
+
+// SetFailedIds sets the "failed_ids" field.
+func (m *JobCheckpointMutation) SetFailedIds(s []string) {
+	m.failed_ids = &s
+	m.appendfailed_ids = nil
+}
+
+// FailedIds returns the value of the "failed_ids" field in the mutation.
+func (m *JobCheckpointMutation) FailedIds() (r []string, exists bool) {
+	v := m.failed_ids
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldFailedIds returns the old "failed_ids" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldFailedIds(ctx context.Context) (v []string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldFailedIds is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldFailedIds requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldFailedIds: %w", err)
+	}
+	return oldValue.FailedIds, nil
+}
+
+// AppendFailedIds adds s to the "failed_ids" field.
+func (m *JobCheckpointMutation) AppendFailedIds(s []string) {
+	m.appendfailed_ids = append(m.appendfailed_ids, s...)
+}
+
+// AppendedFailedIds returns the list of values that were appended to the "failed_ids" field in this mutation.
+func (m *JobCheckpointMutation) AppendedFailedIds() ([]string, bool) {
+	if len(m.appendfailed_ids) == 0 {
+		return nil, false
+	}
+	return m.appendfailed_ids, true
+}
+
+// ClearFailedIds clears the value of the "failed_ids" field.
+func (m *JobCheckpointMutation) ClearFailedIds() {
+	m.failed_ids = nil
+	m.appendfailed_ids = nil
+	m.clearedFields[jobcheckpoint.FieldFailedIds] = struct{}{}
+}
+
+// FailedIdsCleared returns if the "failed_ids" field was cleared in this mutation.
+func (m *JobCheckpointMutation) FailedIdsCleared() bool {
+	_, ok := m.clearedFields[jobcheckpoint.FieldFailedIds]
+	return ok
+}
+
+// ResetFailedIds resets all changes to the "failed_ids" field.
+func (m *JobCheckpointMutation) ResetFailedIds() {
+	m.failed_ids = nil
+	m.appendfailed_ids = nil
+	delete(m.clearedFields, jobcheckpoint.FieldFailedIds)
+}
+
+// SetCompletedAt sets the "completed_at" field.
+func (m *JobCheckpointMutation) SetCompletedAt(t time.Time) {
+	m.completed_at = &t
+}
+
+// CompletedAt returns the value of the "completed_at" field in the mutation.
+func (m *JobCheckpointMutation) CompletedAt() (r time.Time, exists bool) {
+	v := m.completed_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCompletedAt returns the old "completed_at" field's value of the JobCheckpoint entity.
+// If the JobCheckpoint object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *JobCheckpointMutation) OldCompletedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCompletedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCompletedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCompletedAt: %w", err)
+	}
+	return oldValue.CompletedAt, nil
+}
+
+// ClearCompletedAt clears the value of the "completed_at" field.
+func (m *JobCheckpointMutation) ClearCompletedAt() {
+	m.completed_at = nil
+	m.clearedFields[jobcheckpoint.FieldCompletedAt] = struct{}{}
+}
+
+// CompletedAtCleared returns if the "completed_at" field was cleared in this mutation.
+func (m *JobCheckpointMutation) CompletedAtCleared() bool {
+	_, ok := m.clearedFields[jobcheckpoint.FieldCompletedAt]
+	return ok
+}
+
+// ResetCompletedAt resets all changes to the "completed_at" field.
+func (m *JobCheckpointMutation) ResetCompletedAt() {
+	m.completed_at = nil
+	delete(m.clearedFields, jobcheckpoint.FieldCompletedAt)
+}
+
+// Where appends a list predicates to the JobCheckpointMutation builder.
+func (m *JobCheckpointMutation) Where(ps ...predicate.JobCheckpoint) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the JobCheckpointMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *JobCheckpointMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.JobCheckpoint, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *JobCheckpointMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *JobCheckpointMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (JobCheckpoint).
+func (m *JobCheckpointMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *JobCheckpointMutation) Fields() []string {
+	fields := make([]string, 0, 8)
+	if m.created_at != nil {
+		fields = append(fields, jobcheckpoint.FieldCreatedAt)
+	}
+	if m.updated_at != nil {
+		fields = append(fields, jobcheckpoint.FieldUpdatedAt)
+	}
+	if m.deleted_at != nil {
+		fields = append(fields, jobcheckpoint.FieldDeletedAt)
+	}
+	if m.name != nil {
+		fields = append(fields, jobcheckpoint.FieldName)
+	}
+	if m.cursor != nil {
+		fields = append(fields, jobcheckpoint.FieldCursor)
+	}
+	if m.processed != nil {
+		fields = append(fields, jobcheckpoint.FieldProcessed)
+	}
+	if m.failed_ids != nil {
+		fields = append(fields, jobcheckpoint.FieldFailedIds)
+	}
+	if m.completed_at != nil {
+		fields = append(fields, jobcheckpoint.FieldCompletedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *JobCheckpointMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case jobcheckpoint.FieldCreatedAt:
+		return m.CreatedAt()
+	case jobcheckpoint.FieldUpdatedAt:
+		return m.UpdatedAt()
+	case jobcheckpoint.FieldDeletedAt:
+		return m.DeletedAt()
+	case jobcheckpoint.FieldName:
+		return m.Name()
+	case jobcheckpoint.FieldCursor:
+		return m.Cursor()
+	case jobcheckpoint.FieldProcessed:
+		return m.Processed()
+	case jobcheckpoint.FieldFailedIds:
+		return m.FailedIds()
+	case jobcheckpoint.FieldCompletedAt:
+		return m.CompletedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *JobCheckpointMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case jobcheckpoint.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case jobcheckpoint.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
+	case jobcheckpoint.FieldDeletedAt:
+		return m.OldDeletedAt(ctx)
+	case jobcheckpoint.FieldName:
+		return m.OldName(ctx)
+	case jobcheckpoint.FieldCursor:
+		return m.OldCursor(ctx)
+	case jobcheckpoint.FieldProcessed:
+		return m.OldProcessed(ctx)
+	case jobcheckpoint.FieldFailedIds:
+		return m.OldFailedIds(ctx)
+	case jobcheckpoint.FieldCompletedAt:
+		return m.OldCompletedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown JobCheckpoint field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *JobCheckpointMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case jobcheckpoint.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	case jobcheckpoint.FieldUpdatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUpdatedAt(v)
+		return nil
+	case jobcheckpoint.FieldDeletedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDeletedAt(v)
+		return nil
+	case jobcheckpoint.FieldName:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetName(v)
+		return nil
+	case jobcheckpoint.FieldCursor:
+		v, ok := value.(binid.BinId)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCursor(v)
+		return nil
+	case jobcheckpoint.FieldProcessed:
+		v, ok := value.(uint64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetProcessed(v)
+		return nil
+	case jobcheckpoint.FieldFailedIds:
+		v, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetFailedIds(v)
+		return nil
+	case jobcheckpoint.FieldCompletedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCompletedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown JobCheckpoint field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *JobCheckpointMutation) AddedFields() []string {
+	var fields []string
+	if m.addprocessed != nil {
+		fields = append(fields, jobcheckpoint.FieldProcessed)
+	}
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *JobCheckpointMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case jobcheckpoint.FieldProcessed:
+		return m.AddedProcessed()
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *JobCheckpointMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	case jobcheckpoint.FieldProcessed:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddProcessed(v)
+		return nil
+	}
+	return fmt.Errorf("unknown JobCheckpoint numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *JobCheckpointMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(jobcheckpoint.FieldDeletedAt) {
+		fields = append(fields, jobcheckpoint.FieldDeletedAt)
+	}
+	if m.FieldCleared(jobcheckpoint.FieldCursor) {
+		fields = append(fields, jobcheckpoint.FieldCursor)
+	}
+	if m.FieldCleared(jobcheckpoint.FieldFailedIds) {
+		fields = append(fields, jobcheckpoint.FieldFailedIds)
+	}
+	if m.FieldCleared(jobcheckpoint.FieldCompletedAt) {
+		fields = append(fields, jobcheckpoint.FieldCompletedAt)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *JobCheckpointMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *JobCheckpointMutation) ClearField(name string) error {
+	switch name {
+	case jobcheckpoint.FieldDeletedAt:
+		m.ClearDeletedAt()
+		return nil
+	case jobcheckpoint.FieldCursor:
+		m.ClearCursor()
+		return nil
+	case jobcheckpoint.FieldFailedIds:
+		m.ClearFailedIds()
+		return nil
+	case jobcheckpoint.FieldCompletedAt:
+		m.ClearCompletedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown JobCheckpoint nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *JobCheckpointMutation) ResetField(name string) error {
+	switch name {
+	case jobcheckpoint.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case jobcheckpoint.FieldUpdatedAt:
+		m.ResetUpdatedAt()
+		return nil
+	case jobcheckpoint.FieldDeletedAt:
+		m.ResetDeletedAt()
+		return nil
+	case jobcheckpoint.FieldName:
+		m.ResetName()
+		return nil
+	case jobcheckpoint.FieldCursor:
+		m.ResetCursor()
+		return nil
+	case jobcheckpoint.FieldProcessed:
+		m.ResetProcessed()
+		return nil
+	case jobcheckpoint.FieldFailedIds:
+		m.ResetFailedIds()
+		return nil
+	case jobcheckpoint.FieldCompletedAt:
+		m.ResetCompletedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown JobCheckpoint field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *JobCheckpointMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *JobCheckpointMutation) AddedIDs(name string) []ent.Value {
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *JobCheckpointMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *JobCheckpointMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *JobCheckpointMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *JobCheckpointMutation) EdgeCleared(name string) bool {
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *JobCheckpointMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown JobCheckpoint unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *JobCheckpointMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown JobCheckpoint edge %s", name)
+}
+
 // MfaQrMutation represents an operation that mutates the MfaQr nodes in the graph.
 type MfaQrMutation struct {
+	config
+	op                 Op
+	typ                string
+	id                 *binid.BinId
+	created_at         *time.Time
+	updated_at         *time.Time
+	deleted_at         *time.Time
+	failed_attempts    *uint32
+	addfailed_attempts *int32
+	last_failed_at     *time.Time
+	locked_until       *time.Time
+	secret             *[]byte
+	secret_bound       *bool
+	_type              *mfaqr.Type
+	algorithm          *mfaqr.Algorithm
+	digits             *uint8
+	adddigits          *int8
+	period             *uint32
+	addperiod          *int32
+	counter            *uint64
+	addcounter         *int64
+	last_step          *uint64
+	addlast_step       *int64
+	status             *mfaqr.Status
+	expires_at         *time.Time
+	activated_at       *time.Time
+	clearedFields      map[string]struct{}
+	user               *binid.BinId
+	cleareduser        bool
+	data_key           *binid.BinId
+	cleareddata_key    bool
+	done               bool
+	oldValue           func(context.Context) (*MfaQr, error)
+	predicates         []predicate.MfaQr
+}
+
+var _ ent.Mutation = (*MfaQrMutation)(nil)
+
+// mfaqrOption allows management of the mutation configuration using functional options.
+type mfaqrOption func(*MfaQrMutation)
+
+// newMfaQrMutation creates new mutation for the MfaQr entity.
+func newMfaQrMutation(c config, op Op, opts ...mfaqrOption) *MfaQrMutation {
+	m := &MfaQrMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeMfaQr,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withMfaQrID sets the ID field of the mutation.
+func withMfaQrID(id binid.BinId) mfaqrOption {
+	return func(m *MfaQrMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *MfaQr
+		)
+		m.oldValue = func(ctx context.Context) (*MfaQr, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().MfaQr.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withMfaQr sets the old MfaQr of the mutation.
+func withMfaQr(node *MfaQr) mfaqrOption {
+	return func(m *MfaQrMutation) {
+		m.oldValue = func(context.Context) (*MfaQr, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m MfaQrMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m MfaQrMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of MfaQr entities.
+func (m *MfaQrMutation) SetID(id binid.BinId) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *MfaQrMutation) ID() (id binid.BinId, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *MfaQrMutation) IDs(ctx context.Context) ([]binid.BinId, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []binid.BinId{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().MfaQr.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (m *MfaQrMutation) SetCreatedAt(t time.Time) {
+	m.created_at = &t
+}
+
+// CreatedAt returns the value of the "created_at" field in the mutation.
+func (m *MfaQrMutation) CreatedAt() (r time.Time, exists bool) {
+	v := m.created_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCreatedAt returns the old "created_at" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCreatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCreatedAt: %w", err)
+	}
+	return oldValue.CreatedAt, nil
+}
+
+// ResetCreatedAt resets all changes to the "created_at" field.
+func (m *MfaQrMutation) ResetCreatedAt() {
+	m.created_at = nil
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (m *MfaQrMutation) SetUpdatedAt(t time.Time) {
+	m.updated_at = &t
+}
+
+// UpdatedAt returns the value of the "updated_at" field in the mutation.
+func (m *MfaQrMutation) UpdatedAt() (r time.Time, exists bool) {
+	v := m.updated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUpdatedAt returns the old "updated_at" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUpdatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUpdatedAt: %w", err)
+	}
+	return oldValue.UpdatedAt, nil
+}
+
+// ResetUpdatedAt resets all changes to the "updated_at" field.
+func (m *MfaQrMutation) ResetUpdatedAt() {
+	m.updated_at = nil
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (m *MfaQrMutation) SetDeletedAt(t time.Time) {
+	m.deleted_at = &t
+}
+
+// DeletedAt returns the value of the "deleted_at" field in the mutation.
+func (m *MfaQrMutation) DeletedAt() (r time.Time, exists bool) {
+	v := m.deleted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDeletedAt returns the old "deleted_at" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldDeletedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDeletedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDeletedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDeletedAt: %w", err)
+	}
+	return oldValue.DeletedAt, nil
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (m *MfaQrMutation) ClearDeletedAt() {
+	m.deleted_at = nil
+	m.clearedFields[mfaqr.FieldDeletedAt] = struct{}{}
+}
+
+// DeletedAtCleared returns if the "deleted_at" field was cleared in this mutation.
+func (m *MfaQrMutation) DeletedAtCleared() bool {
+	_, ok := m.clearedFields[mfaqr.FieldDeletedAt]
+	return ok
+}
+
+// ResetDeletedAt resets all changes to the "deleted_at" field.
+func (m *MfaQrMutation) ResetDeletedAt() {
+	m.deleted_at = nil
+	delete(m.clearedFields, mfaqr.FieldDeletedAt)
+}
+
+// SetFailedAttempts sets the "failed_attempts" field.
+func (m *MfaQrMutation) SetFailedAttempts(u uint32) {
+	m.failed_attempts = &u
+	m.addfailed_attempts = nil
+}
+
+// FailedAttempts returns the value of the "failed_attempts" field in the mutation.
+func (m *MfaQrMutation) FailedAttempts() (r uint32, exists bool) {
+	v := m.failed_attempts
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldFailedAttempts returns the old "failed_attempts" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldFailedAttempts(ctx context.Context) (v uint32, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldFailedAttempts is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldFailedAttempts requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldFailedAttempts: %w", err)
+	}
+	return oldValue.FailedAttempts, nil
+}
+
+// AddFailedAttempts adds u to the "failed_attempts" field.
+func (m *MfaQrMutation) AddFailedAttempts(u int32) {
+	if m.addfailed_attempts != nil {
+		*m.addfailed_attempts += u
+	} else {
+		m.addfailed_attempts = &u
+	}
+}
+
+// AddedFailedAttempts returns the value that was added to the "failed_attempts" field in this mutation.
+func (m *MfaQrMutation) AddedFailedAttempts() (r int32, exists bool) {
+	v := m.addfailed_attempts
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetFailedAttempts resets all changes to the "failed_attempts" field.
+func (m *MfaQrMutation) ResetFailedAttempts() {
+	m.failed_attempts = nil
+	m.addfailed_attempts = nil
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (m *MfaQrMutation) SetLastFailedAt(t time.Time) {
+	m.last_failed_at = &t
+}
+
+// LastFailedAt returns the value of the "last_failed_at" field in the mutation.
+func (m *MfaQrMutation) LastFailedAt() (r time.Time, exists bool) {
+	v := m.last_failed_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLastFailedAt returns the old "last_failed_at" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldLastFailedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLastFailedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLastFailedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLastFailedAt: %w", err)
+	}
+	return oldValue.LastFailedAt, nil
+}
+
+// ClearLastFailedAt clears the value of the "last_failed_at" field.
+func (m *MfaQrMutation) ClearLastFailedAt() {
+	m.last_failed_at = nil
+	m.clearedFields[mfaqr.FieldLastFailedAt] = struct{}{}
+}
+
+// LastFailedAtCleared returns if the "last_failed_at" field was cleared in this mutation.
+func (m *MfaQrMutation) LastFailedAtCleared() bool {
+	_, ok := m.clearedFields[mfaqr.FieldLastFailedAt]
+	return ok
+}
+
+// ResetLastFailedAt resets all changes to the "last_failed_at" field.
+func (m *MfaQrMutation) ResetLastFailedAt() {
+	m.last_failed_at = nil
+	delete(m.clearedFields, mfaqr.FieldLastFailedAt)
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (m *MfaQrMutation) SetLockedUntil(t time.Time) {
+	m.locked_until = &t
+}
+
+// LockedUntil returns the value of the "locked_until" field in the mutation.
+func (m *MfaQrMutation) LockedUntil() (r time.Time, exists bool) {
+	v := m.locked_until
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLockedUntil returns the old "locked_until" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldLockedUntil(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLockedUntil is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLockedUntil requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLockedUntil: %w", err)
+	}
+	return oldValue.LockedUntil, nil
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (m *MfaQrMutation) ClearLockedUntil() {
+	m.locked_until = nil
+	m.clearedFields[mfaqr.FieldLockedUntil] = struct{}{}
+}
+
+// LockedUntilCleared returns if the "locked_until" field was cleared in this mutation.
+func (m *MfaQrMutation) LockedUntilCleared() bool {
+	_, ok := m.clearedFields[mfaqr.FieldLockedUntil]
+	return ok
+}
+
+// ResetLockedUntil resets all changes to the "locked_until" field.
+func (m *MfaQrMutation) ResetLockedUntil() {
+	m.locked_until = nil
+	delete(m.clearedFields, mfaqr.FieldLockedUntil)
+}
+
+// SetSecret sets the "secret" field.
+func (m *MfaQrMutation) SetSecret(b []byte) {
+	m.secret = &b
+}
+
+// Secret returns the value of the "secret" field in the mutation.
+func (m *MfaQrMutation) Secret() (r []byte, exists bool) {
+	v := m.secret
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSecret returns the old "secret" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldSecret(ctx context.Context) (v []byte, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSecret is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSecret requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSecret: %w", err)
+	}
+	return oldValue.Secret, nil
+}
+
+// ResetSecret resets all changes to the "secret" field.
+func (m *MfaQrMutation) ResetSecret() {
+	m.secret = nil
+}
+
+// SetUserID sets the "user_id" field.
+func (m *MfaQrMutation) SetUserID(bi binid.BinId) {
+	m.user = &bi
+}
+
+// UserID returns the value of the "user_id" field in the mutation.
+func (m *MfaQrMutation) UserID() (r binid.BinId, exists bool) {
+	v := m.user
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUserID returns the old "user_id" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldUserID(ctx context.Context) (v binid.BinId, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUserID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUserID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUserID: %w", err)
+	}
+	return oldValue.UserID, nil
+}
+
+// ResetUserID resets all changes to the "user_id" field.
+func (m *MfaQrMutation) ResetUserID() {
+	m.user = nil
+}
+
+// SetDataKeyID sets the "data_key_id" field.
+func (m *MfaQrMutation) SetDataKeyID(bi binid.BinId) {
+	m.data_key = &bi
+}
+
+// DataKeyID returns the value of the "data_key_id" field in the mutation.
+func (m *MfaQrMutation) DataKeyID() (r binid.BinId, exists bool) {
+	v := m.data_key
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDataKeyID returns the old "data_key_id" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldDataKeyID(ctx context.Context) (v *binid.BinId, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDataKeyID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDataKeyID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDataKeyID: %w", err)
+	}
+	return oldValue.DataKeyID, nil
+}
+
+// ClearDataKeyID clears the value of the "data_key_id" field.
+func (m *MfaQrMutation) ClearDataKeyID() {
+	m.data_key = nil
+	m.clearedFields[mfaqr.FieldDataKeyID] = struct{}{}
+}
+
+// DataKeyIDCleared returns if the "data_key_id" field was cleared in this mutation.
+func (m *MfaQrMutation) DataKeyIDCleared() bool {
+	_, ok := m.clearedFields[mfaqr.FieldDataKeyID]
+	return ok
+}
+
+// ResetDataKeyID resets all changes to the "data_key_id" field.
+func (m *MfaQrMutation) ResetDataKeyID() {
+	m.data_key = nil
+	delete(m.clearedFields, mfaqr.FieldDataKeyID)
+}
+
+// SetSecretBound sets the "secret_bound" field.
+func (m *MfaQrMutation) SetSecretBound(b bool) {
+	m.secret_bound = &b
+}
+
+// SecretBound returns the value of the "secret_bound" field in the mutation.
+func (m *MfaQrMutation) SecretBound() (r bool, exists bool) {
+	v := m.secret_bound
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSecretBound returns the old "secret_bound" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldSecretBound(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSecretBound is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSecretBound requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSecretBound: %w", err)
+	}
+	return oldValue.SecretBound, nil
+}
+
+// ResetSecretBound resets all changes to the "secret_bound" field.
+func (m *MfaQrMutation) ResetSecretBound() {
+	m.secret_bound = nil
+}
+
+// SetType sets the "type" field.
+func (m *MfaQrMutation) SetType(value mfaqr.Type) {
+	m._type = &value
+}
+
+// GetType returns the value of the "type" field in the mutation.
+func (m *MfaQrMutation) GetType() (r mfaqr.Type, exists bool) {
+	v := m._type
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldType returns the old "type" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldType(ctx context.Context) (v mfaqr.Type, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldType is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldType requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldType: %w", err)
+	}
+	return oldValue.Type, nil
+}
+
+// ResetType resets all changes to the "type" field.
+func (m *MfaQrMutation) ResetType() {
+	m._type = nil
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (m *MfaQrMutation) SetAlgorithm(value mfaqr.Algorithm) {
+	m.algorithm = &value
+}
+
+// Algorithm returns the value of the "algorithm" field in the mutation.
+func (m *MfaQrMutation) Algorithm() (r mfaqr.Algorithm, exists bool) {
+	v := m.algorithm
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAlgorithm returns the old "algorithm" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldAlgorithm(ctx context.Context) (v mfaqr.Algorithm, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAlgorithm is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAlgorithm requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAlgorithm: %w", err)
+	}
+	return oldValue.Algorithm, nil
+}
+
+// ResetAlgorithm resets all changes to the "algorithm" field.
+func (m *MfaQrMutation) ResetAlgorithm() {
+	m.algorithm = nil
+}
+
+// SetDigits sets the "digits" field.
+func (m *MfaQrMutation) SetDigits(u uint8) {
+	m.digits = &u
+	m.adddigits = nil
+}
+
+// Digits returns the value of the "digits" field in the mutation.
+func (m *MfaQrMutation) Digits() (r uint8, exists bool) {
+	v := m.digits
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDigits returns the old "digits" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldDigits(ctx context.Context) (v uint8, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDigits is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDigits requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDigits: %w", err)
+	}
+	return oldValue.Digits, nil
+}
+
+// AddDigits adds u to the "digits" field.
+func (m *MfaQrMutation) AddDigits(u int8) {
+	if m.adddigits != nil {
+		*m.adddigits += u
+	} else {
+		m.adddigits = &u
+	}
+}
+
+// AddedDigits returns the value that was added to the "digits" field in this mutation.
+func (m *MfaQrMutation) AddedDigits() (r int8, exists bool) {
+	v := m.adddigits
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetDigits resets all changes to the "digits" field.
+func (m *MfaQrMutation) ResetDigits() {
+	m.digits = nil
+	m.adddigits = nil
+}
+
+// SetPeriod sets the "period" field.
+func (m *MfaQrMutation) SetPeriod(u uint32) {
+	m.period = &u
+	m.addperiod = nil
+}
+
+// Period returns the value of the "period" field in the mutation.
+func (m *MfaQrMutation) Period() (r uint32, exists bool) {
+	v := m.period
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPeriod returns the old "period" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldPeriod(ctx context.Context) (v uint32, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPeriod is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPeriod requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPeriod: %w", err)
+	}
+	return oldValue.Period, nil
+}
+
+// AddPeriod adds u to the "period" field.
+func (m *MfaQrMutation) AddPeriod(u int32) {
+	if m.addperiod != nil {
+		*m.addperiod += u
+	} else {
+		m.addperiod = &u
+	}
+}
+
+// AddedPeriod returns the value that was added to the "period" field in this mutation.
+func (m *MfaQrMutation) AddedPeriod() (r int32, exists bool) {
+	v := m.addperiod
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetPeriod resets all changes to the "period" field.
+func (m *MfaQrMutation) ResetPeriod() {
+	m.period = nil
+	m.addperiod = nil
+}
+
+// SetCounter sets the "counter" field.
+func (m *MfaQrMutation) SetCounter(u uint64) {
+	m.counter = &u
+	m.addcounter = nil
+}
+
+// Counter returns the value of the "counter" field in the mutation.
+func (m *MfaQrMutation) Counter() (r uint64, exists bool) {
+	v := m.counter
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCounter returns the old "counter" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldCounter(ctx context.Context) (v uint64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCounter is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCounter requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCounter: %w", err)
+	}
+	return oldValue.Counter, nil
+}
+
+// AddCounter adds u to the "counter" field.
+func (m *MfaQrMutation) AddCounter(u int64) {
+	if m.addcounter != nil {
+		*m.addcounter += u
+	} else {
+		m.addcounter = &u
+	}
+}
+
+// AddedCounter returns the value that was added to the "counter" field in this mutation.
+func (m *MfaQrMutation) AddedCounter() (r int64, exists bool) {
+	v := m.addcounter
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetCounter resets all changes to the "counter" field.
+func (m *MfaQrMutation) ResetCounter() {
+	m.counter = nil
+	m.addcounter = nil
+}
+
+// SetLastStep sets the "last_step" field.
+func (m *MfaQrMutation) SetLastStep(u uint64) {
+	m.last_step = &u
+	m.addlast_step = nil
+}
+
+// LastStep returns the value of the "last_step" field in the mutation.
+func (m *MfaQrMutation) LastStep() (r uint64, exists bool) {
+	v := m.last_step
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLastStep returns the old "last_step" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldLastStep(ctx context.Context) (v uint64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLastStep is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLastStep requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLastStep: %w", err)
+	}
+	return oldValue.LastStep, nil
+}
+
+// AddLastStep adds u to the "last_step" field.
+func (m *MfaQrMutation) AddLastStep(u int64) {
+	if m.addlast_step != nil {
+		*m.addlast_step += u
+	} else {
+		m.addlast_step = &u
+	}
+}
+
+// AddedLastStep returns the value that was added to the "last_step" field in this mutation.
+func (m *MfaQrMutation) AddedLastStep() (r int64, exists bool) {
+	v := m.addlast_step
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetLastStep resets all changes to the "last_step" field.
+func (m *MfaQrMutation) ResetLastStep() {
+	m.last_step = nil
+	m.addlast_step = nil
+}
+
+// SetStatus sets the "status" field.
+func (m *MfaQrMutation) SetStatus(value mfaqr.Status) {
+	m.status = &value
+}
+
+// Status returns the value of the "status" field in the mutation.
+func (m *MfaQrMutation) Status() (r mfaqr.Status, exists bool) {
+	v := m.status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldStatus returns the old "status" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldStatus(ctx context.Context) (v mfaqr.Status, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldStatus: %w", err)
+	}
+	return oldValue.Status, nil
+}
+
+// ResetStatus resets all changes to the "status" field.
+func (m *MfaQrMutation) ResetStatus() {
+	m.status = nil
+}
+
+// SetExpiresAt sets the "expires_at" field.
+func (m *MfaQrMutation) SetExpiresAt(t time.Time) {
+	m.expires_at = &t
+}
+
+// ExpiresAt returns the value of the "expires_at" field in the mutation.
+func (m *MfaQrMutation) ExpiresAt() (r time.Time, exists bool) {
+	v := m.expires_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldExpiresAt returns the old "expires_at" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldExpiresAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldExpiresAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldExpiresAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldExpiresAt: %w", err)
+	}
+	return oldValue.ExpiresAt, nil
+}
+
+// ClearExpiresAt clears the value of the "expires_at" field.
+func (m *MfaQrMutation) ClearExpiresAt() {
+	m.expires_at = nil
+	m.clearedFields[mfaqr.FieldExpiresAt] = struct{}{}
+}
+
+// ExpiresAtCleared returns if the "expires_at" field was cleared in this mutation.
+func (m *MfaQrMutation) ExpiresAtCleared() bool {
+	_, ok := m.clearedFields[mfaqr.FieldExpiresAt]
+	return ok
+}
+
+// ResetExpiresAt resets all changes to the "expires_at" field.
+func (m *MfaQrMutation) ResetExpiresAt() {
+	m.expires_at = nil
+	delete(m.clearedFields, mfaqr.FieldExpiresAt)
+}
+
+// SetActivatedAt sets the "activated_at" field.
+func (m *MfaQrMutation) SetActivatedAt(t time.Time) {
+	m.activated_at = &t
+}
+
+// ActivatedAt returns the value of the "activated_at" field in the mutation.
+func (m *MfaQrMutation) ActivatedAt() (r time.Time, exists bool) {
+	v := m.activated_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldActivatedAt returns the old "activated_at" field's value of the MfaQr entity.
+// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *MfaQrMutation) OldActivatedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldActivatedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldActivatedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldActivatedAt: %w", err)
+	}
+	return oldValue.ActivatedAt, nil
+}
+
+// ClearActivatedAt clears the value of the "activated_at" field.
+func (m *MfaQrMutation) ClearActivatedAt() {
+	m.activated_at = nil
+	m.clearedFields[mfaqr.FieldActivatedAt] = struct{}{}
+}
+
+// ActivatedAtCleared returns if the "activated_at" field was cleared in this mutation.
+func (m *MfaQrMutation) ActivatedAtCleared() bool {
+	_, ok := m.clearedFields[mfaqr.FieldActivatedAt]
+	return ok
+}
+
+// ResetActivatedAt resets all changes to the "activated_at" field.
+func (m *MfaQrMutation) ResetActivatedAt() {
+	m.activated_at = nil
+	delete(m.clearedFields, mfaqr.FieldActivatedAt)
+}
+
+// ClearUser clears the "user" edge to the User entity.
+func (m *MfaQrMutation) ClearUser() {
+	m.cleareduser = true
+	m.clearedFields[mfaqr.FieldUserID] = struct{}{}
+}
+
+// UserCleared reports if the "user" edge to the User entity was cleared.
+func (m *MfaQrMutation) UserCleared() bool {
+	return m.cleareduser
+}
+
+// UserIDs returns the "user" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// UserID instead. It exists only for internal usage by the builders.
+func (m *MfaQrMutation) UserIDs() (ids []binid.BinId) {
+	if id := m.user; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetUser resets all changes to the "user" edge.
+func (m *MfaQrMutation) ResetUser() {
+	m.user = nil
+	m.cleareduser = false
+}
+
+// ClearDataKey clears the "data_key" edge to the DataKey entity.
+func (m *MfaQrMutation) ClearDataKey() {
+	m.cleareddata_key = true
+	m.clearedFields[mfaqr.FieldDataKeyID] = struct{}{}
+}
+
+// DataKeyCleared reports if the "data_key" edge to the DataKey entity was cleared.
+func (m *MfaQrMutation) DataKeyCleared() bool {
+	return m.DataKeyIDCleared() || m.cleareddata_key
+}
+
+// DataKeyIDs returns the "data_key" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// DataKeyID instead. It exists only for internal usage by the builders.
+func (m *MfaQrMutation) DataKeyIDs() (ids []binid.BinId) {
+	if id := m.data_key; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetDataKey resets all changes to the "data_key" edge.
+func (m *MfaQrMutation) ResetDataKey() {
+	m.data_key = nil
+	m.cleareddata_key = false
+}
+
+// Where appends a list predicates to the MfaQrMutation builder.
+func (m *MfaQrMutation) Where(ps ...predicate.MfaQr) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the MfaQrMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *MfaQrMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.MfaQr, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *MfaQrMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *MfaQrMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (MfaQr).
+func (m *MfaQrMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *MfaQrMutation) Fields() []string {
+	fields := make([]string, 0, 19)
+	if m.created_at != nil {
+		fields = append(fields, mfaqr.FieldCreatedAt)
+	}
+	if m.updated_at != nil {
+		fields = append(fields, mfaqr.FieldUpdatedAt)
+	}
+	if m.deleted_at != nil {
+		fields = append(fields, mfaqr.FieldDeletedAt)
+	}
+	if m.failed_attempts != nil {
+		fields = append(fields, mfaqr.FieldFailedAttempts)
+	}
+	if m.last_failed_at != nil {
+		fields = append(fields, mfaqr.FieldLastFailedAt)
+	}
+	if m.locked_until != nil {
+		fields = append(fields, mfaqr.FieldLockedUntil)
+	}
+	if m.secret != nil {
+		fields = append(fields, mfaqr.FieldSecret)
+	}
+	if m.user != nil {
+		fields = append(fields, mfaqr.FieldUserID)
+	}
+	if m.data_key != nil {
+		fields = append(fields, mfaqr.FieldDataKeyID)
+	}
+	if m.secret_bound != nil {
+		fields = append(fields, mfaqr.FieldSecretBound)
+	}
+	if m._type != nil {
+		fields = append(fields, mfaqr.FieldType)
+	}
+	if m.algorithm != nil {
+		fields = append(fields, mfaqr.FieldAlgorithm)
+	}
+	if m.digits != nil {
+		fields = append(fields, mfaqr.FieldDigits)
+	}
+	if m.period != nil {
+		fields = append(fields, mfaqr.FieldPeriod)
+	}
+	if m.counter != nil {
+		fields = append(fields, mfaqr.FieldCounter)
+	}
+	if m.last_step != nil {
+		fields = append(fields, mfaqr.FieldLastStep)
+	}
+	if m.status != nil {
+		fields = append(fields, mfaqr.FieldStatus)
+	}
+	if m.expires_at != nil {
+		fields = append(fields, mfaqr.FieldExpiresAt)
+	}
+	if m.activated_at != nil {
+		fields = append(fields, mfaqr.FieldActivatedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *MfaQrMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case mfaqr.FieldCreatedAt:
+		return m.CreatedAt()
+	case mfaqr.FieldUpdatedAt:
+		return m.UpdatedAt()
+	case mfaqr.FieldDeletedAt:
+		return m.DeletedAt()
+	case mfaqr.FieldFailedAttempts:
+		return m.FailedAttempts()
+	case mfaqr.FieldLastFailedAt:
+		return m.LastFailedAt()
+	case mfaqr.FieldLockedUntil:
+		return m.LockedUntil()
+	case mfaqr.FieldSecret:
+		return m.Secret()
+	case mfaqr.FieldUserID:
+		return m.UserID()
+	case mfaqr.FieldDataKeyID:
+		return m.DataKeyID()
+	case mfaqr.FieldSecretBound:
+		return m.SecretBound()
+	case mfaqr.FieldType:
+		return m.GetType()
+	case mfaqr.FieldAlgorithm:
+		return m.Algorithm()
+	case mfaqr.FieldDigits:
+		return m.Digits()
+	case mfaqr.FieldPeriod:
+		return m.Period()
+	case mfaqr.FieldCounter:
+		return m.Counter()
+	case mfaqr.FieldLastStep:
+		return m.LastStep()
+	case mfaqr.FieldStatus:
+		return m.Status()
+	case mfaqr.FieldExpiresAt:
+		return m.ExpiresAt()
+	case mfaqr.FieldActivatedAt:
+		return m.ActivatedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *MfaQrMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case mfaqr.FieldCreatedAt:
+		return m.OldCreatedAt(ctx)
+	case mfaqr.FieldUpdatedAt:
+		return m.OldUpdatedAt(ctx)
+	case mfaqr.FieldDeletedAt:
+		return m.OldDeletedAt(ctx)
+	case mfaqr.FieldFailedAttempts:
+		return m.OldFailedAttempts(ctx)
+	case mfaqr.FieldLastFailedAt:
+		return m.OldLastFailedAt(ctx)
+	case mfaqr.FieldLockedUntil:
+		return m.OldLockedUntil(ctx)
+	case mfaqr.FieldSecret:
+		return m.OldSecret(ctx)
+	case mfaqr.FieldUserID:
+		return m.OldUserID(ctx)
+	case mfaqr.FieldDataKeyID:
+		return m.OldDataKeyID(ctx)
+	case mfaqr.FieldSecretBound:
+		return m.OldSecretBound(ctx)
+	case mfaqr.FieldType:
+		return m.OldType(ctx)
+	case mfaqr.FieldAlgorithm:
+		return m.OldAlgorithm(ctx)
+	case mfaqr.FieldDigits:
+		return m.OldDigits(ctx)
+	case mfaqr.FieldPeriod:
+		return m.OldPeriod(ctx)
+	case mfaqr.FieldCounter:
+		return m.OldCounter(ctx)
+	case mfaqr.FieldLastStep:
+		return m.OldLastStep(ctx)
+	case mfaqr.FieldStatus:
+		return m.OldStatus(ctx)
+	case mfaqr.FieldExpiresAt:
+		return m.OldExpiresAt(ctx)
+	case mfaqr.FieldActivatedAt:
+		return m.OldActivatedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown MfaQr field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *MfaQrMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case mfaqr.FieldCreatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCreatedAt(v)
+		return nil
+	case mfaqr.FieldUpdatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUpdatedAt(v)
+		return nil
+	case mfaqr.FieldDeletedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDeletedAt(v)
+		return nil
+	case mfaqr.FieldFailedAttempts:
+		v, ok := value.(uint32)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetFailedAttempts(v)
+		return nil
+	case mfaqr.FieldLastFailedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLastFailedAt(v)
+		return nil
+	case mfaqr.FieldLockedUntil:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLockedUntil(v)
+		return nil
+	case mfaqr.FieldSecret:
+		v, ok := value.([]byte)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSecret(v)
+		return nil
+	case mfaqr.FieldUserID:
+		v, ok := value.(binid.BinId)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUserID(v)
+		return nil
+	case mfaqr.FieldDataKeyID:
+		v, ok := value.(binid.BinId)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDataKeyID(v)
+		return nil
+	case mfaqr.FieldSecretBound:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSecretBound(v)
+		return nil
+	case mfaqr.FieldType:
+		v, ok := value.(mfaqr.Type)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetType(v)
+		return nil
+	case mfaqr.FieldAlgorithm:
+		v, ok := value.(mfaqr.Algorithm)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAlgorithm(v)
+		return nil
+	case mfaqr.FieldDigits:
+		v, ok := value.(uint8)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDigits(v)
+		return nil
+	case mfaqr.FieldPeriod:
+		v, ok := value.(uint32)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPeriod(v)
+		return nil
+	case mfaqr.FieldCounter:
+		v, ok := value.(uint64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCounter(v)
+		return nil
+	case mfaqr.FieldLastStep:
+		v, ok := value.(uint64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLastStep(v)
+		return nil
+	case mfaqr.FieldStatus:
+		v, ok := value.(mfaqr.Status)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStatus(v)
+		return nil
+	case mfaqr.FieldExpiresAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetExpiresAt(v)
+		return nil
+	case mfaqr.FieldActivatedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetActivatedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown MfaQr field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *MfaQrMutation) AddedFields() []string {
+	var fields []string
+	if m.addfailed_attempts != nil {
+		fields = append(fields, mfaqr.FieldFailedAttempts)
+	}
+	if m.adddigits != nil {
+		fields = append(fields, mfaqr.FieldDigits)
+	}
+	if m.addperiod != nil {
+		fields = append(fields, mfaqr.FieldPeriod)
+	}
+	if m.addcounter != nil {
+		fields = append(fields, mfaqr.FieldCounter)
+	}
+	if m.addlast_step != nil {
+		fields = append(fields, mfaqr.FieldLastStep)
+	}
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *MfaQrMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case mfaqr.FieldFailedAttempts:
+		return m.AddedFailedAttempts()
+	case mfaqr.FieldDigits:
+		return m.AddedDigits()
+	case mfaqr.FieldPeriod:
+		return m.AddedPeriod()
+	case mfaqr.FieldCounter:
+		return m.AddedCounter()
+	case mfaqr.FieldLastStep:
+		return m.AddedLastStep()
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *MfaQrMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	case mfaqr.FieldFailedAttempts:
+		v, ok := value.(int32)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddFailedAttempts(v)
+		return nil
+	case mfaqr.FieldDigits:
+		v, ok := value.(int8)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddDigits(v)
+		return nil
+	case mfaqr.FieldPeriod:
+		v, ok := value.(int32)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddPeriod(v)
+		return nil
+	case mfaqr.FieldCounter:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddCounter(v)
+		return nil
+	case mfaqr.FieldLastStep:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddLastStep(v)
+		return nil
+	}
+	return fmt.Errorf("unknown MfaQr numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *MfaQrMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(mfaqr.FieldDeletedAt) {
+		fields = append(fields, mfaqr.FieldDeletedAt)
+	}
+	if m.FieldCleared(mfaqr.FieldLastFailedAt) {
+		fields = append(fields, mfaqr.FieldLastFailedAt)
+	}
+	if m.FieldCleared(mfaqr.FieldLockedUntil) {
+		fields = append(fields, mfaqr.FieldLockedUntil)
+	}
+	if m.FieldCleared(mfaqr.FieldDataKeyID) {
+		fields = append(fields, mfaqr.FieldDataKeyID)
+	}
+	if m.FieldCleared(mfaqr.FieldExpiresAt) {
+		fields = append(fields, mfaqr.FieldExpiresAt)
+	}
+	if m.FieldCleared(mfaqr.FieldActivatedAt) {
+		fields = append(fields, mfaqr.FieldActivatedAt)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *MfaQrMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *MfaQrMutation) ClearField(name string) error {
+	switch name {
+	case mfaqr.FieldDeletedAt:
+		m.ClearDeletedAt()
+		return nil
+	case mfaqr.FieldLastFailedAt:
+		m.ClearLastFailedAt()
+		return nil
+	case mfaqr.FieldLockedUntil:
+		m.ClearLockedUntil()
+		return nil
+	case mfaqr.FieldDataKeyID:
+		m.ClearDataKeyID()
+		return nil
+	case mfaqr.FieldExpiresAt:
+		m.ClearExpiresAt()
+		return nil
+	case mfaqr.FieldActivatedAt:
+		m.ClearActivatedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown MfaQr nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *MfaQrMutation) ResetField(name string) error {
+	switch name {
+	case mfaqr.FieldCreatedAt:
+		m.ResetCreatedAt()
+		return nil
+	case mfaqr.FieldUpdatedAt:
+		m.ResetUpdatedAt()
+		return nil
+	case mfaqr.FieldDeletedAt:
+		m.ResetDeletedAt()
+		return nil
+	case mfaqr.FieldFailedAttempts:
+		m.ResetFailedAttempts()
+		return nil
+	case mfaqr.FieldLastFailedAt:
+		m.ResetLastFailedAt()
+		return nil
+	case mfaqr.FieldLockedUntil:
+		m.ResetLockedUntil()
+		return nil
+	case mfaqr.FieldSecret:
+		m.ResetSecret()
+		return nil
+	case mfaqr.FieldUserID:
+		m.ResetUserID()
+		return nil
+	case mfaqr.FieldDataKeyID:
+		m.ResetDataKeyID()
+		return nil
+	case mfaqr.FieldSecretBound:
+		m.ResetSecretBound()
+		return nil
+	case mfaqr.FieldType:
+		m.ResetType()
+		return nil
+	case mfaqr.FieldAlgorithm:
+		m.ResetAlgorithm()
+		return nil
+	case mfaqr.FieldDigits:
+		m.ResetDigits()
+		return nil
+	case mfaqr.FieldPeriod:
+		m.ResetPeriod()
+		return nil
+	case mfaqr.FieldCounter:
+		m.ResetCounter()
+		return nil
+	case mfaqr.FieldLastStep:
+		m.ResetLastStep()
+		return nil
+	case mfaqr.FieldStatus:
+		m.ResetStatus()
+		return nil
+	case mfaqr.FieldExpiresAt:
+		m.ResetExpiresAt()
+		return nil
+	case mfaqr.FieldActivatedAt:
+		m.ResetActivatedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown MfaQr field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *MfaQrMutation) AddedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.user != nil {
+		edges = append(edges, mfaqr.EdgeUser)
+	}
+	if m.data_key != nil {
+		edges = append(edges, mfaqr.EdgeDataKey)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *MfaQrMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case mfaqr.EdgeUser:
+		if id := m.user; id != nil {
+			return []ent.Value{*id}
+		}
+	case mfaqr.EdgeDataKey:
+		if id := m.data_key; id != nil {
+			return []ent.Value{*id}
+		}
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *MfaQrMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 2)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *MfaQrMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *MfaQrMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.cleareduser {
+		edges = append(edges, mfaqr.EdgeUser)
+	}
+	if m.cleareddata_key {
+		edges = append(edges, mfaqr.EdgeDataKey)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *MfaQrMutation) EdgeCleared(name string) bool {
+	switch name {
+	case mfaqr.EdgeUser:
+		return m.cleareduser
+	case mfaqr.EdgeDataKey:
+		return m.cleareddata_key
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *MfaQrMutation) ClearEdge(name string) error {
+	switch name {
+	case mfaqr.EdgeUser:
+		m.ClearUser()
+		return nil
+	case mfaqr.EdgeDataKey:
+		m.ClearDataKey()
+		return nil
+	}
+	return fmt.Errorf("unknown MfaQr unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *MfaQrMutation) ResetEdge(name string) error {
+	switch name {
+	case mfaqr.EdgeUser:
+		m.ResetUser()
+		return nil
+	case mfaqr.EdgeDataKey:
+		m.ResetDataKey()
+		return nil
+	}
+	return fmt.Errorf("unknown MfaQr edge %s", name)
+}
+
+// RateLimitBucketMutation represents an operation that mutates the RateLimitBucket nodes in the graph.
+type RateLimitBucketMutation struct {
+	config
+	op            Op
+	typ           string
+	id            *binid.BinId
+	key           *string
+	tokens        *float64
+	addtokens     *float64
+	refilled_at   *time.Time
+	full_at       *time.Time
+	version       *uint64
+	addversion    *int64
+	clearedFields map[string]struct{}
+	done          bool
+	oldValue      func(context.Context) (*RateLimitBucket, error)
+	predicates    []predicate.RateLimitBucket
+}
+
+var _ ent.Mutation = (*RateLimitBucketMutation)(nil)
+
+// ratelimitbucketOption allows management of the mutation configuration using functional options.
+type ratelimitbucketOption func(*RateLimitBucketMutation)
+
+// newRateLimitBucketMutation creates new mutation for the RateLimitBucket entity.
+func newRateLimitBucketMutation(c config, op Op, opts ...ratelimitbucketOption) *RateLimitBucketMutation {
+	m := &RateLimitBucketMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeRateLimitBucket,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withRateLimitBucketID sets the ID field of the mutation.
+func withRateLimitBucketID(id binid.BinId) ratelimitbucketOption {
+	return func(m *RateLimitBucketMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *RateLimitBucket
+		)
+		m.oldValue = func(ctx context.Context) (*RateLimitBucket, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().RateLimitBucket.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withRateLimitBucket sets the old RateLimitBucket of the mutation.
+func withRateLimitBucket(node *RateLimitBucket) ratelimitbucketOption {
+	return func(m *RateLimitBucketMutation) {
+		m.oldValue = func(context.Context) (*RateLimitBucket, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m RateLimitBucketMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m RateLimitBucketMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("ent: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of RateLimitBucket entities.
+func (m *RateLimitBucketMutation) SetID(id binid.BinId) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *RateLimitBucketMutation) ID() (id binid.BinId, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *RateLimitBucketMutation) IDs(ctx context.Context) ([]binid.BinId, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []binid.BinId{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().RateLimitBucket.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetKey sets the "key" field.
+func (m *RateLimitBucketMutation) SetKey(s string) {
+	m.key = &s
+}
+
+// Key returns the value of the "key" field in the mutation.
+func (m *RateLimitBucketMutation) Key() (r string, exists bool) {
+	v := m.key
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldKey returns the old "key" field's value of the RateLimitBucket entity.
+// If the RateLimitBucket object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RateLimitBucketMutation) OldKey(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldKey is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldKey requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldKey: %w", err)
+	}
+	return oldValue.Key, nil
+}
+
+// ResetKey resets all changes to the "key" field.
+func (m *RateLimitBucketMutation) ResetKey() {
+	m.key = nil
+}
+
+// SetTokens sets the "tokens" field.
+func (m *RateLimitBucketMutation) SetTokens(f float64) {
+	m.tokens = &f
+	m.addtokens = nil
+}
+
+// Tokens returns the value of the "tokens" field in the mutation.
+func (m *RateLimitBucketMutation) Tokens() (r float64, exists bool) {
+	v := m.tokens
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTokens returns the old "tokens" field's value of the RateLimitBucket entity.
+// If the RateLimitBucket object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RateLimitBucketMutation) OldTokens(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTokens is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTokens requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTokens: %w", err)
+	}
+	return oldValue.Tokens, nil
+}
+
+// AddTokens adds f to the "tokens" field.
+func (m *RateLimitBucketMutation) AddTokens(f float64) {
+	if m.addtokens != nil {
+		*m.addtokens += f
+	} else {
+		m.addtokens = &f
+	}
+}
+
+// AddedTokens returns the value that was added to the "tokens" field in this mutation.
+func (m *RateLimitBucketMutation) AddedTokens() (r float64, exists bool) {
+	v := m.addtokens
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetTokens resets all changes to the "tokens" field.
+func (m *RateLimitBucketMutation) ResetTokens() {
+	m.tokens = nil
+	m.addtokens = nil
+}
+
+// SetRefilledAt sets the "refilled_at" field.
+func (m *RateLimitBucketMutation) SetRefilledAt(t time.Time) {
+	m.refilled_at = &t
+}
+
+// RefilledAt returns the value of the "refilled_at" field in the mutation.
+func (m *RateLimitBucketMutation) RefilledAt() (r time.Time, exists bool) {
+	v := m.refilled_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRefilledAt returns the old "refilled_at" field's value of the RateLimitBucket entity.
+// If the RateLimitBucket object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RateLimitBucketMutation) OldRefilledAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRefilledAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRefilledAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRefilledAt: %w", err)
+	}
+	return oldValue.RefilledAt, nil
+}
+
+// ResetRefilledAt resets all changes to the "refilled_at" field.
+func (m *RateLimitBucketMutation) ResetRefilledAt() {
+	m.refilled_at = nil
+}
+
+// SetFullAt sets the "full_at" field.
+func (m *RateLimitBucketMutation) SetFullAt(t time.Time) {
+	m.full_at = &t
+}
+
+// FullAt returns the value of the "full_at" field in the mutation.
+func (m *RateLimitBucketMutation) FullAt() (r time.Time, exists bool) {
+	v := m.full_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldFullAt returns the old "full_at" field's value of the RateLimitBucket entity.
+// If the RateLimitBucket object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RateLimitBucketMutation) OldFullAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldFullAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldFullAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldFullAt: %w", err)
+	}
+	return oldValue.FullAt, nil
+}
+
+// ResetFullAt resets all changes to the "full_at" field.
+func (m *RateLimitBucketMutation) ResetFullAt() {
+	m.full_at = nil
+}
+
+// SetVersion sets the "version" field.
+func (m *RateLimitBucketMutation) SetVersion(u uint64) {
+	m.version = &u
+	m.addversion = nil
+}
+
+// Version returns the value of the "version" field in the mutation.
+func (m *RateLimitBucketMutation) Version() (r uint64, exists bool) {
+	v := m.version
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldVersion returns the old "version" field's value of the RateLimitBucket entity.
+// If the RateLimitBucket object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RateLimitBucketMutation) OldVersion(ctx context.Context) (v uint64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldVersion is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldVersion requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldVersion: %w", err)
+	}
+	return oldValue.Version, nil
+}
+
+// AddVersion adds u to the "version" field.
+func (m *RateLimitBucketMutation) AddVersion(u int64) {
+	if m.addversion != nil {
+		*m.addversion += u
+	} else {
+		m.addversion = &u
+	}
+}
+
+// AddedVersion returns the value that was added to the "version" field in this mutation.
+func (m *RateLimitBucketMutation) AddedVersion() (r int64, exists bool) {
+	v := m.addversion
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetVersion resets all changes to the "version" field.
+func (m *RateLimitBucketMutation) ResetVersion() {
+	m.version = nil
+	m.addversion = nil
+}
+
+// Where appends a list predicates to the RateLimitBucketMutation builder.
+func (m *RateLimitBucketMutation) Where(ps ...predicate.RateLimitBucket) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the RateLimitBucketMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *RateLimitBucketMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.RateLimitBucket, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *RateLimitBucketMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *RateLimitBucketMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (RateLimitBucket).
+func (m *RateLimitBucketMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *RateLimitBucketMutation) Fields() []string {
+	fields := make([]string, 0, 5)
+	if m.key != nil {
+		fields = append(fields, ratelimitbucket.FieldKey)
+	}
+	if m.tokens != nil {
+		fields = append(fields, ratelimitbucket.FieldTokens)
+	}
+	if m.refilled_at != nil {
+		fields = append(fields, ratelimitbucket.FieldRefilledAt)
+	}
+	if m.full_at != nil {
+		fields = append(fields, ratelimitbucket.FieldFullAt)
+	}
+	if m.version != nil {
+		fields = append(fields, ratelimitbucket.FieldVersion)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *RateLimitBucketMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case ratelimitbucket.FieldKey:
+		return m.Key()
+	case ratelimitbucket.FieldTokens:
+		return m.Tokens()
+	case ratelimitbucket.FieldRefilledAt:
+		return m.RefilledAt()
+	case ratelimitbucket.FieldFullAt:
+		return m.FullAt()
+	case ratelimitbucket.FieldVersion:
+		return m.Version()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *RateLimitBucketMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case ratelimitbucket.FieldKey:
+		return m.OldKey(ctx)
+	case ratelimitbucket.FieldTokens:
+		return m.OldTokens(ctx)
+	case ratelimitbucket.FieldRefilledAt:
+		return m.OldRefilledAt(ctx)
+	case ratelimitbucket.FieldFullAt:
+		return m.OldFullAt(ctx)
+	case ratelimitbucket.FieldVersion:
+		return m.OldVersion(ctx)
+	}
+	return nil, fmt.Errorf("unknown RateLimitBucket field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *RateLimitBucketMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case ratelimitbucket.FieldKey:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetKey(v)
+		return nil
+	case ratelimitbucket.FieldTokens:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTokens(v)
+		return nil
+	case ratelimitbucket.FieldRefilledAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRefilledAt(v)
+		return nil
+	case ratelimitbucket.FieldFullAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetFullAt(v)
+		return nil
+	case ratelimitbucket.FieldVersion:
+		v, ok := value.(uint64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetVersion(v)
+		return nil
+	}
+	return fmt.Errorf("unknown RateLimitBucket field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *RateLimitBucketMutation) AddedFields() []string {
+	var fields []string
+	if m.addtokens != nil {
+		fields = append(fields, ratelimitbucket.FieldTokens)
+	}
+	if m.addversion != nil {
+		fields = append(fields, ratelimitbucket.FieldVersion)
+	}
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *RateLimitBucketMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case ratelimitbucket.FieldTokens:
+		return m.AddedTokens()
+	case ratelimitbucket.FieldVersion:
+		return m.AddedVersion()
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *RateLimitBucketMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	case ratelimitbucket.FieldTokens:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTokens(v)
+		return nil
+	case ratelimitbucket.FieldVersion:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddVersion(v)
+		return nil
+	}
+	return fmt.Errorf("unknown RateLimitBucket numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *RateLimitBucketMutation) ClearedFields() []string {
+	return nil
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *RateLimitBucketMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *RateLimitBucketMutation) ClearField(name string) error {
+	return fmt.Errorf("unknown RateLimitBucket nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *RateLimitBucketMutation) ResetField(name string) error {
+	switch name {
+	case ratelimitbucket.FieldKey:
+		m.ResetKey()
+		return nil
+	case ratelimitbucket.FieldTokens:
+		m.ResetTokens()
+		return nil
+	case ratelimitbucket.FieldRefilledAt:
+		m.ResetRefilledAt()
+		return nil
+	case ratelimitbucket.FieldFullAt:
+		m.ResetFullAt()
+		return nil
+	case ratelimitbucket.FieldVersion:
+		m.ResetVersion()
+		return nil
+	}
+	return fmt.Errorf("unknown RateLimitBucket field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *RateLimitBucketMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *RateLimitBucketMutation) AddedIDs(name string) []ent.Value {
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *RateLimitBucketMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *RateLimitBucketMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *RateLimitBucketMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *RateLimitBucketMutation) EdgeCleared(name string) bool {
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *RateLimitBucketMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown RateLimitBucket unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *RateLimitBucketMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown RateLimitBucket edge %s", name)
+}
+
+// RecoveryCodeMutation represents an operation that mutates the RecoveryCode nodes in the graph.
+type RecoveryCodeMutation struct {
 	config
 	op            Op
 	typ           string
@@ -39,26 +3904,27 @@ type MfaQrMutation struct {
 	created_at    *time.Time
 	updated_at    *time.Time
 	deleted_at    *time.Time
-	secret        *[]byte
+	hash          *string
+	used_at       *time.Time
 	clearedFields map[string]struct{}
 	user          *binid.BinId
 	cleareduser   bool
 	done          bool
-	oldValue      func(context.Context) (*MfaQr, error)
-	predicates    []predicate.MfaQr
+	oldValue      func(context.Context) (*RecoveryCode, error)
+	predicates    []predicate.RecoveryCode
 }
 
-var _ ent.Mutation = (*MfaQrMutation)(nil)
+var _ ent.Mutation = (*RecoveryCodeMutation)(nil)
 
-// mfaqrOption allows management of the mutation configuration using functional options.
-type mfaqrOption func(*MfaQrMutation)
+// recoverycodeOption allows management of the mutation configuration using functional options.
+type recoverycodeOption func(*RecoveryCodeMutation)
 
-// newMfaQrMutation creates new mutation for the MfaQr entity.
-func newMfaQrMutation(c config, op Op, opts ...mfaqrOption) *MfaQrMutation {
-	m := &MfaQrMutation{
+// newRecoveryCodeMutation creates new mutation for the RecoveryCode entity.
+func newRecoveryCodeMutation(c config, op Op, opts ...recoverycodeOption) *RecoveryCodeMutation {
+	m := &RecoveryCodeMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeMfaQr,
+		typ:           TypeRecoveryCode,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -67,20 +3933,20 @@ func newMfaQrMutation(c config, op Op, opts ...mfaqrOption) *MfaQrMutation {
 	return m
 }
 
-// withMfaQrID sets the ID field of the mutation.
-func withMfaQrID(id binid.BinId) mfaqrOption {
-	return func(m *MfaQrMutation) {
+// withRecoveryCodeID sets the ID field of the mutation.
+func withRecoveryCodeID(id binid.BinId) recoverycodeOption {
+	return func(m *RecoveryCodeMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *MfaQr
+			value *RecoveryCode
 		)
-		m.oldValue = func(ctx context.Context) (*MfaQr, error) {
+		m.oldValue = func(ctx context.Context) (*RecoveryCode, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().MfaQr.Get(ctx, id)
+					value, err = m.Client().RecoveryCode.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -89,10 +3955,10 @@ func withMfaQrID(id binid.BinId) mfaqrOption {
 	}
 }
 
-// withMfaQr sets the old MfaQr of the mutation.
-func withMfaQr(node *MfaQr) mfaqrOption {
-	return func(m *MfaQrMutation) {
-		m.oldValue = func(context.Context) (*MfaQr, error) {
+// withRecoveryCode sets the old RecoveryCode of the mutation.
+func withRecoveryCode(node *RecoveryCode) recoverycodeOption {
+	return func(m *RecoveryCodeMutation) {
+		m.oldValue = func(context.Context) (*RecoveryCode, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -101,7 +3967,7 @@ func withMfaQr(node *MfaQr) mfaqrOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m MfaQrMutation) Client() *Client {
+func (m RecoveryCodeMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -109,7 +3975,7 @@ func (m MfaQrMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m MfaQrMutation) Tx() (*Tx, error) {
+func (m RecoveryCodeMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("ent: mutation is not running in a transaction")
 	}
@@ -119,14 +3985,14 @@ func (m MfaQrMutation) Tx() (*Tx, error) {
 }
 
 // SetID sets the value of the id field. Note that this
-// operation is only accepted on creation of MfaQr entities.
-func (m *MfaQrMutation) SetID(id binid.BinId) {
+// operation is only accepted on creation of RecoveryCode entities.
+func (m *RecoveryCodeMutation) SetID(id binid.BinId) {
 	m.id = &id
 }
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *MfaQrMutation) ID() (id binid.BinId, exists bool) {
+func (m *RecoveryCodeMutation) ID() (id binid.BinId, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -137,7 +4003,7 @@ func (m *MfaQrMutation) ID() (id binid.BinId, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *MfaQrMutation) IDs(ctx context.Context) ([]binid.BinId, error) {
+func (m *RecoveryCodeMutation) IDs(ctx context.Context) ([]binid.BinId, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -146,19 +4012,19 @@ func (m *MfaQrMutation) IDs(ctx context.Context) ([]binid.BinId, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().MfaQr.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().RecoveryCode.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
 // SetCreatedAt sets the "created_at" field.
-func (m *MfaQrMutation) SetCreatedAt(t time.Time) {
+func (m *RecoveryCodeMutation) SetCreatedAt(t time.Time) {
 	m.created_at = &t
 }
 
 // CreatedAt returns the value of the "created_at" field in the mutation.
-func (m *MfaQrMutation) CreatedAt() (r time.Time, exists bool) {
+func (m *RecoveryCodeMutation) CreatedAt() (r time.Time, exists bool) {
 	v := m.created_at
 	if v == nil {
 		return
@@ -166,10 +4032,10 @@ func (m *MfaQrMutation) CreatedAt() (r time.Time, exists bool) {
 	return *v, true
 }
 
-// OldCreatedAt returns the old "created_at" field's value of the MfaQr entity.
-// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// OldCreatedAt returns the old "created_at" field's value of the RecoveryCode entity.
+// If the RecoveryCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MfaQrMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *RecoveryCodeMutation) OldCreatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldCreatedAt is only allowed on UpdateOne operations")
 	}
@@ -184,17 +4050,17 @@ func (m *MfaQrMutation) OldCreatedAt(ctx context.Context) (v time.Time, err erro
 }
 
 // ResetCreatedAt resets all changes to the "created_at" field.
-func (m *MfaQrMutation) ResetCreatedAt() {
+func (m *RecoveryCodeMutation) ResetCreatedAt() {
 	m.created_at = nil
 }
 
 // SetUpdatedAt sets the "updated_at" field.
-func (m *MfaQrMutation) SetUpdatedAt(t time.Time) {
+func (m *RecoveryCodeMutation) SetUpdatedAt(t time.Time) {
 	m.updated_at = &t
 }
 
 // UpdatedAt returns the value of the "updated_at" field in the mutation.
-func (m *MfaQrMutation) UpdatedAt() (r time.Time, exists bool) {
+func (m *RecoveryCodeMutation) UpdatedAt() (r time.Time, exists bool) {
 	v := m.updated_at
 	if v == nil {
 		return
@@ -202,10 +4068,10 @@ func (m *MfaQrMutation) UpdatedAt() (r time.Time, exists bool) {
 	return *v, true
 }
 
-// OldUpdatedAt returns the old "updated_at" field's value of the MfaQr entity.
-// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// OldUpdatedAt returns the old "updated_at" field's value of the RecoveryCode entity.
+// If the RecoveryCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MfaQrMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
+func (m *RecoveryCodeMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldUpdatedAt is only allowed on UpdateOne operations")
 	}
@@ -220,17 +4086,17 @@ func (m *MfaQrMutation) OldUpdatedAt(ctx context.Context) (v time.Time, err erro
 }
 
 // ResetUpdatedAt resets all changes to the "updated_at" field.
-func (m *MfaQrMutation) ResetUpdatedAt() {
+func (m *RecoveryCodeMutation) ResetUpdatedAt() {
 	m.updated_at = nil
 }
 
 // SetDeletedAt sets the "deleted_at" field.
-func (m *MfaQrMutation) SetDeletedAt(t time.Time) {
+func (m *RecoveryCodeMutation) SetDeletedAt(t time.Time) {
 	m.deleted_at = &t
 }
 
 // DeletedAt returns the value of the "deleted_at" field in the mutation.
-func (m *MfaQrMutation) DeletedAt() (r time.Time, exists bool) {
+func (m *RecoveryCodeMutation) DeletedAt() (r time.Time, exists bool) {
 	v := m.deleted_at
 	if v == nil {
 		return
@@ -238,10 +4104,10 @@ func (m *MfaQrMutation) DeletedAt() (r time.Time, exists bool) {
 	return *v, true
 }
 
-// OldDeletedAt returns the old "deleted_at" field's value of the MfaQr entity.
-// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// OldDeletedAt returns the old "deleted_at" field's value of the RecoveryCode entity.
+// If the RecoveryCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MfaQrMutation) OldDeletedAt(ctx context.Context) (v *time.Time, err error) {
+func (m *RecoveryCodeMutation) OldDeletedAt(ctx context.Context) (v *time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldDeletedAt is only allowed on UpdateOne operations")
 	}
@@ -256,66 +4122,66 @@ func (m *MfaQrMutation) OldDeletedAt(ctx context.Context) (v *time.Time, err err
 }
 
 // ClearDeletedAt clears the value of the "deleted_at" field.
-func (m *MfaQrMutation) ClearDeletedAt() {
+func (m *RecoveryCodeMutation) ClearDeletedAt() {
 	m.deleted_at = nil
-	m.clearedFields[mfaqr.FieldDeletedAt] = struct{}{}
+	m.clearedFields[recoverycode.FieldDeletedAt] = struct{}{}
 }
 
 // DeletedAtCleared returns if the "deleted_at" field was cleared in this mutation.
-func (m *MfaQrMutation) DeletedAtCleared() bool {
-	_, ok := m.clearedFields[mfaqr.FieldDeletedAt]
+func (m *RecoveryCodeMutation) DeletedAtCleared() bool {
+	_, ok := m.clearedFields[recoverycode.FieldDeletedAt]
 	return ok
 }
 
 // ResetDeletedAt resets all changes to the "deleted_at" field.
-func (m *MfaQrMutation) ResetDeletedAt() {
+func (m *RecoveryCodeMutation) ResetDeletedAt() {
 	m.deleted_at = nil
-	delete(m.clearedFields, mfaqr.FieldDeletedAt)
+	delete(m.clearedFields, recoverycode.FieldDeletedAt)
 }
 
-// SetSecret sets the "secret" field.
-func (m *MfaQrMutation) SetSecret(b []byte) {
-	m.secret = &b
+// SetHash sets the "hash" field.
+func (m *RecoveryCodeMutation) SetHash(s string) {
+	m.hash = &s
 }
 
-// Secret returns the value of the "secret" field in the mutation.
-func (m *MfaQrMutation) Secret() (r []byte, exists bool) {
-	v := m.secret
+// Hash returns the value of the "hash" field in the mutation.
+func (m *RecoveryCodeMutation) Hash() (r string, exists bool) {
+	v := m.hash
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldSecret returns the old "secret" field's value of the MfaQr entity.
-// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// OldHash returns the old "hash" field's value of the RecoveryCode entity.
+// If the RecoveryCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MfaQrMutation) OldSecret(ctx context.Context) (v []byte, err error) {
+func (m *RecoveryCodeMutation) OldHash(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldSecret is only allowed on UpdateOne operations")
+		return v, errors.New("OldHash is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldSecret requires an ID field in the mutation")
+		return v, errors.New("OldHash requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldSecret: %w", err)
+		return v, fmt.Errorf("querying old value for OldHash: %w", err)
 	}
-	return oldValue.Secret, nil
+	return oldValue.Hash, nil
 }
 
-// ResetSecret resets all changes to the "secret" field.
-func (m *MfaQrMutation) ResetSecret() {
-	m.secret = nil
+// ResetHash resets all changes to the "hash" field.
+func (m *RecoveryCodeMutation) ResetHash() {
+	m.hash = nil
 }
 
 // SetUserID sets the "user_id" field.
-func (m *MfaQrMutation) SetUserID(bi binid.BinId) {
+func (m *RecoveryCodeMutation) SetUserID(bi binid.BinId) {
 	m.user = &bi
 }
 
 // UserID returns the value of the "user_id" field in the mutation.
-func (m *MfaQrMutation) UserID() (r binid.BinId, exists bool) {
+func (m *RecoveryCodeMutation) UserID() (r binid.BinId, exists bool) {
 	v := m.user
 	if v == nil {
 		return
@@ -323,10 +4189,10 @@ func (m *MfaQrMutation) UserID() (r binid.BinId, exists bool) {
 	return *v, true
 }
 
-// OldUserID returns the old "user_id" field's value of the MfaQr entity.
-// If the MfaQr object wasn't provided to the builder, the object is fetched from the database.
+// OldUserID returns the old "user_id" field's value of the RecoveryCode entity.
+// If the RecoveryCode object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *MfaQrMutation) OldUserID(ctx context.Context) (v binid.BinId, err error) {
+func (m *RecoveryCodeMutation) OldUserID(ctx context.Context) (v binid.BinId, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldUserID is only allowed on UpdateOne operations")
 	}
@@ -341,25 +4207,74 @@ func (m *MfaQrMutation) OldUserID(ctx context.Context) (v binid.BinId, err error
 }
 
 // ResetUserID resets all changes to the "user_id" field.
-func (m *MfaQrMutation) ResetUserID() {
+func (m *RecoveryCodeMutation) ResetUserID() {
 	m.user = nil
 }
 
+// SetUsedAt sets the "used_at" field.
+func (m *RecoveryCodeMutation) SetUsedAt(t time.Time) {
+	m.used_at = &t
+}
+
+// UsedAt returns the value of the "used_at" field in the mutation.
+func (m *RecoveryCodeMutation) UsedAt() (r time.Time, exists bool) {
+	v := m.used_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldUsedAt returns the old "used_at" field's value of the RecoveryCode entity.
+// If the RecoveryCode object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RecoveryCodeMutation) OldUsedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldUsedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldUsedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldUsedAt: %w", err)
+	}
+	return oldValue.UsedAt, nil
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (m *RecoveryCodeMutation) ClearUsedAt() {
+	m.used_at = nil
+	m.clearedFields[recoverycode.FieldUsedAt] = struct{}{}
+}
+
+// UsedAtCleared returns if the "used_at" field was cleared in this mutation.
+func (m *RecoveryCodeMutation) UsedAtCleared() bool {
+	_, ok := m.clearedFields[recoverycode.FieldUsedAt]
+	return ok
+}
+
+// ResetUsedAt resets all changes to the "used_at" field.
+func (m *RecoveryCodeMutation) ResetUsedAt() {
+	m.used_at = nil
+	delete(m.clearedFields, recoverycode.FieldUsedAt)
+}
+
 // ClearUser clears the "user" edge to the User entity.
-func (m *MfaQrMutation) ClearUser() {
+func (m *RecoveryCodeMutation) ClearUser() {
 	m.cleareduser = true
-	m.clearedFields[mfaqr.FieldUserID] = struct{}{}
+	m.clearedFields[recoverycode.FieldUserID] = struct{}{}
 }
 
 // UserCleared reports if the "user" edge to the User entity was cleared.
-func (m *MfaQrMutation) UserCleared() bool {
+func (m *RecoveryCodeMutation) UserCleared() bool {
 	return m.cleareduser
 }
 
 // UserIDs returns the "user" edge IDs in the mutation.
 // Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
 // UserID instead. It exists only for internal usage by the builders.
-func (m *MfaQrMutation) UserIDs() (ids []binid.BinId) {
+func (m *RecoveryCodeMutation) UserIDs() (ids []binid.BinId) {
 	if id := m.user; id != nil {
 		ids = append(ids, *id)
 	}
@@ -367,20 +4282,20 @@ func (m *MfaQrMutation) UserIDs() (ids []binid.BinId) {
 }
 
 // ResetUser resets all changes to the "user" edge.
-func (m *MfaQrMutation) ResetUser() {
+func (m *RecoveryCodeMutation) ResetUser() {
 	m.user = nil
 	m.cleareduser = false
 }
 
-// Where appends a list predicates to the MfaQrMutation builder.
-func (m *MfaQrMutation) Where(ps ...predicate.MfaQr) {
+// Where appends a list predicates to the RecoveryCodeMutation builder.
+func (m *RecoveryCodeMutation) Where(ps ...predicate.RecoveryCode) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the MfaQrMutation builder. Using this method,
+// WhereP appends storage-level predicates to the RecoveryCodeMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *MfaQrMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.MfaQr, len(ps))
+func (m *RecoveryCodeMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.RecoveryCode, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -388,39 +4303,42 @@ func (m *MfaQrMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *MfaQrMutation) Op() Op {
+func (m *RecoveryCodeMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *MfaQrMutation) SetOp(op Op) {
+func (m *RecoveryCodeMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (MfaQr).
-func (m *MfaQrMutation) Type() string {
+// Type returns the node type of this mutation (RecoveryCode).
+func (m *RecoveryCodeMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *MfaQrMutation) Fields() []string {
-	fields := make([]string, 0, 5)
+func (m *RecoveryCodeMutation) Fields() []string {
+	fields := make([]string, 0, 6)
 	if m.created_at != nil {
-		fields = append(fields, mfaqr.FieldCreatedAt)
+		fields = append(fields, recoverycode.FieldCreatedAt)
 	}
 	if m.updated_at != nil {
-		fields = append(fields, mfaqr.FieldUpdatedAt)
+		fields = append(fields, recoverycode.FieldUpdatedAt)
 	}
 	if m.deleted_at != nil {
-		fields = append(fields, mfaqr.FieldDeletedAt)
+		fields = append(fields, recoverycode.FieldDeletedAt)
 	}
-	if m.secret != nil {
-		fields = append(fields, mfaqr.FieldSecret)
+	if m.hash != nil {
+		fields = append(fields, recoverycode.FieldHash)
 	}
 	if m.user != nil {
-		fields = append(fields, mfaqr.FieldUserID)
+		fields = append(fields, recoverycode.FieldUserID)
+	}
+	if m.used_at != nil {
+		fields = append(fields, recoverycode.FieldUsedAt)
 	}
 	return fields
 }
@@ -428,18 +4346,20 @@ func (m *MfaQrMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *MfaQrMutation) Field(name string) (ent.Value, bool) {
+func (m *RecoveryCodeMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case mfaqr.FieldCreatedAt:
+	case recoverycode.FieldCreatedAt:
 		return m.CreatedAt()
-	case mfaqr.FieldUpdatedAt:
+	case recoverycode.FieldUpdatedAt:
 		return m.UpdatedAt()
-	case mfaqr.FieldDeletedAt:
+	case recoverycode.FieldDeletedAt:
 		return m.DeletedAt()
-	case mfaqr.FieldSecret:
-		return m.Secret()
-	case mfaqr.FieldUserID:
+	case recoverycode.FieldHash:
+		return m.Hash()
+	case recoverycode.FieldUserID:
 		return m.UserID()
+	case recoverycode.FieldUsedAt:
+		return m.UsedAt()
 	}
 	return nil, false
 }
@@ -447,153 +4367,171 @@ func (m *MfaQrMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *MfaQrMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *RecoveryCodeMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case mfaqr.FieldCreatedAt:
+	case recoverycode.FieldCreatedAt:
 		return m.OldCreatedAt(ctx)
-	case mfaqr.FieldUpdatedAt:
+	case recoverycode.FieldUpdatedAt:
 		return m.OldUpdatedAt(ctx)
-	case mfaqr.FieldDeletedAt:
+	case recoverycode.FieldDeletedAt:
 		return m.OldDeletedAt(ctx)
-	case mfaqr.FieldSecret:
-		return m.OldSecret(ctx)
-	case mfaqr.FieldUserID:
+	case recoverycode.FieldHash:
+		return m.OldHash(ctx)
+	case recoverycode.FieldUserID:
 		return m.OldUserID(ctx)
+	case recoverycode.FieldUsedAt:
+		return m.OldUsedAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown MfaQr field %s", name)
+	return nil, fmt.Errorf("unknown RecoveryCode field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MfaQrMutation) SetField(name string, value ent.Value) error {
+func (m *RecoveryCodeMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case mfaqr.FieldCreatedAt:
+	case recoverycode.FieldCreatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetCreatedAt(v)
 		return nil
-	case mfaqr.FieldUpdatedAt:
+	case recoverycode.FieldUpdatedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetUpdatedAt(v)
 		return nil
-	case mfaqr.FieldDeletedAt:
+	case recoverycode.FieldDeletedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetDeletedAt(v)
 		return nil
-	case mfaqr.FieldSecret:
-		v, ok := value.([]byte)
+	case recoverycode.FieldHash:
+		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetSecret(v)
+		m.SetHash(v)
 		return nil
-	case mfaqr.FieldUserID:
+	case recoverycode.FieldUserID:
 		v, ok := value.(binid.BinId)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
 		m.SetUserID(v)
 		return nil
+	case recoverycode.FieldUsedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetUsedAt(v)
+		return nil
 	}
-	return fmt.Errorf("unknown MfaQr field %s", name)
+	return fmt.Errorf("unknown RecoveryCode field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *MfaQrMutation) AddedFields() []string {
+func (m *RecoveryCodeMutation) AddedFields() []string {
 	return nil
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *MfaQrMutation) AddedField(name string) (ent.Value, bool) {
+func (m *RecoveryCodeMutation) AddedField(name string) (ent.Value, bool) {
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *MfaQrMutation) AddField(name string, value ent.Value) error {
+func (m *RecoveryCodeMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown MfaQr numeric field %s", name)
+	return fmt.Errorf("unknown RecoveryCode numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *MfaQrMutation) ClearedFields() []string {
+func (m *RecoveryCodeMutation) ClearedFields() []string {
 	var fields []string
-	if m.FieldCleared(mfaqr.FieldDeletedAt) {
-		fields = append(fields, mfaqr.FieldDeletedAt)
+	if m.FieldCleared(recoverycode.FieldDeletedAt) {
+		fields = append(fields, recoverycode.FieldDeletedAt)
+	}
+	if m.FieldCleared(recoverycode.FieldUsedAt) {
+		fields = append(fields, recoverycode.FieldUsedAt)
 	}
 	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *MfaQrMutation) FieldCleared(name string) bool {
+func (m *RecoveryCodeMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *MfaQrMutation) ClearField(name string) error {
+func (m *RecoveryCodeMutation) ClearField(name string) error {
 	switch name {
-	case mfaqr.FieldDeletedAt:
+	case recoverycode.FieldDeletedAt:
 		m.ClearDeletedAt()
 		return nil
+	case recoverycode.FieldUsedAt:
+		m.ClearUsedAt()
+		return nil
 	}
-	return fmt.Errorf("unknown MfaQr nullable field %s", name)
+	return fmt.Errorf("unknown RecoveryCode nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *MfaQrMutation) ResetField(name string) error {
+func (m *RecoveryCodeMutation) ResetField(name string) error {
 	switch name {
-	case mfaqr.FieldCreatedAt:
+	case recoverycode.FieldCreatedAt:
 		m.ResetCreatedAt()
 		return nil
-	case mfaqr.FieldUpdatedAt:
+	case recoverycode.FieldUpdatedAt:
 		m.ResetUpdatedAt()
 		return nil
-	case mfaqr.FieldDeletedAt:
+	case recoverycode.FieldDeletedAt:
 		m.ResetDeletedAt()
 		return nil
-	case mfaqr.FieldSecret:
-		m.ResetSecret()
+	case recoverycode.FieldHash:
+		m.ResetHash()
 		return nil
-	case mfaqr.FieldUserID:
+	case recoverycode.FieldUserID:
 		m.ResetUserID()
 		return nil
+	case recoverycode.FieldUsedAt:
+		m.ResetUsedAt()
+		return nil
 	}
-	return fmt.Errorf("unknown MfaQr field %s", name)
+	return fmt.Errorf("unknown RecoveryCode field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *MfaQrMutation) AddedEdges() []string {
+func (m *RecoveryCodeMutation) AddedEdges() []string {
 	edges := make([]string, 0, 1)
 	if m.user != nil {
-		edges = append(edges, mfaqr.EdgeUser)
+		edges = append(edges, recoverycode.EdgeUser)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *MfaQrMutation) AddedIDs(name string) []ent.Value {
+func (m *RecoveryCodeMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case mfaqr.EdgeUser:
+	case recoverycode.EdgeUser:
 		if id := m.user; id != nil {
 			return []ent.Value{*id}
 		}
@@ -602,31 +4540,31 @@ func (m *MfaQrMutation) AddedIDs(name string) []ent.Value {
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *MfaQrMutation) RemovedEdges() []string {
+func (m *RecoveryCodeMutation) RemovedEdges() []string {
 	edges := make([]string, 0, 1)
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *MfaQrMutation) RemovedIDs(name string) []ent.Value {
+func (m *RecoveryCodeMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *MfaQrMutation) ClearedEdges() []string {
+func (m *RecoveryCodeMutation) ClearedEdges() []string {
 	edges := make([]string, 0, 1)
 	if m.cleareduser {
-		edges = append(edges, mfaqr.EdgeUser)
+		edges = append(edges, recoverycode.EdgeUser)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *MfaQrMutation) EdgeCleared(name string) bool {
+func (m *RecoveryCodeMutation) EdgeCleared(name string) bool {
 	switch name {
-	case mfaqr.EdgeUser:
+	case recoverycode.EdgeUser:
 		return m.cleareduser
 	}
 	return false
@@ -634,45 +4572,54 @@ func (m *MfaQrMutation) EdgeCleared(name string) bool {
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *MfaQrMutation) ClearEdge(name string) error {
+func (m *RecoveryCodeMutation) ClearEdge(name string) error {
 	switch name {
-	case mfaqr.EdgeUser:
+	case recoverycode.EdgeUser:
 		m.ClearUser()
 		return nil
 	}
-	return fmt.Errorf("unknown MfaQr unique edge %s", name)
+	return fmt.Errorf("unknown RecoveryCode unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *MfaQrMutation) ResetEdge(name string) error {
+func (m *RecoveryCodeMutation) ResetEdge(name string) error {
 	switch name {
-	case mfaqr.EdgeUser:
+	case recoverycode.EdgeUser:
 		m.ResetUser()
 		return nil
 	}
-	return fmt.Errorf("unknown MfaQr edge %s", name)
+	return fmt.Errorf("unknown RecoveryCode edge %s", name)
 }
 
 // UserMutation represents an operation that mutates the User nodes in the graph.
 type UserMutation struct {
 	config
-	op             Op
-	typ            string
-	id             *binid.BinId
-	created_at     *time.Time
-	updated_at     *time.Time
-	deleted_at     *time.Time
-	name           *string
-	email          *string
-	login_method   *user.LoginMethod
-	clearedFields  map[string]struct{}
-	mfa_qrs        map[binid.BinId]struct{}
-	removedmfa_qrs map[binid.BinId]struct{}
-	clearedmfa_qrs bool
-	done           bool
-	oldValue       func(context.Context) (*User, error)
-	predicates     []predicate.User
+	op                    Op
+	typ                   string
+	id                    *binid.BinId
+	created_at            *time.Time
+	updated_at            *time.Time
+	deleted_at            *time.Time
+	failed_attempts       *uint32
+	addfailed_attempts    *int32
+	last_failed_at        *time.Time
+	locked_until          *time.Time
+	name                  *string
+	email                 *string
+	login_method          *user.LoginMethod
+	clearedFields         map[string]struct{}
+	mfa_qrs               map[binid.BinId]struct{}
+	removedmfa_qrs        map[binid.BinId]struct{}
+	clearedmfa_qrs        bool
+	recovery_codes        map[binid.BinId]struct{}
+	removedrecovery_codes map[binid.BinId]struct{}
+	clearedrecovery_codes bool
+	data_key              *binid.BinId
+	cleareddata_key       bool
+	done                  bool
+	oldValue              func(context.Context) (*User, error)
+	predicates            []predicate.User
 }
 
 var _ ent.Mutation = (*UserMutation)(nil)
@@ -900,6 +4847,160 @@ func (m *UserMutation) ResetDeletedAt() {
 	delete(m.clearedFields, user.FieldDeletedAt)
 }
 
+// SetFailedAttempts sets the "failed_attempts" field.
+func (m *UserMutation) SetFailedAttempts(u uint32) {
+	m.failed_attempts = &u
+	m.addfailed_attempts = nil
+}
+
+// FailedAttempts returns the value of the "failed_attempts" field in the mutation.
+func (m *UserMutation) FailedAttempts() (r uint32, exists bool) {
+	v := m.failed_attempts
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldFailedAttempts returns the old "failed_attempts" field's value of the User entity.
+// If the User object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *UserMutation) OldFailedAttempts(ctx context.Context) (v uint32, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldFailedAttempts is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldFailedAttempts requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldFailedAttempts: %w", err)
+	}
+	return oldValue.FailedAttempts, nil
+}
+
+// AddFailedAttempts adds u to the "failed_attempts" field.
+func (m *UserMutation) AddFailedAttempts(u int32) {
+	if m.addfailed_attempts != nil {
+		*m.addfailed_attempts += u
+	} else {
+		m.addfailed_attempts = &u
+	}
+}
+
+// AddedFailedAttempts returns the value that was added to the "failed_attempts" field in this mutation.
+func (m *UserMutation) AddedFailedAttempts() (r int32, exists bool) {
+	v := m.addfailed_attempts
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetFailedAttempts resets all changes to the "failed_attempts" field.
+func (m *UserMutation) ResetFailedAttempts() {
+	m.failed_attempts = nil
+	m.addfailed_attempts = nil
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (m *UserMutation) SetLastFailedAt(t time.Time) {
+	m.last_failed_at = &t
+}
+
+// LastFailedAt returns the value of the "last_failed_at" field in the mutation.
+func (m *UserMutation) LastFailedAt() (r time.Time, exists bool) {
+	v := m.last_failed_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLastFailedAt returns the old "last_failed_at" field's value of the User entity.
+// If the User object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *UserMutation) OldLastFailedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLastFailedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLastFailedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLastFailedAt: %w", err)
+	}
+	return oldValue.LastFailedAt, nil
+}
+
+// ClearLastFailedAt clears the value of the "last_failed_at" field.
+func (m *UserMutation) ClearLastFailedAt() {
+	m.last_failed_at = nil
+	m.clearedFields[user.FieldLastFailedAt] = struct{}{}
+}
+
+// LastFailedAtCleared returns if the "last_failed_at" field was cleared in this mutation.
+func (m *UserMutation) LastFailedAtCleared() bool {
+	_, ok := m.clearedFields[user.FieldLastFailedAt]
+	return ok
+}
+
+// ResetLastFailedAt resets all changes to the "last_failed_at" field.
+func (m *UserMutation) ResetLastFailedAt() {
+	m.last_failed_at = nil
+	delete(m.clearedFields, user.FieldLastFailedAt)
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (m *UserMutation) SetLockedUntil(t time.Time) {
+	m.locked_until = &t
+}
+
+// LockedUntil returns the value of the "locked_until" field in the mutation.
+func (m *UserMutation) LockedUntil() (r time.Time, exists bool) {
+	v := m.locked_until
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLockedUntil returns the old "locked_until" field's value of the User entity.
+// If the User object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *UserMutation) OldLockedUntil(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLockedUntil is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLockedUntil requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLockedUntil: %w", err)
+	}
+	return oldValue.LockedUntil, nil
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (m *UserMutation) ClearLockedUntil() {
+	m.locked_until = nil
+	m.clearedFields[user.FieldLockedUntil] = struct{}{}
+}
+
+// LockedUntilCleared returns if the "locked_until" field was cleared in this mutation.
+func (m *UserMutation) LockedUntilCleared() bool {
+	_, ok := m.clearedFields[user.FieldLockedUntil]
+	return ok
+}
+
+// ResetLockedUntil resets all changes to the "locked_until" field.
+func (m *UserMutation) ResetLockedUntil() {
+	m.locked_until = nil
+	delete(m.clearedFields, user.FieldLockedUntil)
+}
+
 // SetName sets the "name" field.
 func (m *UserMutation) SetName(s string) {
 	m.name = &s
@@ -1062,6 +5163,99 @@ func (m *UserMutation) ResetMfaQrs() {
 	m.removedmfa_qrs = nil
 }
 
+// AddRecoveryCodeIDs adds the "recovery_codes" edge to the RecoveryCode entity by ids.
+func (m *UserMutation) AddRecoveryCodeIDs(ids ...binid.BinId) {
+	if m.recovery_codes == nil {
+		m.recovery_codes = make(map[binid.BinId]struct{})
+	}
+	for i := range ids {
+		m.recovery_codes[ids[i]] = struct{}{}
+	}
+}
+
+// ClearRecoveryCodes clears the "recovery_codes" edge to the RecoveryCode entity.
+func (m *UserMutation) ClearRecoveryCodes() {
+	m.clearedrecovery_codes = true
+}
+
+// RecoveryCodesCleared reports if the "recovery_codes" edge to the RecoveryCode entity was cleared.
+func (m *UserMutation) RecoveryCodesCleared() bool {
+	return m.clearedrecovery_codes
+}
+
+// RemoveRecoveryCodeIDs removes the "recovery_codes" edge to the RecoveryCode entity by IDs.
+func (m *UserMutation) RemoveRecoveryCodeIDs(ids ...binid.BinId) {
+	if m.removedrecovery_codes == nil {
+		m.removedrecovery_codes = make(map[binid.BinId]struct{})
+	}
+	for i := range ids {
+		delete(m.recovery_codes, ids[i])
+		m.removedrecovery_codes[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedRecoveryCodes returns the removed IDs of the "recovery_codes" edge to the RecoveryCode entity.
+func (m *UserMutation) RemovedRecoveryCodesIDs() (ids []binid.BinId) {
+	for id := range m.removedrecovery_codes {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// RecoveryCodesIDs returns the "recovery_codes" edge IDs in the mutation.
+func (m *UserMutation) RecoveryCodesIDs() (ids []binid.BinId) {
+	for id := range m.recovery_codes {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetRecoveryCodes resets all changes to the "recovery_codes" edge.
+func (m *UserMutation) ResetRecoveryCodes() {
+	m.recovery_codes = nil
+	m.clearedrecovery_codes = false
+	m.removedrecovery_codes = nil
+}
+
+// SetDataKeyID sets the "data_key" edge to the DataKey entity by id.
+func (m *UserMutation) SetDataKeyID(id binid.BinId) {
+	m.data_key = &id
+}
+
+// ClearDataKey clears the "data_key" edge to the DataKey entity.
+func (m *UserMutation) ClearDataKey() {
+	m.cleareddata_key = true
+}
+
+// DataKeyCleared reports if the "data_key" edge to the DataKey entity was cleared.
+func (m *UserMutation) DataKeyCleared() bool {
+	return m.cleareddata_key
+}
+
+// DataKeyID returns the "data_key" edge ID in the mutation.
+func (m *UserMutation) DataKeyID() (id binid.BinId, exists bool) {
+	if m.data_key != nil {
+		return *m.data_key, true
+	}
+	return
+}
+
+// DataKeyIDs returns the "data_key" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// DataKeyID instead. It exists only for internal usage by the builders.
+func (m *UserMutation) DataKeyIDs() (ids []binid.BinId) {
+	if id := m.data_key; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetDataKey resets all changes to the "data_key" edge.
+func (m *UserMutation) ResetDataKey() {
+	m.data_key = nil
+	m.cleareddata_key = false
+}
+
 // Where appends a list predicates to the UserMutation builder.
 func (m *UserMutation) Where(ps ...predicate.User) {
 	m.predicates = append(m.predicates, ps...)
@@ -1096,7 +5290,7 @@ func (m *UserMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *UserMutation) Fields() []string {
-	fields := make([]string, 0, 6)
+	fields := make([]string, 0, 9)
 	if m.created_at != nil {
 		fields = append(fields, user.FieldCreatedAt)
 	}
@@ -1106,6 +5300,15 @@ func (m *UserMutation) Fields() []string {
 	if m.deleted_at != nil {
 		fields = append(fields, user.FieldDeletedAt)
 	}
+	if m.failed_attempts != nil {
+		fields = append(fields, user.FieldFailedAttempts)
+	}
+	if m.last_failed_at != nil {
+		fields = append(fields, user.FieldLastFailedAt)
+	}
+	if m.locked_until != nil {
+		fields = append(fields, user.FieldLockedUntil)
+	}
 	if m.name != nil {
 		fields = append(fields, user.FieldName)
 	}
@@ -1129,6 +5332,12 @@ func (m *UserMutation) Field(name string) (ent.Value, bool) {
 		return m.UpdatedAt()
 	case user.FieldDeletedAt:
 		return m.DeletedAt()
+	case user.FieldFailedAttempts:
+		return m.FailedAttempts()
+	case user.FieldLastFailedAt:
+		return m.LastFailedAt()
+	case user.FieldLockedUntil:
+		return m.LockedUntil()
 	case user.FieldName:
 		return m.Name()
 	case user.FieldEmail:
@@ -1150,6 +5359,12 @@ func (m *UserMutation) OldField(ctx context.Context, name string) (ent.Value, er
 		return m.OldUpdatedAt(ctx)
 	case user.FieldDeletedAt:
 		return m.OldDeletedAt(ctx)
+	case user.FieldFailedAttempts:
+		return m.OldFailedAttempts(ctx)
+	case user.FieldLastFailedAt:
+		return m.OldLastFailedAt(ctx)
+	case user.FieldLockedUntil:
+		return m.OldLockedUntil(ctx)
 	case user.FieldName:
 		return m.OldName(ctx)
 	case user.FieldEmail:
@@ -1186,6 +5401,27 @@ func (m *UserMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetDeletedAt(v)
 		return nil
+	case user.FieldFailedAttempts:
+		v, ok := value.(uint32)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetFailedAttempts(v)
+		return nil
+	case user.FieldLastFailedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLastFailedAt(v)
+		return nil
+	case user.FieldLockedUntil:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLockedUntil(v)
+		return nil
 	case user.FieldName:
 		v, ok := value.(string)
 		if !ok {
@@ -1214,13 +5450,21 @@ func (m *UserMutation) SetField(name string, value ent.Value) error {
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
 func (m *UserMutation) AddedFields() []string {
-	return nil
+	var fields []string
+	if m.addfailed_attempts != nil {
+		fields = append(fields, user.FieldFailedAttempts)
+	}
+	return fields
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
 func (m *UserMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case user.FieldFailedAttempts:
+		return m.AddedFailedAttempts()
+	}
 	return nil, false
 }
 
@@ -1229,6 +5473,13 @@ func (m *UserMutation) AddedField(name string) (ent.Value, bool) {
 // type.
 func (m *UserMutation) AddField(name string, value ent.Value) error {
 	switch name {
+	case user.FieldFailedAttempts:
+		v, ok := value.(int32)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddFailedAttempts(v)
+		return nil
 	}
 	return fmt.Errorf("unknown User numeric field %s", name)
 }
@@ -1240,6 +5491,12 @@ func (m *UserMutation) ClearedFields() []string {
 	if m.FieldCleared(user.FieldDeletedAt) {
 		fields = append(fields, user.FieldDeletedAt)
 	}
+	if m.FieldCleared(user.FieldLastFailedAt) {
+		fields = append(fields, user.FieldLastFailedAt)
+	}
+	if m.FieldCleared(user.FieldLockedUntil) {
+		fields = append(fields, user.FieldLockedUntil)
+	}
 	return fields
 }
 
@@ -1257,6 +5514,12 @@ func (m *UserMutation) ClearField(name string) error {
 	case user.FieldDeletedAt:
 		m.ClearDeletedAt()
 		return nil
+	case user.FieldLastFailedAt:
+		m.ClearLastFailedAt()
+		return nil
+	case user.FieldLockedUntil:
+		m.ClearLockedUntil()
+		return nil
 	}
 	return fmt.Errorf("unknown User nullable field %s", name)
 }
@@ -1274,6 +5537,15 @@ func (m *UserMutation) ResetField(name string) error {
 	case user.FieldDeletedAt:
 		m.ResetDeletedAt()
 		return nil
+	case user.FieldFailedAttempts:
+		m.ResetFailedAttempts()
+		return nil
+	case user.FieldLastFailedAt:
+		m.ResetLastFailedAt()
+		return nil
+	case user.FieldLockedUntil:
+		m.ResetLockedUntil()
+		return nil
 	case user.FieldName:
 		m.ResetName()
 		return nil
@@ -1289,10 +5561,16 @@ func (m *UserMutation) ResetField(name string) error {
 
 // AddedEdges returns all edge names that were set/added in this mutation.
 func (m *UserMutation) AddedEdges() []string {
-	edges := make([]string, 0, 1)
+	edges := make([]string, 0, 3)
 	if m.mfa_qrs != nil {
 		edges = append(edges, user.EdgeMfaQrs)
 	}
+	if m.recovery_codes != nil {
+		edges = append(edges, user.EdgeRecoveryCodes)
+	}
+	if m.data_key != nil {
+		edges = append(edges, user.EdgeDataKey)
+	}
 	return edges
 }
 
@@ -1306,16 +5584,29 @@ func (m *UserMutation) AddedIDs(name string) []ent.Value {
 			ids = append(ids, id)
 		}
 		return ids
+	case user.EdgeRecoveryCodes:
+		ids := make([]ent.Value, 0, len(m.recovery_codes))
+		for id := range m.recovery_codes {
+			ids = append(ids, id)
+		}
+		return ids
+	case user.EdgeDataKey:
+		if id := m.data_key; id != nil {
+			return []ent.Value{*id}
+		}
 	}
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
 func (m *UserMutation) RemovedEdges() []string {
-	edges := make([]string, 0, 1)
+	edges := make([]string, 0, 3)
 	if m.removedmfa_qrs != nil {
 		edges = append(edges, user.EdgeMfaQrs)
 	}
+	if m.removedrecovery_codes != nil {
+		edges = append(edges, user.EdgeRecoveryCodes)
+	}
 	return edges
 }
 
@@ -1329,16 +5620,28 @@ func (m *UserMutation) RemovedIDs(name string) []ent.Value {
 			ids = append(ids, id)
 		}
 		return ids
+	case user.EdgeRecoveryCodes:
+		ids := make([]ent.Value, 0, len(m.removedrecovery_codes))
+		for id := range m.removedrecovery_codes {
+			ids = append(ids, id)
+		}
+		return ids
 	}
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
 func (m *UserMutation) ClearedEdges() []string {
-	edges := make([]string, 0, 1)
+	edges := make([]string, 0, 3)
 	if m.clearedmfa_qrs {
 		edges = append(edges, user.EdgeMfaQrs)
 	}
+	if m.clearedrecovery_codes {
+		edges = append(edges, user.EdgeRecoveryCodes)
+	}
+	if m.cleareddata_key {
+		edges = append(edges, user.EdgeDataKey)
+	}
 	return edges
 }
 
@@ -1348,6 +5651,10 @@ func (m *UserMutation) EdgeCleared(name string) bool {
 	switch name {
 	case user.EdgeMfaQrs:
 		return m.clearedmfa_qrs
+	case user.EdgeRecoveryCodes:
+		return m.clearedrecovery_codes
+	case user.EdgeDataKey:
+		return m.cleareddata_key
 	}
 	return false
 }
@@ -1356,6 +5663,9 @@ func (m *UserMutation) EdgeCleared(name string) bool {
 // if that edge is not defined in the schema.
 func (m *UserMutation) ClearEdge(name string) error {
 	switch name {
+	case user.EdgeDataKey:
+		m.ClearDataKey()
+		return nil
 	}
 	return fmt.Errorf("unknown User unique edge %s", name)
 }
@@ -1367,6 +5677,12 @@ func (m *UserMutation) ResetEdge(name string) error {
 	case user.EdgeMfaQrs:
 		m.ResetMfaQrs()
 		return nil
+	case user.EdgeRecoveryCodes:
+		m.ResetRecoveryCodes()
+		return nil
+	case user.EdgeDataKey:
+		m.ResetDataKey()
+		return nil
 	}
 	return fmt.Errorf("unknown User edge %s", name)
 }
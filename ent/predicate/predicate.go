@@ -6,8 +6,20 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// DataKey is the predicate function for datakey builders.
+type DataKey func(*sql.Selector)
+
+// JobCheckpoint is the predicate function for jobcheckpoint builders.
+type JobCheckpoint func(*sql.Selector)
+
 // MfaQr is the predicate function for mfaqr builders.
 type MfaQr func(*sql.Selector)
 
+// RateLimitBucket is the predicate function for ratelimitbucket builders.
+type RateLimitBucket func(*sql.Selector)
+
+// RecoveryCode is the predicate function for recoverycode builders.
+type RecoveryCode func(*sql.Selector)
+
 // User is the predicate function for user builders.
 type User func(*sql.Selector)
@@ -0,0 +1,155 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/ratelimitbucket"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+)
+
+// RateLimitBucket is the model entity for the RateLimitBucket schema.
+type RateLimitBucket struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID binid.BinId `json:"id,omitempty"`
+	// Key holds the value of the "key" field.
+	Key string `json:"key,omitempty"`
+	// Tokens holds the value of the "tokens" field.
+	Tokens float64 `json:"tokens,omitempty"`
+	// RefilledAt holds the value of the "refilled_at" field.
+	RefilledAt time.Time `json:"refilled_at,omitempty"`
+	// FullAt holds the value of the "full_at" field.
+	FullAt time.Time `json:"full_at,omitempty"`
+	// Version holds the value of the "version" field.
+	Version      uint64 `json:"version,omitempty"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*RateLimitBucket) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case ratelimitbucket.FieldID:
+			values[i] = new(binid.BinId)
+		case ratelimitbucket.FieldTokens:
+			values[i] = new(sql.NullFloat64)
+		case ratelimitbucket.FieldVersion:
+			values[i] = new(sql.NullInt64)
+		case ratelimitbucket.FieldKey:
+			values[i] = new(sql.NullString)
+		case ratelimitbucket.FieldRefilledAt, ratelimitbucket.FieldFullAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the RateLimitBucket fields.
+func (_m *RateLimitBucket) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case ratelimitbucket.FieldID:
+			if value, ok := values[i].(*binid.BinId); !ok {
+				return fmt.Errorf("unexpected type %T for field id", values[i])
+			} else if value != nil {
+				_m.ID = *value
+			}
+		case ratelimitbucket.FieldKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field key", values[i])
+			} else if value.Valid {
+				_m.Key = value.String
+			}
+		case ratelimitbucket.FieldTokens:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field tokens", values[i])
+			} else if value.Valid {
+				_m.Tokens = value.Float64
+			}
+		case ratelimitbucket.FieldRefilledAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field refilled_at", values[i])
+			} else if value.Valid {
+				_m.RefilledAt = value.Time
+			}
+		case ratelimitbucket.FieldFullAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field full_at", values[i])
+			} else if value.Valid {
+				_m.FullAt = value.Time
+			}
+		case ratelimitbucket.FieldVersion:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field version", values[i])
+			} else if value.Valid {
+				_m.Version = uint64(value.Int64)
+			}
+		default:
+			_m.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the RateLimitBucket.
+// This includes values selected through modifiers, order, etc.
+func (_m *RateLimitBucket) Value(name string) (ent.Value, error) {
+	return _m.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this RateLimitBucket.
+// Note that you need to call RateLimitBucket.Unwrap() before calling this method if this RateLimitBucket
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (_m *RateLimitBucket) Update() *RateLimitBucketUpdateOne {
+	return NewRateLimitBucketClient(_m.config).UpdateOne(_m)
+}
+
+// Unwrap unwraps the RateLimitBucket entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (_m *RateLimitBucket) Unwrap() *RateLimitBucket {
+	_tx, ok := _m.config.driver.(*txDriver)
+	if !ok {
+		panic("ent: RateLimitBucket is not a transactional entity")
+	}
+	_m.config.driver = _tx.drv
+	return _m
+}
+
+// String implements the fmt.Stringer.
+func (_m *RateLimitBucket) String() string {
+	var builder strings.Builder
+	builder.WriteString("RateLimitBucket(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", _m.ID))
+	builder.WriteString("key=")
+	builder.WriteString(_m.Key)
+	builder.WriteString(", ")
+	builder.WriteString("tokens=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Tokens))
+	builder.WriteString(", ")
+	builder.WriteString("refilled_at=")
+	builder.WriteString(_m.RefilledAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("full_at=")
+	builder.WriteString(_m.FullAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("version=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Version))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// RateLimitBuckets is a parsable slice of RateLimitBucket.
+type RateLimitBuckets []*RateLimitBucket
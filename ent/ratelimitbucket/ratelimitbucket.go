@@ -0,0 +1,86 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ratelimitbucket
+
+import (
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the ratelimitbucket type in the database.
+	Label = "rate_limit_bucket"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldKey holds the string denoting the key field in the database.
+	FieldKey = "key"
+	// FieldTokens holds the string denoting the tokens field in the database.
+	FieldTokens = "tokens"
+	// FieldRefilledAt holds the string denoting the refilled_at field in the database.
+	FieldRefilledAt = "refilled_at"
+	// FieldFullAt holds the string denoting the full_at field in the database.
+	FieldFullAt = "full_at"
+	// FieldVersion holds the string denoting the version field in the database.
+	FieldVersion = "version"
+	// Table holds the table name of the ratelimitbucket in the database.
+	Table = "rate_limit_buckets"
+)
+
+// Columns holds all SQL columns for ratelimitbucket fields.
+var Columns = []string{
+	FieldID,
+	FieldKey,
+	FieldTokens,
+	FieldRefilledAt,
+	FieldFullAt,
+	FieldVersion,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// KeyValidator is a validator for the "key" field. It is called by the builders before save.
+	KeyValidator func(string) error
+	// DefaultVersion holds the default value on creation for the "version" field.
+	DefaultVersion uint64
+)
+
+// OrderOption defines the ordering options for the RateLimitBucket queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByKey orders the results by the key field.
+func ByKey(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldKey, opts...).ToFunc()
+}
+
+// ByTokens orders the results by the tokens field.
+func ByTokens(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTokens, opts...).ToFunc()
+}
+
+// ByRefilledAt orders the results by the refilled_at field.
+func ByRefilledAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRefilledAt, opts...).ToFunc()
+}
+
+// ByFullAt orders the results by the full_at field.
+func ByFullAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFullAt, opts...).ToFunc()
+}
+
+// ByVersion orders the results by the version field.
+func ByVersion(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldVersion, opts...).ToFunc()
+}
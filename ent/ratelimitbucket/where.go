@@ -0,0 +1,321 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ratelimitbucket
+
+import (
+	"nidan-kai/binid"
+	"nidan-kai/ent/predicate"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id binid.BinId) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLTE(FieldID, id))
+}
+
+// Key applies equality check predicate on the "key" field. It's identical to KeyEQ.
+func Key(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldKey, v))
+}
+
+// Tokens applies equality check predicate on the "tokens" field. It's identical to TokensEQ.
+func Tokens(v float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldTokens, v))
+}
+
+// RefilledAt applies equality check predicate on the "refilled_at" field. It's identical to RefilledAtEQ.
+func RefilledAt(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldRefilledAt, v))
+}
+
+// FullAt applies equality check predicate on the "full_at" field. It's identical to FullAtEQ.
+func FullAt(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldFullAt, v))
+}
+
+// Version applies equality check predicate on the "version" field. It's identical to VersionEQ.
+func Version(v uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldVersion, v))
+}
+
+// KeyEQ applies the EQ predicate on the "key" field.
+func KeyEQ(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldKey, v))
+}
+
+// KeyNEQ applies the NEQ predicate on the "key" field.
+func KeyNEQ(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNEQ(FieldKey, v))
+}
+
+// KeyIn applies the In predicate on the "key" field.
+func KeyIn(vs ...string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldIn(FieldKey, vs...))
+}
+
+// KeyNotIn applies the NotIn predicate on the "key" field.
+func KeyNotIn(vs ...string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNotIn(FieldKey, vs...))
+}
+
+// KeyGT applies the GT predicate on the "key" field.
+func KeyGT(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGT(FieldKey, v))
+}
+
+// KeyGTE applies the GTE predicate on the "key" field.
+func KeyGTE(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGTE(FieldKey, v))
+}
+
+// KeyLT applies the LT predicate on the "key" field.
+func KeyLT(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLT(FieldKey, v))
+}
+
+// KeyLTE applies the LTE predicate on the "key" field.
+func KeyLTE(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLTE(FieldKey, v))
+}
+
+// KeyContains applies the Contains predicate on the "key" field.
+func KeyContains(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldContains(FieldKey, v))
+}
+
+// KeyHasPrefix applies the HasPrefix predicate on the "key" field.
+func KeyHasPrefix(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldHasPrefix(FieldKey, v))
+}
+
+// KeyHasSuffix applies the HasSuffix predicate on the "key" field.
+func KeyHasSuffix(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldHasSuffix(FieldKey, v))
+}
+
+// KeyEqualFold applies the EqualFold predicate on the "key" field.
+func KeyEqualFold(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEqualFold(FieldKey, v))
+}
+
+// KeyContainsFold applies the ContainsFold predicate on the "key" field.
+func KeyContainsFold(v string) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldContainsFold(FieldKey, v))
+}
+
+// TokensEQ applies the EQ predicate on the "tokens" field.
+func TokensEQ(v float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldTokens, v))
+}
+
+// TokensNEQ applies the NEQ predicate on the "tokens" field.
+func TokensNEQ(v float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNEQ(FieldTokens, v))
+}
+
+// TokensIn applies the In predicate on the "tokens" field.
+func TokensIn(vs ...float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldIn(FieldTokens, vs...))
+}
+
+// TokensNotIn applies the NotIn predicate on the "tokens" field.
+func TokensNotIn(vs ...float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNotIn(FieldTokens, vs...))
+}
+
+// TokensGT applies the GT predicate on the "tokens" field.
+func TokensGT(v float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGT(FieldTokens, v))
+}
+
+// TokensGTE applies the GTE predicate on the "tokens" field.
+func TokensGTE(v float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGTE(FieldTokens, v))
+}
+
+// TokensLT applies the LT predicate on the "tokens" field.
+func TokensLT(v float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLT(FieldTokens, v))
+}
+
+// TokensLTE applies the LTE predicate on the "tokens" field.
+func TokensLTE(v float64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLTE(FieldTokens, v))
+}
+
+// RefilledAtEQ applies the EQ predicate on the "refilled_at" field.
+func RefilledAtEQ(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldRefilledAt, v))
+}
+
+// RefilledAtNEQ applies the NEQ predicate on the "refilled_at" field.
+func RefilledAtNEQ(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNEQ(FieldRefilledAt, v))
+}
+
+// RefilledAtIn applies the In predicate on the "refilled_at" field.
+func RefilledAtIn(vs ...time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldIn(FieldRefilledAt, vs...))
+}
+
+// RefilledAtNotIn applies the NotIn predicate on the "refilled_at" field.
+func RefilledAtNotIn(vs ...time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNotIn(FieldRefilledAt, vs...))
+}
+
+// RefilledAtGT applies the GT predicate on the "refilled_at" field.
+func RefilledAtGT(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGT(FieldRefilledAt, v))
+}
+
+// RefilledAtGTE applies the GTE predicate on the "refilled_at" field.
+func RefilledAtGTE(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGTE(FieldRefilledAt, v))
+}
+
+// RefilledAtLT applies the LT predicate on the "refilled_at" field.
+func RefilledAtLT(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLT(FieldRefilledAt, v))
+}
+
+// RefilledAtLTE applies the LTE predicate on the "refilled_at" field.
+func RefilledAtLTE(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLTE(FieldRefilledAt, v))
+}
+
+// FullAtEQ applies the EQ predicate on the "full_at" field.
+func FullAtEQ(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldFullAt, v))
+}
+
+// FullAtNEQ applies the NEQ predicate on the "full_at" field.
+func FullAtNEQ(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNEQ(FieldFullAt, v))
+}
+
+// FullAtIn applies the In predicate on the "full_at" field.
+func FullAtIn(vs ...time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldIn(FieldFullAt, vs...))
+}
+
+// FullAtNotIn applies the NotIn predicate on the "full_at" field.
+func FullAtNotIn(vs ...time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNotIn(FieldFullAt, vs...))
+}
+
+// FullAtGT applies the GT predicate on the "full_at" field.
+func FullAtGT(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGT(FieldFullAt, v))
+}
+
+// FullAtGTE applies the GTE predicate on the "full_at" field.
+func FullAtGTE(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGTE(FieldFullAt, v))
+}
+
+// FullAtLT applies the LT predicate on the "full_at" field.
+func FullAtLT(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLT(FieldFullAt, v))
+}
+
+// FullAtLTE applies the LTE predicate on the "full_at" field.
+func FullAtLTE(v time.Time) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLTE(FieldFullAt, v))
+}
+
+// VersionEQ applies the EQ predicate on the "version" field.
+func VersionEQ(v uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldEQ(FieldVersion, v))
+}
+
+// VersionNEQ applies the NEQ predicate on the "version" field.
+func VersionNEQ(v uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNEQ(FieldVersion, v))
+}
+
+// VersionIn applies the In predicate on the "version" field.
+func VersionIn(vs ...uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldIn(FieldVersion, vs...))
+}
+
+// VersionNotIn applies the NotIn predicate on the "version" field.
+func VersionNotIn(vs ...uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldNotIn(FieldVersion, vs...))
+}
+
+// VersionGT applies the GT predicate on the "version" field.
+func VersionGT(v uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGT(FieldVersion, v))
+}
+
+// VersionGTE applies the GTE predicate on the "version" field.
+func VersionGTE(v uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldGTE(FieldVersion, v))
+}
+
+// VersionLT applies the LT predicate on the "version" field.
+func VersionLT(v uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLT(FieldVersion, v))
+}
+
+// VersionLTE applies the LTE predicate on the "version" field.
+func VersionLTE(v uint64) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.FieldLTE(FieldVersion, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.RateLimitBucket) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.RateLimitBucket) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.RateLimitBucket) predicate.RateLimitBucket {
+	return predicate.RateLimitBucket(sql.NotPredicates(p))
+}
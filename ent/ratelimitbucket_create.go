@@ -0,0 +1,271 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nidan-kai/binid"
+	"nidan-kai/ent/ratelimitbucket"
+	"time"
+
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// RateLimitBucketCreate is the builder for creating a RateLimitBucket entity.
+type RateLimitBucketCreate struct {
+	config
+	mutation *RateLimitBucketMutation
+	hooks    []Hook
+}
+
+// SetKey sets the "key" field.
+func (_c *RateLimitBucketCreate) SetKey(v string) *RateLimitBucketCreate {
+	_c.mutation.SetKey(v)
+	return _c
+}
+
+// SetTokens sets the "tokens" field.
+func (_c *RateLimitBucketCreate) SetTokens(v float64) *RateLimitBucketCreate {
+	_c.mutation.SetTokens(v)
+	return _c
+}
+
+// SetRefilledAt sets the "refilled_at" field.
+func (_c *RateLimitBucketCreate) SetRefilledAt(v time.Time) *RateLimitBucketCreate {
+	_c.mutation.SetRefilledAt(v)
+	return _c
+}
+
+// SetFullAt sets the "full_at" field.
+func (_c *RateLimitBucketCreate) SetFullAt(v time.Time) *RateLimitBucketCreate {
+	_c.mutation.SetFullAt(v)
+	return _c
+}
+
+// SetVersion sets the "version" field.
+func (_c *RateLimitBucketCreate) SetVersion(v uint64) *RateLimitBucketCreate {
+	_c.mutation.SetVersion(v)
+	return _c
+}
+
+// SetNillableVersion sets the "version" field if the given value is not nil.
+func (_c *RateLimitBucketCreate) SetNillableVersion(v *uint64) *RateLimitBucketCreate {
+	if v != nil {
+		_c.SetVersion(*v)
+	}
+	return _c
+}
+
+// SetID sets the "id" field.
+func (_c *RateLimitBucketCreate) SetID(v binid.BinId) *RateLimitBucketCreate {
+	_c.mutation.SetID(v)
+	return _c
+}
+
+// Mutation returns the RateLimitBucketMutation object of the builder.
+func (_c *RateLimitBucketCreate) Mutation() *RateLimitBucketMutation {
+	return _c.mutation
+}
+
+// Save creates the RateLimitBucket in the database.
+func (_c *RateLimitBucketCreate) Save(ctx context.Context) (*RateLimitBucket, error) {
+	_c.defaults()
+	return withHooks(ctx, _c.sqlSave, _c.mutation, _c.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (_c *RateLimitBucketCreate) SaveX(ctx context.Context) *RateLimitBucket {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *RateLimitBucketCreate) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *RateLimitBucketCreate) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_c *RateLimitBucketCreate) defaults() {
+	if _, ok := _c.mutation.Version(); !ok {
+		v := ratelimitbucket.DefaultVersion
+		_c.mutation.SetVersion(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_c *RateLimitBucketCreate) check() error {
+	if _, ok := _c.mutation.Key(); !ok {
+		return &ValidationError{Name: "key", err: errors.New(`ent: missing required field "RateLimitBucket.key"`)}
+	}
+	if v, ok := _c.mutation.Key(); ok {
+		if err := ratelimitbucket.KeyValidator(v); err != nil {
+			return &ValidationError{Name: "key", err: fmt.Errorf(`ent: validator failed for field "RateLimitBucket.key": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Tokens(); !ok {
+		return &ValidationError{Name: "tokens", err: errors.New(`ent: missing required field "RateLimitBucket.tokens"`)}
+	}
+	if _, ok := _c.mutation.RefilledAt(); !ok {
+		return &ValidationError{Name: "refilled_at", err: errors.New(`ent: missing required field "RateLimitBucket.refilled_at"`)}
+	}
+	if _, ok := _c.mutation.FullAt(); !ok {
+		return &ValidationError{Name: "full_at", err: errors.New(`ent: missing required field "RateLimitBucket.full_at"`)}
+	}
+	if _, ok := _c.mutation.Version(); !ok {
+		return &ValidationError{Name: "version", err: errors.New(`ent: missing required field "RateLimitBucket.version"`)}
+	}
+	return nil
+}
+
+func (_c *RateLimitBucketCreate) sqlSave(ctx context.Context) (*RateLimitBucket, error) {
+	if err := _c.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := _c.createSpec()
+	if err := sqlgraph.CreateNode(ctx, _c.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != nil {
+		if id, ok := _spec.ID.Value.(*binid.BinId); ok {
+			_node.ID = *id
+		} else if err := _node.ID.Scan(_spec.ID.Value); err != nil {
+			return nil, err
+		}
+	}
+	_c.mutation.id = &_node.ID
+	_c.mutation.done = true
+	return _node, nil
+}
+
+func (_c *RateLimitBucketCreate) createSpec() (*RateLimitBucket, *sqlgraph.CreateSpec) {
+	var (
+		_node = &RateLimitBucket{config: _c.config}
+		_spec = sqlgraph.NewCreateSpec(ratelimitbucket.Table, sqlgraph.NewFieldSpec(ratelimitbucket.FieldID, field.TypeUUID))
+	)
+	if id, ok := _c.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = &id
+	}
+	if value, ok := _c.mutation.Key(); ok {
+		_spec.SetField(ratelimitbucket.FieldKey, field.TypeString, value)
+		_node.Key = value
+	}
+	if value, ok := _c.mutation.Tokens(); ok {
+		_spec.SetField(ratelimitbucket.FieldTokens, field.TypeFloat64, value)
+		_node.Tokens = value
+	}
+	if value, ok := _c.mutation.RefilledAt(); ok {
+		_spec.SetField(ratelimitbucket.FieldRefilledAt, field.TypeTime, value)
+		_node.RefilledAt = value
+	}
+	if value, ok := _c.mutation.FullAt(); ok {
+		_spec.SetField(ratelimitbucket.FieldFullAt, field.TypeTime, value)
+		_node.FullAt = value
+	}
+	if value, ok := _c.mutation.Version(); ok {
+		_spec.SetField(ratelimitbucket.FieldVersion, field.TypeUint64, value)
+		_node.Version = value
+	}
+	return _node, _spec
+}
+
+// RateLimitBucketCreateBulk is the builder for creating many RateLimitBucket entities in bulk.
+type RateLimitBucketCreateBulk struct {
+	config
+	err      error
+	builders []*RateLimitBucketCreate
+}
+
+// Save creates the RateLimitBucket entities in the database.
+func (_c *RateLimitBucketCreateBulk) Save(ctx context.Context) ([]*RateLimitBucket, error) {
+	if _c.err != nil {
+		return nil, _c.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(_c.builders))
+	nodes := make([]*RateLimitBucket, len(_c.builders))
+	mutators := make([]Mutator, len(_c.builders))
+	for i := range _c.builders {
+		func(i int, root context.Context) {
+			builder := _c.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*RateLimitBucketMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, _c.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, _c.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, _c.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_c *RateLimitBucketCreateBulk) SaveX(ctx context.Context) []*RateLimitBucket {
+	v, err := _c.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (_c *RateLimitBucketCreateBulk) Exec(ctx context.Context) error {
+	_, err := _c.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_c *RateLimitBucketCreateBulk) ExecX(ctx context.Context) {
+	if err := _c.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,338 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"nidan-kai/ent/predicate"
+	"nidan-kai/ent/recoverycode"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+)
+
+// RecoveryCodeUpdate is the builder for updating RecoveryCode entities.
+type RecoveryCodeUpdate struct {
+	config
+	hooks    []Hook
+	mutation *RecoveryCodeMutation
+}
+
+// Where appends a list predicates to the RecoveryCodeUpdate builder.
+func (_u *RecoveryCodeUpdate) Where(ps ...predicate.RecoveryCode) *RecoveryCodeUpdate {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *RecoveryCodeUpdate) SetUpdatedAt(v time.Time) *RecoveryCodeUpdate {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_u *RecoveryCodeUpdate) SetDeletedAt(v time.Time) *RecoveryCodeUpdate {
+	_u.mutation.SetDeletedAt(v)
+	return _u
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_u *RecoveryCodeUpdate) SetNillableDeletedAt(v *time.Time) *RecoveryCodeUpdate {
+	if v != nil {
+		_u.SetDeletedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (_u *RecoveryCodeUpdate) ClearDeletedAt() *RecoveryCodeUpdate {
+	_u.mutation.ClearDeletedAt()
+	return _u
+}
+
+// SetUsedAt sets the "used_at" field.
+func (_u *RecoveryCodeUpdate) SetUsedAt(v time.Time) *RecoveryCodeUpdate {
+	_u.mutation.SetUsedAt(v)
+	return _u
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (_u *RecoveryCodeUpdate) SetNillableUsedAt(v *time.Time) *RecoveryCodeUpdate {
+	if v != nil {
+		_u.SetUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (_u *RecoveryCodeUpdate) ClearUsedAt() *RecoveryCodeUpdate {
+	_u.mutation.ClearUsedAt()
+	return _u
+}
+
+// Mutation returns the RecoveryCodeMutation object of the builder.
+func (_u *RecoveryCodeUpdate) Mutation() *RecoveryCodeMutation {
+	return _u.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (_u *RecoveryCodeUpdate) Save(ctx context.Context) (int, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *RecoveryCodeUpdate) SaveX(ctx context.Context) int {
+	affected, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (_u *RecoveryCodeUpdate) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *RecoveryCodeUpdate) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *RecoveryCodeUpdate) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := recoverycode.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *RecoveryCodeUpdate) check() error {
+	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RecoveryCode.user"`)
+	}
+	return nil
+}
+
+func (_u *RecoveryCodeUpdate) sqlSave(ctx context.Context) (_node int, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(recoverycode.Table, recoverycode.Columns, sqlgraph.NewFieldSpec(recoverycode.FieldID, field.TypeUUID))
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(recoverycode.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.DeletedAt(); ok {
+		_spec.SetField(recoverycode.FieldDeletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeletedAtCleared() {
+		_spec.ClearField(recoverycode.FieldDeletedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.UsedAt(); ok {
+		_spec.SetField(recoverycode.FieldUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.UsedAtCleared() {
+		_spec.ClearField(recoverycode.FieldUsedAt, field.TypeTime)
+	}
+	if _node, err = sqlgraph.UpdateNodes(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{recoverycode.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
+
+// RecoveryCodeUpdateOne is the builder for updating a single RecoveryCode entity.
+type RecoveryCodeUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *RecoveryCodeMutation
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (_u *RecoveryCodeUpdateOne) SetUpdatedAt(v time.Time) *RecoveryCodeUpdateOne {
+	_u.mutation.SetUpdatedAt(v)
+	return _u
+}
+
+// SetDeletedAt sets the "deleted_at" field.
+func (_u *RecoveryCodeUpdateOne) SetDeletedAt(v time.Time) *RecoveryCodeUpdateOne {
+	_u.mutation.SetDeletedAt(v)
+	return _u
+}
+
+// SetNillableDeletedAt sets the "deleted_at" field if the given value is not nil.
+func (_u *RecoveryCodeUpdateOne) SetNillableDeletedAt(v *time.Time) *RecoveryCodeUpdateOne {
+	if v != nil {
+		_u.SetDeletedAt(*v)
+	}
+	return _u
+}
+
+// ClearDeletedAt clears the value of the "deleted_at" field.
+func (_u *RecoveryCodeUpdateOne) ClearDeletedAt() *RecoveryCodeUpdateOne {
+	_u.mutation.ClearDeletedAt()
+	return _u
+}
+
+// SetUsedAt sets the "used_at" field.
+func (_u *RecoveryCodeUpdateOne) SetUsedAt(v time.Time) *RecoveryCodeUpdateOne {
+	_u.mutation.SetUsedAt(v)
+	return _u
+}
+
+// SetNillableUsedAt sets the "used_at" field if the given value is not nil.
+func (_u *RecoveryCodeUpdateOne) SetNillableUsedAt(v *time.Time) *RecoveryCodeUpdateOne {
+	if v != nil {
+		_u.SetUsedAt(*v)
+	}
+	return _u
+}
+
+// ClearUsedAt clears the value of the "used_at" field.
+func (_u *RecoveryCodeUpdateOne) ClearUsedAt() *RecoveryCodeUpdateOne {
+	_u.mutation.ClearUsedAt()
+	return _u
+}
+
+// Mutation returns the RecoveryCodeMutation object of the builder.
+func (_u *RecoveryCodeUpdateOne) Mutation() *RecoveryCodeMutation {
+	return _u.mutation
+}
+
+// Where appends a list predicates to the RecoveryCodeUpdate builder.
+func (_u *RecoveryCodeUpdateOne) Where(ps ...predicate.RecoveryCode) *RecoveryCodeUpdateOne {
+	_u.mutation.Where(ps...)
+	return _u
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (_u *RecoveryCodeUpdateOne) Select(field string, fields ...string) *RecoveryCodeUpdateOne {
+	_u.fields = append([]string{field}, fields...)
+	return _u
+}
+
+// Save executes the query and returns the updated RecoveryCode entity.
+func (_u *RecoveryCodeUpdateOne) Save(ctx context.Context) (*RecoveryCode, error) {
+	_u.defaults()
+	return withHooks(ctx, _u.sqlSave, _u.mutation, _u.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (_u *RecoveryCodeUpdateOne) SaveX(ctx context.Context) *RecoveryCode {
+	node, err := _u.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (_u *RecoveryCodeUpdateOne) Exec(ctx context.Context) error {
+	_, err := _u.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (_u *RecoveryCodeUpdateOne) ExecX(ctx context.Context) {
+	if err := _u.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (_u *RecoveryCodeUpdateOne) defaults() {
+	if _, ok := _u.mutation.UpdatedAt(); !ok {
+		v := recoverycode.UpdateDefaultUpdatedAt()
+		_u.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (_u *RecoveryCodeUpdateOne) check() error {
+	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
+		return errors.New(`ent: clearing a required unique edge "RecoveryCode.user"`)
+	}
+	return nil
+}
+
+func (_u *RecoveryCodeUpdateOne) sqlSave(ctx context.Context) (_node *RecoveryCode, err error) {
+	if err := _u.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(recoverycode.Table, recoverycode.Columns, sqlgraph.NewFieldSpec(recoverycode.FieldID, field.TypeUUID))
+	id, ok := _u.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`ent: missing "RecoveryCode.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := _u.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, recoverycode.FieldID)
+		for _, f := range fields {
+			if !recoverycode.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("ent: invalid field %q for query", f)}
+			}
+			if f != recoverycode.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := _u.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := _u.mutation.UpdatedAt(); ok {
+		_spec.SetField(recoverycode.FieldUpdatedAt, field.TypeTime, value)
+	}
+	if value, ok := _u.mutation.DeletedAt(); ok {
+		_spec.SetField(recoverycode.FieldDeletedAt, field.TypeTime, value)
+	}
+	if _u.mutation.DeletedAtCleared() {
+		_spec.ClearField(recoverycode.FieldDeletedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.UsedAt(); ok {
+		_spec.SetField(recoverycode.FieldUsedAt, field.TypeTime, value)
+	}
+	if _u.mutation.UsedAtCleared() {
+		_spec.ClearField(recoverycode.FieldUsedAt, field.TypeTime)
+	}
+	_node = &RecoveryCode{config: _u.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, _u.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{recoverycode.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	_u.mutation.done = true
+	return _node, nil
+}
@@ -3,7 +3,11 @@
 package ent
 
 import (
+	"nidan-kai/ent/datakey"
+	"nidan-kai/ent/jobcheckpoint"
 	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/ratelimitbucket"
+	"nidan-kai/ent/recoverycode"
 	"nidan-kai/ent/schema"
 	"nidan-kai/ent/user"
 	"time"
@@ -13,9 +17,81 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	datakeyMixin := schema.DataKey{}.Mixin()
+	datakeyMixinFields0 := datakeyMixin[0].Fields()
+	_ = datakeyMixinFields0
+	datakeyFields := schema.DataKey{}.Fields()
+	_ = datakeyFields
+	// datakeyDescCreatedAt is the schema descriptor for created_at field.
+	datakeyDescCreatedAt := datakeyMixinFields0[0].Descriptor()
+	// datakey.DefaultCreatedAt holds the default value on creation for the created_at field.
+	datakey.DefaultCreatedAt = datakeyDescCreatedAt.Default.(func() time.Time)
+	// datakeyDescUpdatedAt is the schema descriptor for updated_at field.
+	datakeyDescUpdatedAt := datakeyMixinFields0[1].Descriptor()
+	// datakey.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	datakey.DefaultUpdatedAt = datakeyDescUpdatedAt.Default.(func() time.Time)
+	// datakey.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	datakey.UpdateDefaultUpdatedAt = datakeyDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// datakeyDescWrappedKey is the schema descriptor for wrapped_key field.
+	datakeyDescWrappedKey := datakeyFields[1].Descriptor()
+	// datakey.WrappedKeyValidator is a validator for the "wrapped_key" field. It is called by the builders before save.
+	datakey.WrappedKeyValidator = func() func([]byte) error {
+		validators := datakeyDescWrappedKey.Validators
+		fns := [...]func([]byte) error{
+			validators[0].(func([]byte) error),
+			validators[1].(func([]byte) error),
+		}
+		return func(wrapped_key []byte) error {
+			for _, fn := range fns {
+				if err := fn(wrapped_key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	jobcheckpointMixin := schema.JobCheckpoint{}.Mixin()
+	jobcheckpointMixinFields0 := jobcheckpointMixin[0].Fields()
+	_ = jobcheckpointMixinFields0
+	jobcheckpointFields := schema.JobCheckpoint{}.Fields()
+	_ = jobcheckpointFields
+	// jobcheckpointDescCreatedAt is the schema descriptor for created_at field.
+	jobcheckpointDescCreatedAt := jobcheckpointMixinFields0[0].Descriptor()
+	// jobcheckpoint.DefaultCreatedAt holds the default value on creation for the created_at field.
+	jobcheckpoint.DefaultCreatedAt = jobcheckpointDescCreatedAt.Default.(func() time.Time)
+	// jobcheckpointDescUpdatedAt is the schema descriptor for updated_at field.
+	jobcheckpointDescUpdatedAt := jobcheckpointMixinFields0[1].Descriptor()
+	// jobcheckpoint.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	jobcheckpoint.DefaultUpdatedAt = jobcheckpointDescUpdatedAt.Default.(func() time.Time)
+	// jobcheckpoint.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	jobcheckpoint.UpdateDefaultUpdatedAt = jobcheckpointDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// jobcheckpointDescName is the schema descriptor for name field.
+	jobcheckpointDescName := jobcheckpointFields[1].Descriptor()
+	// jobcheckpoint.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	jobcheckpoint.NameValidator = func() func(string) error {
+		validators := jobcheckpointDescName.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(name string) error {
+			for _, fn := range fns {
+				if err := fn(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// jobcheckpointDescProcessed is the schema descriptor for processed field.
+	jobcheckpointDescProcessed := jobcheckpointFields[3].Descriptor()
+	// jobcheckpoint.DefaultProcessed holds the default value on creation for the processed field.
+	jobcheckpoint.DefaultProcessed = jobcheckpointDescProcessed.Default.(uint64)
 	mfaqrMixin := schema.MfaQr{}.Mixin()
 	mfaqrMixinFields0 := mfaqrMixin[0].Fields()
 	_ = mfaqrMixinFields0
+	mfaqrMixinFields1 := mfaqrMixin[1].Fields()
+	_ = mfaqrMixinFields1
 	mfaqrFields := schema.MfaQr{}.Fields()
 	_ = mfaqrFields
 	// mfaqrDescCreatedAt is the schema descriptor for created_at field.
@@ -28,6 +104,10 @@ func init() {
 	mfaqr.DefaultUpdatedAt = mfaqrDescUpdatedAt.Default.(func() time.Time)
 	// mfaqr.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	mfaqr.UpdateDefaultUpdatedAt = mfaqrDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// mfaqrDescFailedAttempts is the schema descriptor for failed_attempts field.
+	mfaqrDescFailedAttempts := mfaqrMixinFields1[0].Descriptor()
+	// mfaqr.DefaultFailedAttempts holds the default value on creation for the failed_attempts field.
+	mfaqr.DefaultFailedAttempts = mfaqrDescFailedAttempts.Default.(uint32)
 	// mfaqrDescSecret is the schema descriptor for secret field.
 	mfaqrDescSecret := mfaqrFields[1].Descriptor()
 	// mfaqr.SecretValidator is a validator for the "secret" field. It is called by the builders before save.
@@ -47,9 +127,92 @@ func init() {
 			return nil
 		}
 	}()
+	// mfaqrDescSecretBound is the schema descriptor for secret_bound field.
+	mfaqrDescSecretBound := mfaqrFields[4].Descriptor()
+	// mfaqr.DefaultSecretBound holds the default value on creation for the secret_bound field.
+	mfaqr.DefaultSecretBound = mfaqrDescSecretBound.Default.(bool)
+	// mfaqrDescDigits is the schema descriptor for digits field.
+	mfaqrDescDigits := mfaqrFields[7].Descriptor()
+	// mfaqr.DefaultDigits holds the default value on creation for the digits field.
+	mfaqr.DefaultDigits = mfaqrDescDigits.Default.(uint8)
+	// mfaqr.DigitsValidator is a validator for the "digits" field. It is called by the builders before save.
+	mfaqr.DigitsValidator = mfaqrDescDigits.Validators[0].(func(uint8) error)
+	// mfaqrDescPeriod is the schema descriptor for period field.
+	mfaqrDescPeriod := mfaqrFields[8].Descriptor()
+	// mfaqr.DefaultPeriod holds the default value on creation for the period field.
+	mfaqr.DefaultPeriod = mfaqrDescPeriod.Default.(uint32)
+	// mfaqr.PeriodValidator is a validator for the "period" field. It is called by the builders before save.
+	mfaqr.PeriodValidator = mfaqrDescPeriod.Validators[0].(func(uint32) error)
+	// mfaqrDescCounter is the schema descriptor for counter field.
+	mfaqrDescCounter := mfaqrFields[9].Descriptor()
+	// mfaqr.DefaultCounter holds the default value on creation for the counter field.
+	mfaqr.DefaultCounter = mfaqrDescCounter.Default.(uint64)
+	// mfaqrDescLastStep is the schema descriptor for last_step field.
+	mfaqrDescLastStep := mfaqrFields[10].Descriptor()
+	// mfaqr.DefaultLastStep holds the default value on creation for the last_step field.
+	mfaqr.DefaultLastStep = mfaqrDescLastStep.Default.(uint64)
+	ratelimitbucketFields := schema.RateLimitBucket{}.Fields()
+	_ = ratelimitbucketFields
+	// ratelimitbucketDescKey is the schema descriptor for key field.
+	ratelimitbucketDescKey := ratelimitbucketFields[1].Descriptor()
+	// ratelimitbucket.KeyValidator is a validator for the "key" field. It is called by the builders before save.
+	ratelimitbucket.KeyValidator = func() func(string) error {
+		validators := ratelimitbucketDescKey.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(key string) error {
+			for _, fn := range fns {
+				if err := fn(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// ratelimitbucketDescVersion is the schema descriptor for version field.
+	ratelimitbucketDescVersion := ratelimitbucketFields[5].Descriptor()
+	// ratelimitbucket.DefaultVersion holds the default value on creation for the version field.
+	ratelimitbucket.DefaultVersion = ratelimitbucketDescVersion.Default.(uint64)
+	recoverycodeMixin := schema.RecoveryCode{}.Mixin()
+	recoverycodeMixinFields0 := recoverycodeMixin[0].Fields()
+	_ = recoverycodeMixinFields0
+	recoverycodeFields := schema.RecoveryCode{}.Fields()
+	_ = recoverycodeFields
+	// recoverycodeDescCreatedAt is the schema descriptor for created_at field.
+	recoverycodeDescCreatedAt := recoverycodeMixinFields0[0].Descriptor()
+	// recoverycode.DefaultCreatedAt holds the default value on creation for the created_at field.
+	recoverycode.DefaultCreatedAt = recoverycodeDescCreatedAt.Default.(func() time.Time)
+	// recoverycodeDescUpdatedAt is the schema descriptor for updated_at field.
+	recoverycodeDescUpdatedAt := recoverycodeMixinFields0[1].Descriptor()
+	// recoverycode.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	recoverycode.DefaultUpdatedAt = recoverycodeDescUpdatedAt.Default.(func() time.Time)
+	// recoverycode.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	recoverycode.UpdateDefaultUpdatedAt = recoverycodeDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// recoverycodeDescHash is the schema descriptor for hash field.
+	recoverycodeDescHash := recoverycodeFields[1].Descriptor()
+	// recoverycode.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	recoverycode.HashValidator = func() func(string) error {
+		validators := recoverycodeDescHash.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(hash string) error {
+			for _, fn := range fns {
+				if err := fn(hash); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
 	userMixin := schema.User{}.Mixin()
 	userMixinFields0 := userMixin[0].Fields()
 	_ = userMixinFields0
+	userMixinFields1 := userMixin[1].Fields()
+	_ = userMixinFields1
 	userFields := schema.User{}.Fields()
 	_ = userFields
 	// userDescCreatedAt is the schema descriptor for created_at field.
@@ -62,6 +225,10 @@ func init() {
 	user.DefaultUpdatedAt = userDescUpdatedAt.Default.(func() time.Time)
 	// user.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	user.UpdateDefaultUpdatedAt = userDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// userDescFailedAttempts is the schema descriptor for failed_attempts field.
+	userDescFailedAttempts := userMixinFields1[0].Descriptor()
+	// user.DefaultFailedAttempts holds the default value on creation for the failed_attempts field.
+	user.DefaultFailedAttempts = userDescFailedAttempts.Default.(uint32)
 	// userDescName is the schema descriptor for name field.
 	userDescName := userFields[1].Descriptor()
 	// user.NameValidator is a validator for the "name" field. It is called by the builders before save.
@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"nidan-kai/binid"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// DataKey holds the schema definition for the DataKey entity.
+type DataKey struct {
+	ent.Schema
+}
+
+// Fields of the DataKey.
+func (DataKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", binid.BinId{}).
+			Immutable().
+			Unique().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		// data key wrapped by the master key,
+		// mutable only for re-wrapping on rotation
+		field.Bytes("wrapped_key").
+			NotEmpty().
+			MaxLen(256).
+			Sensitive().
+			SchemaType(map[string]string{dialect.MySQL: "varbinary(256)"}),
+		field.UUID("user_id", binid.BinId{}).
+			Immutable().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+	}
+}
+
+// Edges of the DataKey.
+func (DataKey) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("data_key").
+			Field("user_id").
+			Required().
+			Immutable().
+			Unique(),
+		edge.To("mfa_qrs", MfaQr.Type).
+			Immutable(),
+	}
+}
+
+func (DataKey) Indexes() []ent.Index {
+	return []ent.Index{
+		// one data key per user
+		index.Fields("user_id").Unique(),
+	}
+}
+
+func (DataKey) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		Time{},
+	}
+}
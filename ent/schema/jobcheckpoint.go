@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"nidan-kai/binid"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+)
+
+// JobCheckpoint holds the schema definition for the JobCheckpoint entity.
+type JobCheckpoint struct {
+	ent.Schema
+}
+
+// Fields of the JobCheckpoint.
+func (JobCheckpoint) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", binid.BinId{}).
+			Immutable().
+			Unique().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		field.String("name").
+			NotEmpty().
+			MaxLen(64).
+			Immutable().
+			Unique(),
+		// the last id processed, jobs resume after this
+		field.UUID("cursor", binid.BinId{}).
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		field.Uint64("processed").
+			Default(0),
+		// rows failed, retried first on the next run,
+		// the job is not completed while any is left
+		field.JSON("failed_ids", []string{}).
+			Optional(),
+		field.Time("completed_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the JobCheckpoint.
+func (JobCheckpoint) Edges() []ent.Edge {
+	return nil
+}
+
+func (JobCheckpoint) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		Time{},
+	}
+}
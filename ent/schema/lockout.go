@@ -0,0 +1,30 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+)
+
+// attempts to guess codes, kept in the database
+// so that they are counted across server instances
+type Lockout struct {
+	mixin.Schema
+}
+
+func (Lockout) Fields() []ent.Field {
+	return []ent.Field{
+		// attempts since the last success within the window,
+		// counted up before checking codes
+		field.Uint32("failed_attempts").
+			Default(0),
+		// the last attempt counted
+		field.Time("last_failed_at").
+			Optional().
+			Nillable(),
+		// codes are not checked until this
+		field.Time("locked_until").
+			Optional().
+			Nillable(),
+	}
+}
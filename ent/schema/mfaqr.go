@@ -23,15 +23,72 @@ func (MfaQr) Fields() []ent.Field {
 			Immutable().
 			Unique().
 			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		// mutable only for re-encryption,
+		// the shortest format is the one encrypted with pkcs11
 		field.Bytes("secret").
 			NotEmpty().
-			Immutable().
-			MinLen(60).
+			MinLen(53).
 			MaxLen(256).
 			SchemaType(map[string]string{dialect.MySQL: "varbinary(256)"}),
 		field.UUID("user_id", binid.BinId{}).
 			Immutable().
 			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		// secret is sealed with this data key when set,
+		// or encrypted with the master key directly until first use
+		field.UUID("data_key_id", binid.BinId{}).
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		// secret is encrypted with id and user_id as associated data,
+		// false for rows encrypted before binding
+		field.Bool("secret_bound").
+			Default(false),
+		field.Enum("type").
+			Values(
+				"totp",
+				"hotp",
+			).
+			Default("totp").
+			Immutable(),
+		field.Enum("algorithm").
+			Values(
+				"SHA1",
+				"SHA256",
+				"SHA512",
+			).
+			Default("SHA1").
+			Immutable(),
+		field.Uint8("digits").
+			Range(6, 8).
+			Default(6).
+			Immutable(),
+		// not used for hotp
+		field.Uint32("period").
+			Positive().
+			Default(30).
+			Immutable(),
+		// the next counter expected for hotp
+		field.Uint64("counter").
+			Default(0),
+		// the last time step accepted, codes at or before this are replays
+		field.Uint64("last_step").
+			Default(0),
+		// pending until the first code is confirmed
+		field.Enum("status").
+			Values(
+				"pending",
+				"active",
+			).
+			Default("active"),
+		// pending enrollment can't be confirmed after this
+		field.Time("expires_at").
+			Optional().
+			Nillable(),
+		// sessions authenticated before this are not valid anymore,
+		// created_at is taken for rows activated before this is set
+		field.Time("activated_at").
+			Optional().
+			Nillable(),
 	}
 }
 
@@ -44,6 +101,10 @@ func (MfaQr) Edges() []ent.Edge {
 			Required().
 			Immutable().
 			Unique(),
+		edge.From("data_key", DataKey.Type).
+			Ref("mfa_qrs").
+			Field("data_key_id").
+			Unique(),
 	}
 }
 
@@ -59,5 +120,6 @@ func (MfaQr) Indexes() []ent.Index {
 func (MfaQr) Mixin() []ent.Mixin {
 	return []ent.Mixin{
 		Time{},
+		Lockout{},
 	}
 }
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"nidan-kai/binid"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/field"
+)
+
+// RateLimitBucket holds the schema definition for the RateLimitBucket entity.
+type RateLimitBucket struct {
+	ent.Schema
+}
+
+// Fields of the RateLimitBucket.
+func (RateLimitBucket) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", binid.BinId{}).
+			Immutable().
+			Unique().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		// route and client ip
+		field.String("key").
+			NotEmpty().
+			MaxLen(256).
+			Immutable().
+			Unique(),
+		field.Float("tokens"),
+		// tokens are refilled from this
+		field.Time("refilled_at").
+			SchemaType(map[string]string{dialect.MySQL: "datetime(6)"}),
+		// the bucket is full again and can be removed after this
+		field.Time("full_at").
+			SchemaType(map[string]string{dialect.MySQL: "datetime(6)"}),
+		// for optimistic locking between server instances
+		field.Uint64("version").
+			Default(0),
+	}
+}
+
+// Edges of the RateLimitBucket.
+func (RateLimitBucket) Edges() []ent.Edge {
+	return nil
+}
@@ -0,0 +1,61 @@
+package schema
+
+import (
+	"nidan-kai/binid"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// RecoveryCode holds the schema definition for the RecoveryCode entity.
+type RecoveryCode struct {
+	ent.Schema
+}
+
+// Fields of the RecoveryCode.
+func (RecoveryCode) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", binid.BinId{}).
+			Immutable().
+			Unique().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		field.String("hash").
+			NotEmpty().
+			Immutable().
+			MaxLen(256).
+			Sensitive(),
+		field.UUID("user_id", binid.BinId{}).
+			Immutable().
+			SchemaType(map[string]string{dialect.MySQL: "binary(16)"}),
+		field.Time("used_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Edges of the RecoveryCode.
+func (RecoveryCode) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("recovery_codes").
+			Field("user_id").
+			Required().
+			Immutable().
+			Unique(),
+	}
+}
+
+func (RecoveryCode) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id"),
+	}
+}
+
+func (RecoveryCode) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		Time{},
+	}
+}
@@ -46,6 +46,11 @@ func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("mfa_qrs", MfaQr.Type).
 			Immutable(),
+		edge.To("recovery_codes", RecoveryCode.Type).
+			Immutable(),
+		edge.To("data_key", DataKey.Type).
+			Immutable().
+			Unique(),
 	}
 }
 
@@ -58,5 +63,6 @@ func (User) Indexes() []ent.Index {
 func (User) Mixin() []ent.Mixin {
 	return []ent.Mixin{
 		Time{},
+		Lockout{},
 	}
 }
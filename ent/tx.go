@@ -12,8 +12,16 @@ import (
 // Tx is a transactional client that is created by calling Client.Tx().
 type Tx struct {
 	config
+	// DataKey is the client for interacting with the DataKey builders.
+	DataKey *DataKeyClient
+	// JobCheckpoint is the client for interacting with the JobCheckpoint builders.
+	JobCheckpoint *JobCheckpointClient
 	// MfaQr is the client for interacting with the MfaQr builders.
 	MfaQr *MfaQrClient
+	// RateLimitBucket is the client for interacting with the RateLimitBucket builders.
+	RateLimitBucket *RateLimitBucketClient
+	// RecoveryCode is the client for interacting with the RecoveryCode builders.
+	RecoveryCode *RecoveryCodeClient
 	// User is the client for interacting with the User builders.
 	User *UserClient
 
@@ -147,7 +155,11 @@ func (tx *Tx) Client() *Client {
 }
 
 func (tx *Tx) init() {
+	tx.DataKey = NewDataKeyClient(tx.config)
+	tx.JobCheckpoint = NewJobCheckpointClient(tx.config)
 	tx.MfaQr = NewMfaQrClient(tx.config)
+	tx.RateLimitBucket = NewRateLimitBucketClient(tx.config)
+	tx.RecoveryCode = NewRecoveryCodeClient(tx.config)
 	tx.User = NewUserClient(tx.config)
 }
 
@@ -158,7 +170,7 @@ func (tx *Tx) init() {
 // of them in order to commit or rollback the transaction.
 //
 // If a closed transaction is embedded in one of the generated entities, and the entity
-// applies a query, for example: MfaQr.QueryXXX(), the query will be executed
+// applies a query, for example: DataKey.QueryXXX(), the query will be executed
 // through the driver which created this transaction.
 //
 // Note that txDriver is not goroutine safe.
@@ -7,7 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"nidan-kai/binid"
+	"nidan-kai/ent/datakey"
 	"nidan-kai/ent/mfaqr"
+	"nidan-kai/ent/recoverycode"
 	"nidan-kai/ent/user"
 	"time"
 
@@ -64,6 +66,48 @@ func (_c *UserCreate) SetNillableDeletedAt(v *time.Time) *UserCreate {
 	return _c
 }
 
+// SetFailedAttempts sets the "failed_attempts" field.
+func (_c *UserCreate) SetFailedAttempts(v uint32) *UserCreate {
+	_c.mutation.SetFailedAttempts(v)
+	return _c
+}
+
+// SetNillableFailedAttempts sets the "failed_attempts" field if the given value is not nil.
+func (_c *UserCreate) SetNillableFailedAttempts(v *uint32) *UserCreate {
+	if v != nil {
+		_c.SetFailedAttempts(*v)
+	}
+	return _c
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (_c *UserCreate) SetLastFailedAt(v time.Time) *UserCreate {
+	_c.mutation.SetLastFailedAt(v)
+	return _c
+}
+
+// SetNillableLastFailedAt sets the "last_failed_at" field if the given value is not nil.
+func (_c *UserCreate) SetNillableLastFailedAt(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetLastFailedAt(*v)
+	}
+	return _c
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (_c *UserCreate) SetLockedUntil(v time.Time) *UserCreate {
+	_c.mutation.SetLockedUntil(v)
+	return _c
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (_c *UserCreate) SetNillableLockedUntil(v *time.Time) *UserCreate {
+	if v != nil {
+		_c.SetLockedUntil(*v)
+	}
+	return _c
+}
+
 // SetName sets the "name" field.
 func (_c *UserCreate) SetName(v string) *UserCreate {
 	_c.mutation.SetName(v)
@@ -111,6 +155,40 @@ func (_c *UserCreate) AddMfaQrs(v ...*MfaQr) *UserCreate {
 	return _c.AddMfaQrIDs(ids...)
 }
 
+// AddRecoveryCodeIDs adds the "recovery_codes" edge to the RecoveryCode entity by IDs.
+func (_c *UserCreate) AddRecoveryCodeIDs(ids ...binid.BinId) *UserCreate {
+	_c.mutation.AddRecoveryCodeIDs(ids...)
+	return _c
+}
+
+// AddRecoveryCodes adds the "recovery_codes" edges to the RecoveryCode entity.
+func (_c *UserCreate) AddRecoveryCodes(v ...*RecoveryCode) *UserCreate {
+	ids := make([]binid.BinId, len(v))
+	for i := range v {
+		ids[i] = v[i].ID
+	}
+	return _c.AddRecoveryCodeIDs(ids...)
+}
+
+// SetDataKeyID sets the "data_key" edge to the DataKey entity by ID.
+func (_c *UserCreate) SetDataKeyID(id binid.BinId) *UserCreate {
+	_c.mutation.SetDataKeyID(id)
+	return _c
+}
+
+// SetNillableDataKeyID sets the "data_key" edge to the DataKey entity by ID if the given value is not nil.
+func (_c *UserCreate) SetNillableDataKeyID(id *binid.BinId) *UserCreate {
+	if id != nil {
+		_c = _c.SetDataKeyID(*id)
+	}
+	return _c
+}
+
+// SetDataKey sets the "data_key" edge to the DataKey entity.
+func (_c *UserCreate) SetDataKey(v *DataKey) *UserCreate {
+	return _c.SetDataKeyID(v.ID)
+}
+
 // Mutation returns the UserMutation object of the builder.
 func (_c *UserCreate) Mutation() *UserMutation {
 	return _c.mutation
@@ -154,6 +232,10 @@ func (_c *UserCreate) defaults() {
 		v := user.DefaultUpdatedAt()
 		_c.mutation.SetUpdatedAt(v)
 	}
+	if _, ok := _c.mutation.FailedAttempts(); !ok {
+		v := user.DefaultFailedAttempts
+		_c.mutation.SetFailedAttempts(v)
+	}
 	if _, ok := _c.mutation.LoginMethod(); !ok {
 		v := user.DefaultLoginMethod
 		_c.mutation.SetLoginMethod(v)
@@ -168,6 +250,9 @@ func (_c *UserCreate) check() error {
 	if _, ok := _c.mutation.UpdatedAt(); !ok {
 		return &ValidationError{Name: "updated_at", err: errors.New(`ent: missing required field "User.updated_at"`)}
 	}
+	if _, ok := _c.mutation.FailedAttempts(); !ok {
+		return &ValidationError{Name: "failed_attempts", err: errors.New(`ent: missing required field "User.failed_attempts"`)}
+	}
 	if _, ok := _c.mutation.Name(); !ok {
 		return &ValidationError{Name: "name", err: errors.New(`ent: missing required field "User.name"`)}
 	}
@@ -239,6 +324,18 @@ func (_c *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		_spec.SetField(user.FieldDeletedAt, field.TypeTime, value)
 		_node.DeletedAt = &value
 	}
+	if value, ok := _c.mutation.FailedAttempts(); ok {
+		_spec.SetField(user.FieldFailedAttempts, field.TypeUint32, value)
+		_node.FailedAttempts = value
+	}
+	if value, ok := _c.mutation.LastFailedAt(); ok {
+		_spec.SetField(user.FieldLastFailedAt, field.TypeTime, value)
+		_node.LastFailedAt = &value
+	}
+	if value, ok := _c.mutation.LockedUntil(); ok {
+		_spec.SetField(user.FieldLockedUntil, field.TypeTime, value)
+		_node.LockedUntil = &value
+	}
 	if value, ok := _c.mutation.Name(); ok {
 		_spec.SetField(user.FieldName, field.TypeString, value)
 		_node.Name = value
@@ -267,6 +364,38 @@ func (_c *UserCreate) createSpec() (*User, *sqlgraph.CreateSpec) {
 		}
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := _c.mutation.RecoveryCodesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   user.RecoveryCodesTable,
+			Columns: []string{user.RecoveryCodesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(recoverycode.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	if nodes := _c.mutation.DataKeyIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2O,
+			Inverse: false,
+			Table:   user.DataKeyTable,
+			Columns: []string{user.DataKeyColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(datakey.FieldID, field.TypeUUID),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
@@ -54,6 +54,67 @@ func (_u *UserUpdate) ClearDeletedAt() *UserUpdate {
 	return _u
 }
 
+// SetFailedAttempts sets the "failed_attempts" field.
+func (_u *UserUpdate) SetFailedAttempts(v uint32) *UserUpdate {
+	_u.mutation.ResetFailedAttempts()
+	_u.mutation.SetFailedAttempts(v)
+	return _u
+}
+
+// SetNillableFailedAttempts sets the "failed_attempts" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableFailedAttempts(v *uint32) *UserUpdate {
+	if v != nil {
+		_u.SetFailedAttempts(*v)
+	}
+	return _u
+}
+
+// AddFailedAttempts adds value to the "failed_attempts" field.
+func (_u *UserUpdate) AddFailedAttempts(v int32) *UserUpdate {
+	_u.mutation.AddFailedAttempts(v)
+	return _u
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (_u *UserUpdate) SetLastFailedAt(v time.Time) *UserUpdate {
+	_u.mutation.SetLastFailedAt(v)
+	return _u
+}
+
+// SetNillableLastFailedAt sets the "last_failed_at" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableLastFailedAt(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetLastFailedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastFailedAt clears the value of the "last_failed_at" field.
+func (_u *UserUpdate) ClearLastFailedAt() *UserUpdate {
+	_u.mutation.ClearLastFailedAt()
+	return _u
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (_u *UserUpdate) SetLockedUntil(v time.Time) *UserUpdate {
+	_u.mutation.SetLockedUntil(v)
+	return _u
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (_u *UserUpdate) SetNillableLockedUntil(v *time.Time) *UserUpdate {
+	if v != nil {
+		_u.SetLockedUntil(*v)
+	}
+	return _u
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (_u *UserUpdate) ClearLockedUntil() *UserUpdate {
+	_u.mutation.ClearLockedUntil()
+	return _u
+}
+
 // SetName sets the "name" field.
 func (_u *UserUpdate) SetName(v string) *UserUpdate {
 	_u.mutation.SetName(v)
@@ -178,6 +239,24 @@ func (_u *UserUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.DeletedAtCleared() {
 		_spec.ClearField(user.FieldDeletedAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.FailedAttempts(); ok {
+		_spec.SetField(user.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.AddedFailedAttempts(); ok {
+		_spec.AddField(user.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.LastFailedAt(); ok {
+		_spec.SetField(user.FieldLastFailedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastFailedAtCleared() {
+		_spec.ClearField(user.FieldLastFailedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LockedUntil(); ok {
+		_spec.SetField(user.FieldLockedUntil, field.TypeTime, value)
+	}
+	if _u.mutation.LockedUntilCleared() {
+		_spec.ClearField(user.FieldLockedUntil, field.TypeTime)
+	}
 	if value, ok := _u.mutation.Name(); ok {
 		_spec.SetField(user.FieldName, field.TypeString, value)
 	}
@@ -233,6 +312,67 @@ func (_u *UserUpdateOne) ClearDeletedAt() *UserUpdateOne {
 	return _u
 }
 
+// SetFailedAttempts sets the "failed_attempts" field.
+func (_u *UserUpdateOne) SetFailedAttempts(v uint32) *UserUpdateOne {
+	_u.mutation.ResetFailedAttempts()
+	_u.mutation.SetFailedAttempts(v)
+	return _u
+}
+
+// SetNillableFailedAttempts sets the "failed_attempts" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableFailedAttempts(v *uint32) *UserUpdateOne {
+	if v != nil {
+		_u.SetFailedAttempts(*v)
+	}
+	return _u
+}
+
+// AddFailedAttempts adds value to the "failed_attempts" field.
+func (_u *UserUpdateOne) AddFailedAttempts(v int32) *UserUpdateOne {
+	_u.mutation.AddFailedAttempts(v)
+	return _u
+}
+
+// SetLastFailedAt sets the "last_failed_at" field.
+func (_u *UserUpdateOne) SetLastFailedAt(v time.Time) *UserUpdateOne {
+	_u.mutation.SetLastFailedAt(v)
+	return _u
+}
+
+// SetNillableLastFailedAt sets the "last_failed_at" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableLastFailedAt(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetLastFailedAt(*v)
+	}
+	return _u
+}
+
+// ClearLastFailedAt clears the value of the "last_failed_at" field.
+func (_u *UserUpdateOne) ClearLastFailedAt() *UserUpdateOne {
+	_u.mutation.ClearLastFailedAt()
+	return _u
+}
+
+// SetLockedUntil sets the "locked_until" field.
+func (_u *UserUpdateOne) SetLockedUntil(v time.Time) *UserUpdateOne {
+	_u.mutation.SetLockedUntil(v)
+	return _u
+}
+
+// SetNillableLockedUntil sets the "locked_until" field if the given value is not nil.
+func (_u *UserUpdateOne) SetNillableLockedUntil(v *time.Time) *UserUpdateOne {
+	if v != nil {
+		_u.SetLockedUntil(*v)
+	}
+	return _u
+}
+
+// ClearLockedUntil clears the value of the "locked_until" field.
+func (_u *UserUpdateOne) ClearLockedUntil() *UserUpdateOne {
+	_u.mutation.ClearLockedUntil()
+	return _u
+}
+
 // SetName sets the "name" field.
 func (_u *UserUpdateOne) SetName(v string) *UserUpdateOne {
 	_u.mutation.SetName(v)
@@ -387,6 +527,24 @@ func (_u *UserUpdateOne) sqlSave(ctx context.Context) (_node *User, err error) {
 	if _u.mutation.DeletedAtCleared() {
 		_spec.ClearField(user.FieldDeletedAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.FailedAttempts(); ok {
+		_spec.SetField(user.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.AddedFailedAttempts(); ok {
+		_spec.AddField(user.FieldFailedAttempts, field.TypeUint32, value)
+	}
+	if value, ok := _u.mutation.LastFailedAt(); ok {
+		_spec.SetField(user.FieldLastFailedAt, field.TypeTime, value)
+	}
+	if _u.mutation.LastFailedAtCleared() {
+		_spec.ClearField(user.FieldLastFailedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.LockedUntil(); ok {
+		_spec.SetField(user.FieldLockedUntil, field.TypeTime, value)
+	}
+	if _u.mutation.LockedUntilCleared() {
+		_spec.ClearField(user.FieldLockedUntil, field.TypeTime)
+	}
 	if value, ok := _u.mutation.Name(); ok {
 		_spec.SetField(user.FieldName, field.TypeString, value)
 	}
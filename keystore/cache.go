@@ -0,0 +1,160 @@
+package keystore
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// keys are fetched from the keystore again after this,
+// to pick up rotation done without restarting
+const DEFAULT_CACHE_TTL = 5 * time.Minute
+
+// failed refresh is not retried for this while stale values are used
+const REFRESH_RETRY_INTERVAL = 5 * time.Second
+
+// caches values built from keystore keys, like ciphers or signing keys,
+// so that the keystore is not asked on every call,
+// raw keys are zeroed as soon as values are built
+type Cache[T any] struct {
+	store Keystore
+	ttl   time.Duration
+	build func(key []byte) (T, error)
+	// time.Now by default
+	Clock func() time.Time
+
+	mu       sync.RWMutex
+	values   []T // primary first
+	loadedAt time.Time
+	failedAt time.Time
+
+	// one refresh at a time, others keep using cached values meanwhile
+	refreshMu sync.Mutex
+}
+
+// values never expire when ttl is 0
+func NewCache[T any](store Keystore, ttl time.Duration, build func(key []byte) (T, error)) *Cache[T] {
+	return &Cache[T]{
+		store: store,
+		ttl:   ttl,
+		build: build,
+		Clock: time.Now,
+	}
+}
+
+// fetches keys from the keystore and replaces cached values
+func (c *Cache[T]) Refresh() error {
+	keys, err := GetKeys(c.store)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, key := range keys {
+			clear(key)
+		}
+	}()
+	if len(keys) == 0 {
+		return errors.New("keystore returned no key")
+	}
+
+	values := make([]T, 0, len(keys))
+	for _, key := range keys {
+		v, err := c.build(key)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+	}
+
+	c.mu.Lock()
+	c.values = values
+	c.loadedAt = c.Clock()
+	c.mu.Unlock()
+
+	return nil
+}
+
+// drops cached values, they are loaded again on the next use
+func (c *Cache[T]) Purge() {
+	c.mu.Lock()
+	c.values = nil
+	c.mu.Unlock()
+}
+
+// returns cached values, the primary first, refreshing them after ttl
+func (c *Cache[T]) Get() ([]T, error) {
+	c.mu.RLock()
+	values := c.values
+	fresh := c.ttl <= 0 || c.Clock().Sub(c.loadedAt) < c.ttl
+	c.mu.RUnlock()
+
+	if len(values) > 0 && fresh {
+		return values, nil
+	}
+
+	return c.refresh(values)
+}
+
+// refreshes once for concurrent callers, and falls back to stale values
+// so that a keystore hiccup doesn't fail every call
+func (c *Cache[T]) refresh(stale []T) ([]T, error) {
+	if len(stale) == 0 {
+		c.refreshMu.Lock()
+	} else if !c.refreshMu.TryLock() {
+		// being refreshed by others
+		return stale, nil
+	}
+	defer c.refreshMu.Unlock()
+
+	now := c.Clock()
+	c.mu.RLock()
+	values := c.values
+	fresh := c.ttl <= 0 || now.Sub(c.loadedAt) < c.ttl
+	retrying := now.Sub(c.failedAt) < REFRESH_RETRY_INTERVAL
+	c.mu.RUnlock()
+
+	// refreshed by others while waiting, or failed just now
+	if len(values) > 0 && (fresh || retrying) {
+		return values, nil
+	}
+
+	if err := c.Refresh(); err != nil {
+		if len(values) == 0 {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.failedAt = now
+		c.mu.Unlock()
+		return values, nil
+	}
+
+	return c.current(), nil
+}
+
+// refreshes for a key newer than the cached ones, used by others already,
+// unless missing is false for the values refreshed while waiting,
+// or they are loaded within minInterval
+func (c *Cache[T]) Reload(missing func(values []T) bool, minInterval time.Duration) ([]T, error) {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	c.mu.RLock()
+	values := c.values
+	recent := c.Clock().Sub(c.loadedAt) < minInterval
+	c.mu.RUnlock()
+	if !missing(values) || recent {
+		return values, nil
+	}
+
+	if err := c.Refresh(); err != nil {
+		return nil, err
+	}
+
+	return c.current(), nil
+}
+
+func (c *Cache[T]) current() []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.values
+}
@@ -0,0 +1,176 @@
+package keystore
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// counts fetches, fails while down, blocks while gate is set
+type flakyKey struct {
+	mu    sync.Mutex
+	keys  [][]byte
+	count int
+	down  bool
+	gate  chan struct{}
+}
+
+func (f *flakyKey) Init() error {
+	return nil
+}
+
+func (f *flakyKey) GetKey() ([]byte, error) {
+	keys, err := f.GetKeys()
+	if err != nil {
+		return nil, err
+	}
+	return keys[0], nil
+}
+
+func (f *flakyKey) GetKeys() ([][]byte, error) {
+	f.mu.Lock()
+	f.count++
+	keys, down, gate := f.keys, f.down, f.gate
+	f.mu.Unlock()
+
+	if gate != nil {
+		<-gate
+	}
+	if down {
+		return nil, errors.New("keystore is down")
+	}
+
+	// copies as the cache zeroes them
+	copied := make([][]byte, len(keys))
+	for i, key := range keys {
+		copied[i] = bytes.Clone(key)
+	}
+	return copied, nil
+}
+
+func idOf(key []byte) (KeyId, error) {
+	return NewKeyId(key), nil
+}
+
+func TestCache(t *testing.T) {
+	key0 := bytes.Repeat([]byte{1}, KEY_SIZE)
+	key1 := bytes.Repeat([]byte{2}, KEY_SIZE)
+	store := &flakyKey{keys: [][]byte{key0}}
+	now := time.Unix(1111111109, 0)
+	c := NewCache(store, time.Minute, idOf)
+	c.Clock = func() time.Time { return now }
+
+	for range 10 {
+		ids, err := c.Get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0] != NewKeyId(key0) {
+			t.Fatal("unexpected values")
+		}
+	}
+	if store.count != 1 {
+		t.Fatalf("keys should be cached but fetched %d times\n", store.count)
+	}
+
+	t.Run("should refresh once for concurrent callers after ttl", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		store.count = 0
+		store.gate = make(chan struct{})
+
+		wg := sync.WaitGroup{}
+		for range 10 {
+			wg.Go(func() {
+				if _, err := c.Get(); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+		// callers other than the refreshing one return with stale values
+		time.Sleep(10 * time.Millisecond)
+		close(store.gate)
+		wg.Wait()
+
+		store.gate = nil
+		if store.count != 1 {
+			t.Fatalf("keys should be fetched once but %d times\n", store.count)
+		}
+	})
+
+	t.Run("should fall back to stale values while keystore is down", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		store.count = 0
+		store.down = true
+
+		for range 10 {
+			if _, err := c.Get(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if store.count != 1 {
+			t.Fatalf("failed refresh should not be retried at once but %d times\n", store.count)
+		}
+
+		now = now.Add(REFRESH_RETRY_INTERVAL)
+		if _, err := c.Get(); err != nil {
+			t.Fatal(err)
+		}
+		if store.count != 2 {
+			t.Fatal("should retry after the interval")
+		}
+		store.down = false
+	})
+
+	t.Run("should reload missing keys unless loaded recently", func(t *testing.T) {
+		if err := c.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		store.keys = [][]byte{key1, key0}
+		missing := func(ids []KeyId) bool {
+			return ids[0] != NewKeyId(key1)
+		}
+
+		ids, err := c.Reload(missing, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !missing(ids) {
+			t.Fatal("should not reload so often")
+		}
+
+		now = now.Add(time.Second)
+		ids, err = c.Reload(missing, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if missing(ids) || len(ids) != 2 {
+			t.Fatal("should pick up the new key")
+		}
+	})
+
+	t.Run("should fail without cached values", func(t *testing.T) {
+		c.Purge()
+		store.down = true
+		if _, err := c.Get(); err == nil {
+			t.Fatal("should fail while keystore is down")
+		}
+
+		store.down = false
+		if _, err := c.Get(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("should zero keys from keystore", func(t *testing.T) {
+		key := bytes.Repeat([]byte{1}, KEY_SIZE)
+		c := NewCache(multiKey{key}, 0, idOf)
+		if err := c.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(key, make([]byte, KEY_SIZE)) {
+			t.Fatal("key should be zeroed")
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package keystore
+
+import (
+	"errors"
+	"log"
+	"sync"
+)
+
+// tries backends in order on Init, and the first one
+// initialized successfully supplies keys afterwards
+type Composite struct {
+	names    []string
+	backends []Keystore
+	Logger   *log.Logger
+
+	mu       sync.RWMutex
+	selected Keystore
+	source   string
+}
+
+func NewComposite(names ...string) (*Composite, error) {
+	if len(names) == 0 {
+		return nil, errors.New("composite keystore needs at least one backend")
+	}
+
+	backends := make([]Keystore, len(names))
+	for i, name := range names {
+		backend, err := New(name)
+		if err != nil {
+			return nil, err
+		}
+		backends[i] = backend
+	}
+
+	return &Composite{
+		names:    names,
+		backends: backends,
+		Logger:   log.Default(),
+	}, nil
+}
+
+func (c *Composite) Init() error {
+	logger := c.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	errs := []error{}
+	for i, backend := range c.backends {
+		if err := backend.Init(); err != nil {
+			logger.Printf("keystore %s is not available: %v\n", c.names[i], err)
+			errs = append(errs, err)
+			continue
+		}
+
+		c.mu.Lock()
+		c.selected = backend
+		c.source = c.names[i]
+		c.mu.Unlock()
+
+		logger.Printf("using keystore %s\n", c.names[i])
+		return nil
+	}
+
+	return errors.Join(append([]error{errors.New("no keystore is available")}, errs...)...)
+}
+
+// name of the backend supplying keys, empty before Init
+func (c *Composite) Source() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.source
+}
+
+func (c *Composite) backend() (Keystore, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.selected == nil {
+		return nil, errors.New("composite keystore is not initialized")
+	}
+	return c.selected, nil
+}
+
+func (c *Composite) GetKey() ([]byte, error) {
+	backend, err := c.backend()
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.GetKey()
+}
+
+func (c *Composite) GetKeys() ([][]byte, error) {
+	backend, err := c.backend()
+	if err != nil {
+		return nil, err
+	}
+
+	return GetKeys(backend)
+}
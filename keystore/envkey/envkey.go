@@ -5,12 +5,20 @@ import (
 	"errors"
 	"nidan-kai/keystore"
 	"os"
+	"strings"
 	"unicode/utf8"
 )
 
-// read "ENV_SECRET_KEY"
+// read "ENV_SECRET_KEY" as the primary key,
+// and comma separated "ENV_SECRET_KEY_PREVIOUS" as previous keys
 type EnvKey struct{}
 
+func init() {
+	keystore.Register("env", func() keystore.Keystore {
+		return EnvKey{}
+	})
+}
+
 func getEnv() (string, error) {
 	// don't inject other than env
 	// to prevent exposing sensitive info
@@ -24,6 +32,34 @@ func getEnv() (string, error) {
 	return key, nil
 }
 
+// optional, empty when no key has been rotated
+func getPreviousEnv() []string {
+	prev := os.Getenv("ENV_SECRET_KEY_PREVIOUS")
+	if len(prev) == 0 {
+		return nil
+	}
+
+	return strings.Split(prev, ",")
+}
+
+func decodeKey(s string) ([]byte, error) {
+	// this is actually bypassed when it's utf-16 etc
+	if !utf8.ValidString(s) {
+		return nil, errors.New("this is not a utf-8 string")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) != keystore.KEY_SIZE {
+		return nil, errors.New("unexpected key size")
+	}
+
+	return b, nil
+}
+
 func (e EnvKey) Init() error {
 	_, err := getEnv()
 	if err != nil {
@@ -39,19 +75,23 @@ func (e EnvKey) GetKey() ([]byte, error) {
 		return nil, err
 	}
 
-	// this is actually bypassed when it's utf-16 etc
-	if !utf8.ValidString(s) {
-		return nil, errors.New("this is not a utf-8 string")
-	}
+	return decodeKey(s)
+}
 
-	b, err := base64.StdEncoding.DecodeString(s)
+func (e EnvKey) GetKeys() ([][]byte, error) {
+	primary, err := e.GetKey()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(b) != keystore.KEY_SIZE {
-		return nil, errors.New("unexpected key size")
+	keys := [][]byte{primary}
+	for _, s := range getPreviousEnv() {
+		b, err := decodeKey(s)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, b)
 	}
 
-	return b, nil
+	return keys, nil
 }
@@ -7,10 +7,13 @@ import (
 )
 
 var _ keystore.Keystore = EnvKey{}
+var _ keystore.MultiKeystore = EnvKey{}
 
 var testKEY = "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT="
 var testBytes = []byte{0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34}
 var envKey = "ENV_SECRET_KEY"
+var prevEnvKey = "ENV_SECRET_KEY_PREVIOUS"
+var testPrevKEY = "UUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUU="
 
 func Test_getEnv(t *testing.T) {
 	s, err := getEnv()
@@ -44,3 +47,39 @@ func Test_GetKey(t *testing.T) {
 		t.Fatal("wrong bytes")
 	}
 }
+
+func Test_GetKeys(t *testing.T) {
+	e := EnvKey{}
+	t.Setenv(envKey, testKEY)
+
+	keys, err := e.GetKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || !bytes.Equal(keys[0], testBytes) {
+		t.Fatal("wrong keys without previous")
+	}
+
+	t.Setenv(prevEnvKey, testPrevKEY+", "+testKEY)
+
+	keys, err = e.GetKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 {
+		t.Fatal("wrong number of keys")
+	}
+	if !bytes.Equal(keys[0], testBytes) {
+		t.Fatal("primary should be the first")
+	}
+	if bytes.Equal(keys[1], testBytes) || !bytes.Equal(keys[2], testBytes) {
+		t.Fatal("wrong previous keys")
+	}
+
+	t.Setenv(prevEnvKey, "broken")
+
+	keys, err = e.GetKeys()
+	if err == nil {
+		t.Fatalf("should fail but returned %v\n", keys)
+	}
+}
@@ -0,0 +1,170 @@
+package filekey
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"nidan-kai/keystore"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const DEFAULT_KEY_NAME = "nidan-kai-secret-key"
+
+// docker and kubernetes mount secrets here
+const RUN_SECRETS_DIR = "/run/secrets"
+
+// larger files can't be a key in any format
+const MAX_FILE_SIZE = 1024
+
+// read the key from "FILE_SECRET_KEY_PATH", or file named
+// "FILE_SECRET_KEY_NAME" in "$CREDENTIALS_DIRECTORY" of systemd
+// or in /run/secrets, the file has to be owned by the process user
+// and not be readable by group and others (like 0600),
+// or owned by root and not be writable by group and others
+// (like 0444 or 0644 of secret mounts) unless "FILE_SECRET_KEY_STRICT"
+// is "true", which accepts only the files of the process user
+type FileKey struct{}
+
+func init() {
+	keystore.Register("file", func() keystore.Keystore {
+		return FileKey{}
+	})
+}
+
+func getEnv() (string, string, string, bool) {
+	// don't inject other than env
+	// to prevent exposing sensitive info
+	// just write within module for testing
+
+	path := os.Getenv("FILE_SECRET_KEY_PATH")
+	name := os.Getenv("FILE_SECRET_KEY_NAME")
+	if len(name) == 0 {
+		name = DEFAULT_KEY_NAME
+	}
+	credDir := os.Getenv("CREDENTIALS_DIRECTORY")
+	strict := os.Getenv("FILE_SECRET_KEY_STRICT") == "true"
+
+	return path, name, credDir, strict
+}
+
+func findIn(dirs []string, name string) (string, error) {
+	if strings.ContainsRune(name, filepath.Separator) {
+		return "", errors.New("key name should not contain path separator")
+	}
+
+	for _, dir := range dirs {
+		if len(dir) == 0 {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", errors.New("could not find key file")
+}
+
+func resolvePath() (string, error) {
+	path, name, credDir, _ := getEnv()
+	if len(path) > 0 {
+		return path, nil
+	}
+
+	return findIn([]string{credDir, RUN_SECRETS_DIR}, name)
+}
+
+func readKey(path string, strict bool) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		return nil, errors.New("key file is not a regular file")
+	}
+	if err := checkPermission(info, strict); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if info.Size() > MAX_FILE_SIZE {
+		return nil, errors.New("key file is too large")
+	}
+
+	b, err := io.ReadAll(io.LimitReader(f, MAX_FILE_SIZE))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeKey(b)
+}
+
+// raw 32 bytes, or base64 of them with optional trailing new line
+func decodeKey(b []byte) ([]byte, error) {
+	if len(b) == keystore.KEY_SIZE {
+		return b, nil
+	}
+
+	s := strings.TrimSpace(string(b))
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != keystore.KEY_SIZE {
+		return nil, errors.New("unexpected key size")
+	}
+
+	return key, nil
+}
+
+// for provisioning, writes base64 of the key with a new line
+// or raw bytes, existing files are not overwritten
+func WriteKey(path string, key []byte, raw bool) error {
+	if len(key) != keystore.KEY_SIZE {
+		return errors.New("unexpected key size")
+	}
+
+	content := key
+	if !raw {
+		content = []byte(base64.StdEncoding.EncodeToString(key) + "\n")
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return f.Close()
+}
+
+func (f FileKey) Init() error {
+	_, err := f.GetKey()
+	return err
+}
+
+func (f FileKey) GetKey() ([]byte, error) {
+	path, err := resolvePath()
+	if err != nil {
+		return nil, err
+	}
+	_, _, _, strict := getEnv()
+
+	return readKey(path, strict)
+}
@@ -0,0 +1,215 @@
+package filekey
+
+import (
+	"bytes"
+	"fmt"
+	"nidan-kai/keystore"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var _ keystore.Keystore = FileKey{}
+
+var testKEY = "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT="
+var testBytes = []byte{0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34, 0xd3, 0x4d, 0x34}
+
+var (
+	pathKey    = "FILE_SECRET_KEY_PATH"
+	nameKey    = "FILE_SECRET_KEY_NAME"
+	credDirKey = "CREDENTIALS_DIRECTORY"
+)
+
+func writeKey(t *testing.T, dir, name string, content []byte, perm os.FileMode) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, content, perm); err != nil {
+		t.Fatal(err)
+	}
+	// umask may have changed it
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileKey_GetKey(t *testing.T) {
+	dir := t.TempDir()
+
+	testCases := []struct {
+		name    string
+		content []byte
+	}{
+		{"base64", []byte(testKEY)},
+		{"base64 with new line", []byte(testKEY + "\n")},
+		{"raw", testBytes},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeKey(t, dir, "key", tc.content, 0o600)
+			t.Setenv(pathKey, path)
+
+			f := FileKey{}
+			if err := f.Init(); err != nil {
+				t.Fatal(err)
+			}
+			b, err := f.GetKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b, testBytes) {
+				t.Fatal("wrong bytes")
+			}
+		})
+	}
+}
+
+func TestFileKey_Fail(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("no file", func(t *testing.T) {
+		t.Setenv(pathKey, filepath.Join(dir, "missing"))
+		if err := (FileKey{}).Init(); err == nil {
+			t.Fatal("should fail without file")
+		}
+	})
+
+	t.Run("wrong size", func(t *testing.T) {
+		t.Setenv(pathKey, writeKey(t, dir, "short", []byte("dGVzdA=="), 0o600))
+		if err := (FileKey{}).Init(); err == nil {
+			t.Fatal("should fail with wrong size")
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		content := bytes.Repeat([]byte("A"), MAX_FILE_SIZE+1)
+		t.Setenv(pathKey, writeKey(t, dir, "large", content, 0o600))
+		if err := (FileKey{}).Init(); err == nil {
+			t.Fatal("should fail with large file")
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		t.Setenv(pathKey, dir)
+		if err := (FileKey{}).Init(); err == nil {
+			t.Fatal("should fail with directory")
+		}
+	})
+
+	for _, perm := range []os.FileMode{0o640, 0o604, 0o644, 0o660} {
+		t.Run("permission "+perm.String(), func(t *testing.T) {
+			t.Setenv(pathKey, writeKey(t, dir, "loose", []byte(testKEY), perm))
+			if err := (FileKey{}).Init(); err == nil {
+				t.Fatal("should fail with loose permission")
+			}
+		})
+	}
+
+	t.Run("not owned", func(t *testing.T) {
+		if os.Geteuid() != 0 {
+			t.Skip("changing owner requires root")
+		}
+
+		path := writeKey(t, dir, "others", []byte(testKEY), 0o600)
+		if err := os.Chown(path, 65534, 65534); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(pathKey, path)
+		if err := (FileKey{}).Init(); err == nil {
+			t.Fatal("should fail with file of others")
+		}
+	})
+}
+
+func Test_resolvePath(t *testing.T) {
+	dir := t.TempDir()
+	path := writeKey(t, dir, DEFAULT_KEY_NAME, []byte(testKEY), 0o600)
+
+	t.Run("credentials directory", func(t *testing.T) {
+		t.Setenv(credDirKey, dir)
+
+		p, err := resolvePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != path {
+			t.Fatalf("wrong path %s\n", p)
+		}
+
+		b, err := (FileKey{}).GetKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, testBytes) {
+			t.Fatal("wrong bytes")
+		}
+	})
+
+	t.Run("custom name", func(t *testing.T) {
+		custom := writeKey(t, dir, "custom", []byte(testKEY), 0o600)
+		t.Setenv(credDirKey, dir)
+		t.Setenv(nameKey, "custom")
+
+		p, err := resolvePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != custom {
+			t.Fatalf("wrong path %s\n", p)
+		}
+	})
+
+	t.Run("path takes precedence", func(t *testing.T) {
+		t.Setenv(credDirKey, dir)
+		t.Setenv(pathKey, "/somewhere/else")
+
+		p, err := resolvePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != "/somewhere/else" {
+			t.Fatalf("wrong path %s\n", p)
+		}
+	})
+
+	t.Run("secrets directory", func(t *testing.T) {
+		p, err := findIn([]string{"", filepath.Join(dir, "missing"), dir}, DEFAULT_KEY_NAME)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != path {
+			t.Fatalf("wrong path %s\n", p)
+		}
+	})
+
+	t.Run("should reject separator in name", func(t *testing.T) {
+		p, err := findIn([]string{dir}, "../"+DEFAULT_KEY_NAME)
+		if err == nil {
+			t.Fatalf("should fail but returned %s\n", p)
+		}
+	})
+}
+
+func Test_WriteKey(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, raw := range []bool{true, false} {
+		path := filepath.Join(dir, fmt.Sprintf("key-%v", raw))
+		if err := WriteKey(path, testBytes, raw); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(pathKey, path)
+
+		b, err := FileKey{}.GetKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, testBytes) {
+			t.Fatal("wrong bytes")
+		}
+
+		if err := WriteKey(path, testBytes, raw); err == nil {
+			t.Fatal("should not overwrite existing file")
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package filekey
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// permissions can't be checked the same way,
+// refuse rather than reading a key possibly exposed
+func checkPermission(info fs.FileInfo, strict bool) error {
+	return errors.New("file key is only supported on unix")
+}
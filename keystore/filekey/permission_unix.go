@@ -0,0 +1,40 @@
+//go:build unix
+
+package filekey
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+func checkPermission(info fs.FileInfo, strict bool) error {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return errors.New("could not get owner of key file")
+	}
+
+	return checkMode(info.Mode().Perm(), int(stat.Uid), os.Geteuid(), strict)
+}
+
+// files of the process user have to be private to it, and read-only
+// files of root are also accepted unless strict, as docker and kubernetes
+// mount secrets so for services running as other users
+func checkMode(perm fs.FileMode, uid, euid int, strict bool) error {
+	if uid == euid {
+		if perm&0o077 != 0 {
+			return errors.New("key file should not be accessible by group or others")
+		}
+		return nil
+	}
+
+	if uid != 0 || strict {
+		return errors.New("key file is not owned by the process user")
+	}
+	if perm&0o022 != 0 {
+		return errors.New("key file of root should not be writable by group or others")
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package filekey
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func Test_checkMode(t *testing.T) {
+	user := 1000
+
+	testCases := []struct {
+		name   string
+		perm   fs.FileMode
+		uid    int
+		strict bool
+		ok     bool
+	}{
+		{"private file of the user", 0o600, user, false, true},
+		{"read-only file of the user", 0o400, user, true, true},
+		{"file of the user readable by others", 0o644, user, false, false},
+		{"secret mount of root", 0o444, 0, false, true},
+		{"secret mount of root readable by group", 0o644, 0, false, true},
+		{"secret mount of root in strict", 0o444, 0, true, false},
+		{"file of root writable by group", 0o664, 0, false, false},
+		{"file of root writable by others", 0o646, 0, false, false},
+		{"file of others", 0o400, 65534, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkMode(tc.perm, tc.uid, user, tc.strict)
+			if tc.ok && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatal("should be rejected")
+			}
+		})
+	}
+
+	t.Run("should be strict for root itself", func(t *testing.T) {
+		if err := checkMode(0o644, 0, 0, false); err == nil {
+			t.Fatal("file of the user should be private")
+		}
+	})
+}
@@ -1,8 +1,56 @@
 package keystore
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
 const KEY_SIZE = 32
+const KEY_ID_SIZE = 4
+
+const KEY_ID_LABEL = "nidan-kai/key-id"
 
 type Keystore interface {
 	Init() error
+	// returns the primary key
 	GetKey() ([]byte, error)
 }
+
+// keystore holding previous keys as well as the primary,
+// so that secrets encrypted before rotation can be decrypted
+type MultiKeystore interface {
+	Keystore
+	// returns all keys, the primary first
+	GetKeys() ([][]byte, error)
+}
+
+// non-secret identifier of a key, stored with ciphertext
+type KeyId [KEY_ID_SIZE]byte
+
+func NewKeyId(key []byte) KeyId {
+	h := sha256.New()
+	h.Write([]byte(KEY_ID_LABEL))
+	h.Write(key)
+
+	id := KeyId{}
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+func (id KeyId) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// returns all keys of the keystore, the primary first
+func GetKeys(keystore Keystore) ([][]byte, error) {
+	if multi, ok := keystore.(MultiKeystore); ok {
+		return multi.GetKeys()
+	}
+
+	key, err := keystore.GetKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return [][]byte{key}, nil
+}
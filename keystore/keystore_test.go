@@ -0,0 +1,75 @@
+package keystore
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type singleKey []byte
+
+func (s singleKey) Init() error {
+	return nil
+}
+
+func (s singleKey) GetKey() ([]byte, error) {
+	if len(s) == 0 {
+		return nil, errors.New("no key")
+	}
+	return s, nil
+}
+
+type multiKey [][]byte
+
+func (m multiKey) Init() error {
+	return nil
+}
+
+func (m multiKey) GetKey() ([]byte, error) {
+	return m[0], nil
+}
+
+func (m multiKey) GetKeys() ([][]byte, error) {
+	return m, nil
+}
+
+func Test_NewKeyId(t *testing.T) {
+	key0 := bytes.Repeat([]byte{1}, KEY_SIZE)
+	key1 := bytes.Repeat([]byte{2}, KEY_SIZE)
+
+	if NewKeyId(key0) != NewKeyId(key0) {
+		t.Fatal("key id is not stable")
+	}
+	if NewKeyId(key0) == NewKeyId(key1) {
+		t.Fatal("different keys have the same id")
+	}
+	if len(NewKeyId(key0).String()) != KEY_ID_SIZE*2 {
+		t.Fatal("unexpected key id string")
+	}
+}
+
+func Test_GetKeys(t *testing.T) {
+	key0 := bytes.Repeat([]byte{1}, KEY_SIZE)
+	key1 := bytes.Repeat([]byte{2}, KEY_SIZE)
+
+	keys, err := GetKeys(singleKey(key0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || !bytes.Equal(keys[0], key0) {
+		t.Fatal("wrong keys from single keystore")
+	}
+
+	keys, err = GetKeys(multiKey{key0, key1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || !bytes.Equal(keys[0], key0) || !bytes.Equal(keys[1], key1) {
+		t.Fatal("wrong keys from multi keystore")
+	}
+
+	keys, err = GetKeys(singleKey(nil))
+	if err == nil {
+		t.Fatalf("should fail but returned %v\n", keys)
+	}
+}
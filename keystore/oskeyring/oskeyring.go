@@ -4,16 +4,39 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"nidan-kai/keystore"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/zalando/go-keyring"
 )
 
-// read from "SERVICE_NAME":"OS_KEYRING_USER"
+// the keyring itself can't be used, e.g. no secret service on d-bus
+var ErrUnavailable = errors.New("keyring is unavailable")
+
+// the keyring works but the key is not there, e.g. wiped or not provisioned
+var ErrMissing = errors.New("keyring entry is missing")
+
+// version of the entry "OS_KEYRING_USER" itself,
+// which was the only key before rotation
+const LEGACY_VERSION = 0
+
+// read from "SERVICE_NAME":"OS_KEYRING_USER", key versions are listed
+// in "OS_KEYRING_USER.index" primary first and stored in
+// "OS_KEYRING_USER.v<version>", key is created on Init only when
+// "OS_KEYRING_PROVISION" is "true", otherwise use Provision explicitly
 type OsKeyring struct{}
 
+func init() {
+	keystore.Register("keyring", func() keystore.Keystore {
+		return OsKeyring{}
+	})
+}
+
 func getEnv() (string, string, error) {
 	// don't inject other than env
 	// to prevent exposing sensitive info
@@ -32,27 +55,261 @@ func getEnv() (string, string, error) {
 	return svc, usr, nil
 }
 
+func provisionEnabled() bool {
+	return os.Getenv("OS_KEYRING_PROVISION") == "true"
+}
+
+func indexEntry(usr string) string {
+	return usr + ".index"
+}
+
+func versionEntry(usr string, version int) string {
+	if version == LEGACY_VERSION {
+		return usr
+	}
+	return usr + ".v" + strconv.Itoa(version)
+}
+
+// tells missing entries from the keyring not working
+func get(svc, entry string) (string, error) {
+	s, err := keyring.Get(svc, entry)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf("%w: %s", ErrMissing, entry)
+	} else if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return s, nil
+}
+
+func set(svc, entry, value string) error {
+	if err := keyring.Set(svc, entry, value); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return nil
+}
+
+func remove(svc, entry string) error {
+	err := keyring.Delete(svc, entry)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return nil
+}
+
+// versions primary first, the legacy entry alone before any rotation
+func readIndex(svc, usr string) ([]int, error) {
+	s, err := get(svc, indexEntry(usr))
+	if errors.Is(err, ErrMissing) {
+		if _, err := get(svc, versionEntry(usr, LEGACY_VERSION)); err != nil {
+			return nil, err
+		}
+		return []int{LEGACY_VERSION}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	versions := []int{}
+	for _, raw := range strings.Split(s, ",") {
+		version, err := strconv.Atoi(raw)
+		if err != nil || version < 0 || slices.Contains(versions, version) {
+			return nil, errors.New("keyring index is broken")
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+func writeIndex(svc, usr string, versions []int) error {
+	raw := make([]string, len(versions))
+	for i, version := range versions {
+		raw[i] = strconv.Itoa(version)
+	}
+
+	return set(svc, indexEntry(usr), strings.Join(raw, ","))
+}
+
+func readKey(svc, entry string) ([]byte, error) {
+	s, err := get(svc, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	// this is actually bypassed when it's utf-16 etc
+	if !utf8.ValidString(s) {
+		return nil, errors.New("this is not a utf-8 string")
+	}
+
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) != keystore.KEY_SIZE {
+		return nil, errors.New("unexpected key size")
+	}
+
+	return b, nil
+}
+
+func newKey() ([]byte, error) {
+	b := make([]byte, keystore.KEY_SIZE)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// fails with ErrMissing when the key is not provisioned,
+// creates one instead only in provisioning mode
 func (o OsKeyring) Init() error {
 	svc, usr, err := getEnv()
 	if err != nil {
 		return err
 	}
 
-	_, err = keyring.Get(svc, usr)
-	if errors.Is(err, keyring.ErrNotFound) {
-		b := make([]byte, 32)
-		if _, err := rand.Read(b); err != nil {
+	versions, err := readIndex(svc, usr)
+	if errors.Is(err, ErrMissing) && provisionEnabled() {
+		return Provision()
+	} else if err != nil {
+		return err
+	}
+
+	// every listed key has to be readable
+	for _, version := range versions {
+		key, err := readKey(svc, versionEntry(usr, version))
+		if err != nil {
 			return err
 		}
+		clear(key)
+	}
 
-		p := base64.StdEncoding.EncodeToString(b)
-		if err := keyring.Set(svc, usr, p); err != nil {
-			return err
+	return nil
+}
+
+// creates the first key, fails if any key exists
+func Provision() error {
+	key, err := newKey()
+	if err != nil {
+		return err
+	}
+	defer clear(key)
+
+	return SetKey(key)
+}
+
+// for provisioning with the given key, existing entry is not overwritten
+func SetKey(key []byte) error {
+	svc, usr, err := getEnv()
+	if err != nil {
+		return err
+	}
+	if len(key) != keystore.KEY_SIZE {
+		return errors.New("unexpected key size")
+	}
+
+	_, err = readIndex(svc, usr)
+	if err == nil {
+		return errors.New("keyring entry already exists")
+	} else if !errors.Is(err, ErrMissing) {
+		return err
+	}
+
+	return set(svc, versionEntry(usr, LEGACY_VERSION), base64.StdEncoding.EncodeToString(key))
+}
+
+// creates a new primary key keeping the previous ones,
+// returns the new version
+func Rotate() (int, error) {
+	svc, usr, err := getEnv()
+	if err != nil {
+		return 0, err
+	}
+
+	versions, err := readIndex(svc, usr)
+	if err != nil {
+		return 0, err
+	}
+
+	next := slices.Max(versions) + 1
+	key, err := newKey()
+	if err != nil {
+		return 0, err
+	}
+	defer clear(key)
+
+	// the key is written before the index,
+	// so that failure in between leaves only an unused entry
+	err = set(svc, versionEntry(usr, next), base64.StdEncoding.EncodeToString(key))
+	if err != nil {
+		return 0, err
+	}
+	if err := writeIndex(svc, usr, append([]int{next}, versions...)); err != nil {
+		return 0, err
+	}
+
+	return next, nil
+}
+
+// removes previous keys except the latest keep of them,
+// secrets have to be re-encrypted beforehand
+func Cleanup(keep int) ([]int, error) {
+	if keep < 0 {
+		return nil, errors.New("keep should not be negative")
+	}
+
+	svc, usr, err := getEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := readIndex(svc, usr)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) <= 1+keep {
+		return nil, nil
+	}
+
+	kept, removed := versions[:1+keep], versions[1+keep:]
+	// the index is written before removing,
+	// so that it never lists removed keys
+	if err := writeIndex(svc, usr, kept); err != nil {
+		return nil, err
+	}
+	for _, version := range removed {
+		if err := remove(svc, versionEntry(usr, version)); err != nil {
+			return nil, err
 		}
+	}
+
+	return removed, nil
+}
+
+// removes all keys and the index, secrets encrypted with them are lost
+func Delete() error {
+	svc, usr, err := getEnv()
+	if err != nil {
+		return err
+	}
+
+	versions, err := readIndex(svc, usr)
+	if errors.Is(err, ErrMissing) {
+		return nil
 	} else if err != nil {
 		return err
 	}
 
+	if err := remove(svc, indexEntry(usr)); err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if err := remove(svc, versionEntry(usr, version)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -62,24 +319,33 @@ func (o OsKeyring) GetKey() ([]byte, error) {
 		return nil, err
 	}
 
-	s, err := keyring.Get(svc, usr)
+	versions, err := readIndex(svc, usr)
 	if err != nil {
 		return nil, err
 	}
 
-	// this is actually bypassed when it's utf-16 etc
-	if !utf8.ValidString(s) {
-		return nil, errors.New("this is not a utf-8 string")
+	return readKey(svc, versionEntry(usr, versions[0]))
+}
+
+func (o OsKeyring) GetKeys() ([][]byte, error) {
+	svc, usr, err := getEnv()
+	if err != nil {
+		return nil, err
 	}
 
-	b, err := base64.StdEncoding.DecodeString(s)
+	versions, err := readIndex(svc, usr)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(b) != keystore.KEY_SIZE {
-		return nil, errors.New("unexpected key size")
+	keys := make([][]byte, 0, len(versions))
+	for _, version := range versions {
+		key, err := readKey(svc, versionEntry(usr, version))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
 	}
 
-	return b, nil
+	return keys, nil
 }
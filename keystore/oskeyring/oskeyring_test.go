@@ -2,6 +2,7 @@ package oskeyring
 
 import (
 	"bytes"
+	"errors"
 	"nidan-kai/keystore"
 	"os"
 	"testing"
@@ -10,13 +11,14 @@ import (
 )
 
 // This ensures OsKeyring implements the keystore.Keystore interface.
-var _ keystore.Keystore = OsKeyring{}
+var _ keystore.MultiKeystore = OsKeyring{}
 
 var (
-	serviceKey  = "SERVICE_NAME"
-	userKey     = "OS_KEYRING_USER"
-	testService = "nidan-kai-test-service"
-	testUser    = "nidan-kai-test-user"
+	serviceKey   = "SERVICE_NAME"
+	userKey      = "OS_KEYRING_USER"
+	provisionKey = "OS_KEYRING_PROVISION"
+	testService  = "nidan-kai-test-service"
+	testUser     = "nidan-kai-test-user"
 )
 
 func setupEnv(t *testing.T) {
@@ -75,7 +77,12 @@ func TestOsKeyring_Init_to_Get(t *testing.T) {
 
 	kr := OsKeyring{}
 	err := kr.Init()
-	if err != nil {
+	if !errors.Is(err, ErrMissing) {
+		t.Fatalf("should not create key without provisioning but returned %v\n", err)
+	}
+
+	t.Setenv(provisionKey, "true")
+	if err := kr.Init(); err != nil {
 		t.Fatal(err)
 	}
 
@@ -88,6 +95,10 @@ func TestOsKeyring_Init_to_Get(t *testing.T) {
 		t.Fatal("returned empty secret")
 	}
 
+	// existing key is kept in provisioning mode
+	if err := kr.Init(); err != nil {
+		t.Fatal(err)
+	}
 	secret1, err := kr.GetKey()
 	if err != nil {
 		t.Fatal(err)
@@ -117,6 +128,9 @@ func Test_Fail(t *testing.T) {
 			t.Fatal(err)
 		}
 
+		if err := Provision(); err != nil {
+			t.Fatal(err)
+		}
 		kr := OsKeyring{}
 		if err := kr.Init(); err != nil {
 			t.Fatal(err)
@@ -134,4 +148,125 @@ func Test_Fail(t *testing.T) {
 			t.Fatalf("should fail without env but returned %v\n", b)
 		}
 	})
+
+	t.Run("should tell unavailable from missing", func(t *testing.T) {
+		setupEnv(t)
+		keyring.MockInitWithError(errors.New("no secret service"))
+		t.Setenv(provisionKey, "true")
+
+		err := OsKeyring{}.Init()
+		if !errors.Is(err, ErrUnavailable) || errors.Is(err, ErrMissing) {
+			t.Fatalf("should be unavailable but returned %v\n", err)
+		}
+	})
+
+	t.Run("should refuse second provisioning", func(t *testing.T) {
+		setupEnv(t)
+		keyring.MockInit()
+		if err := Provision(); err != nil {
+			t.Fatal(err)
+		}
+		if err := Provision(); err == nil {
+			t.Fatal("should not overwrite existing key")
+		}
+	})
+}
+
+func Test_Rotate(t *testing.T) {
+	setupEnv(t)
+	keyring.MockInit()
+
+	if _, err := Rotate(); !errors.Is(err, ErrMissing) {
+		t.Fatal("should not rotate before provisioning")
+	}
+
+	// provisioned before versioning
+	legacy := bytes.Repeat([]byte{1}, keystore.KEY_SIZE)
+	if err := SetKey(legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	kr := OsKeyring{}
+	for i := 1; i <= 3; i++ {
+		version, err := Rotate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != i {
+			t.Fatalf("expected version %d but %d\n", i, version)
+		}
+	}
+	if err := kr.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := kr.GetKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 4 || !bytes.Equal(keys[3], legacy) {
+		t.Fatal("should keep previous keys")
+	}
+	primary, err := kr.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(primary, keys[0]) || bytes.Equal(primary, legacy) {
+		t.Fatal("rotated key should be the primary")
+	}
+
+	t.Run("should clean up previous keys", func(t *testing.T) {
+		removed, err := Cleanup(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(removed) != 2 || removed[0] != 1 || removed[1] != LEGACY_VERSION {
+			t.Fatalf("unexpected removed versions %v\n", removed)
+		}
+
+		rest, err := kr.GetKeys()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rest) != 2 || !bytes.Equal(rest[0], keys[0]) || !bytes.Equal(rest[1], keys[1]) {
+			t.Fatal("should keep the primary and the latest previous key")
+		}
+		if _, err := keyring.Get(testService, testUser); !errors.Is(err, keyring.ErrNotFound) {
+			t.Fatal("legacy entry should be removed")
+		}
+	})
+
+	t.Run("should delete all keys", func(t *testing.T) {
+		if err := Delete(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := kr.GetKey(); !errors.Is(err, ErrMissing) {
+			t.Fatal("should be missing after deletion")
+		}
+		if err := Delete(); err != nil {
+			t.Fatal("deleting twice should not fail")
+		}
+	})
+}
+
+func Test_SetKey(t *testing.T) {
+	setupEnv(t)
+	keyring.MockInit()
+
+	key := bytes.Repeat([]byte{1}, keystore.KEY_SIZE)
+	if err := SetKey(key); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := OsKeyring{}.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, key) {
+		t.Fatal("wrong key")
+	}
+
+	if err := SetKey(key); err == nil {
+		t.Fatal("should not overwrite existing entry")
+	}
 }
@@ -0,0 +1,274 @@
+package passphrase
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"nidan-kai/keystore"
+	"os"
+	"strconv"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/term"
+)
+
+const SALT_LEN = 16
+const CHECK_LEN = 16
+const CHECK_LABEL = "nidan-kai/key-check"
+
+// passphrase longer than this is cut
+const MAX_PASSPHRASE_LEN = 1024
+
+// argon2id, as recommended by RFC 9106 for memory constrained
+const DEFAULT_TIME = 3
+const DEFAULT_MEMORY = 64 * 1024
+const DEFAULT_THREADS = 4
+
+// stored with the server, nothing here is secret
+type Params struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	// to tell wrong passphrase without decrypting secrets
+	Check []byte `json:"check"`
+}
+
+type Cost struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+func DefaultCost() Cost {
+	return Cost{
+		Time:    DEFAULT_TIME,
+		Memory:  DEFAULT_MEMORY,
+		Threads: DEFAULT_THREADS,
+	}
+}
+
+// derives the key from passphrase with "PASSPHRASE_KEY_PARAMS" file,
+// passphrase is read from file descriptor "PASSPHRASE_FD" if set,
+// prompted on the terminal otherwise
+type Passphrase struct {
+	mu  sync.RWMutex
+	key []byte
+}
+
+func init() {
+	keystore.Register("passphrase", func() keystore.Keystore {
+		return &Passphrase{}
+	})
+}
+
+func getEnv() (string, int, bool, error) {
+	// don't inject other than env
+	// to prevent exposing sensitive info
+	// just write within module for testing
+
+	path := os.Getenv("PASSPHRASE_KEY_PARAMS")
+	if len(path) == 0 {
+		return "", 0, false, errors.New("env for passphrase key params is not set")
+	}
+
+	rawFd := os.Getenv("PASSPHRASE_FD")
+	if len(rawFd) == 0 {
+		return path, 0, false, nil
+	}
+
+	fd, err := strconv.Atoi(rawFd)
+	if err != nil || fd < 0 {
+		return "", 0, false, errors.New("invalid passphrase fd")
+	}
+
+	return path, fd, true, nil
+}
+
+func check(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(CHECK_LABEL))
+	return mac.Sum(nil)[:CHECK_LEN]
+}
+
+func derive(passphrase []byte, params Params) []byte {
+	return argon2.IDKey(
+		passphrase,
+		params.Salt,
+		params.Time,
+		params.Memory,
+		params.Threads,
+		keystore.KEY_SIZE,
+	)
+}
+
+// for provisioning, returns params to be stored
+func NewParams(passphrase []byte, cost Cost) (Params, error) {
+	if len(passphrase) == 0 {
+		return Params{}, errors.New("passphrase should not be empty")
+	}
+
+	salt := make([]byte, SALT_LEN)
+	if _, err := rand.Read(salt); err != nil {
+		return Params{}, err
+	}
+
+	params := Params{
+		Salt:    salt,
+		Time:    cost.Time,
+		Memory:  cost.Memory,
+		Threads: cost.Threads,
+	}
+	if err := params.Validate(); err != nil {
+		return Params{}, err
+	}
+
+	key := derive(passphrase, params)
+	params.Check = check(key)
+	clear(key)
+
+	return params, nil
+}
+
+func (p Params) Validate() error {
+	if len(p.Salt) < SALT_LEN {
+		return errors.New("salt is too short")
+	}
+	if p.Time == 0 || p.Memory == 0 || p.Threads == 0 {
+		return errors.New("cost should not be 0")
+	}
+	// argon2 requires at least 8 blocks per lane
+	if p.Memory < 8*uint32(p.Threads) {
+		return errors.New("memory is too small for threads")
+	}
+
+	return nil
+}
+
+func ReadParams(path string) (Params, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Params{}, err
+	}
+
+	params := Params{}
+	if err := json.Unmarshal(b, &params); err != nil {
+		return Params{}, err
+	}
+	if err := params.Validate(); err != nil {
+		return Params{}, err
+	}
+	if len(params.Check) != CHECK_LEN {
+		return Params{}, errors.New("unexpected check value size")
+	}
+
+	return params, nil
+}
+
+func WriteParams(path string, params Params) error {
+	b, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+func readPassphrase(r io.Reader) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, MAX_PASSPHRASE_LEN))
+	if err != nil {
+		return nil, err
+	}
+
+	// only the line ending, spaces may be a part of the passphrase
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	b = bytes.TrimSuffix(b, []byte("\r"))
+	if len(b) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	return b, nil
+}
+
+// reads passphrase on the terminal without echo
+func PromptPassphrase(prompt string) ([]byte, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("could not open terminal for passphrase: %w", err)
+	}
+	defer tty.Close()
+
+	if _, err := fmt.Fprint(tty, prompt); err != nil {
+		return nil, err
+	}
+	b, err := term.ReadPassword(int(tty.Fd()))
+	fmt.Fprintln(tty)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("passphrase is empty")
+	}
+
+	return b, nil
+}
+
+func (p *Passphrase) Init() error {
+	path, fd, hasFd, err := getEnv()
+	if err != nil {
+		return err
+	}
+
+	params, err := ReadParams(path)
+	if err != nil {
+		return err
+	}
+
+	var pass []byte
+	if hasFd {
+		f := os.NewFile(uintptr(fd), "passphrase")
+		if f == nil {
+			return errors.New("invalid passphrase fd")
+		}
+		pass, err = readPassphrase(f)
+		f.Close()
+	} else {
+		pass, err = PromptPassphrase("passphrase: ")
+	}
+	if err != nil {
+		return err
+	}
+
+	key := derive(pass, params)
+	clear(pass)
+
+	if subtle.ConstantTimeCompare(check(key), params.Check) != 1 {
+		clear(key)
+		return errors.New("wrong passphrase")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.key)
+	p.key = key
+
+	return nil
+}
+
+func (p *Passphrase) GetKey() ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.key == nil {
+		return nil, errors.New("passphrase keystore is not initialized")
+	}
+
+	return bytes.Clone(p.key), nil
+}
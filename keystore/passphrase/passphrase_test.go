@@ -0,0 +1,173 @@
+package passphrase
+
+import (
+	"nidan-kai/keystore"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+var _ keystore.Keystore = &Passphrase{}
+
+var (
+	paramsKey      = "PASSPHRASE_KEY_PARAMS"
+	fdKey          = "PASSPHRASE_FD"
+	testPassphrase = "correct horse battery staple"
+)
+
+// cheap enough for tests
+var testCost = Cost{
+	Time:    1,
+	Memory:  64,
+	Threads: 1,
+}
+
+func setupParams(t *testing.T) string {
+	params, err := NewParams([]byte(testPassphrase), testCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "params.json")
+	if err := WriteParams(path, params); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(paramsKey, path)
+	return path
+}
+
+// passes passphrase through a pipe like a parent process would
+func setupFd(t *testing.T, passphrase string) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+
+	if _, err := w.WriteString(passphrase); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(fdKey, strconv.Itoa(int(r.Fd())))
+}
+
+func TestPassphrase_Init_to_Get(t *testing.T) {
+	setupParams(t)
+
+	setupFd(t, testPassphrase+"\n")
+	p := &Passphrase{}
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+	key, err := p.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != keystore.KEY_SIZE {
+		t.Fatal("unexpected key size")
+	}
+
+	// the same passphrase and params derive the same key
+	setupFd(t, testPassphrase)
+	p1 := &Passphrase{}
+	if err := p1.Init(); err != nil {
+		t.Fatal(err)
+	}
+	key1, err := p1.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key) != string(key1) {
+		t.Fatal("derived different keys")
+	}
+
+	// returned key is a copy
+	clear(key)
+	key2, err := p1.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key1) != string(key2) {
+		t.Fatal("internal key is exposed")
+	}
+}
+
+func TestPassphrase_Fail(t *testing.T) {
+	t.Run("should fail without env", func(t *testing.T) {
+		p := &Passphrase{}
+		if err := p.Init(); err == nil {
+			t.Fatal("should fail without env")
+		}
+	})
+
+	t.Run("should fail with wrong passphrase on init", func(t *testing.T) {
+		setupParams(t)
+		setupFd(t, "wrong passphrase")
+
+		p := &Passphrase{}
+		if err := p.Init(); err == nil {
+			t.Fatal("should fail with wrong passphrase")
+		}
+		if key, err := p.GetKey(); err == nil {
+			t.Fatalf("should fail but returned %v\n", key)
+		}
+	})
+
+	t.Run("should fail with empty passphrase", func(t *testing.T) {
+		setupParams(t)
+		setupFd(t, "\n")
+
+		p := &Passphrase{}
+		if err := p.Init(); err == nil {
+			t.Fatal("should fail with empty passphrase")
+		}
+	})
+
+	t.Run("should fail with invalid fd", func(t *testing.T) {
+		setupParams(t)
+		t.Setenv(fdKey, "not a number")
+
+		p := &Passphrase{}
+		if err := p.Init(); err == nil {
+			t.Fatal("should fail with invalid fd")
+		}
+	})
+
+	t.Run("should fail with broken params", func(t *testing.T) {
+		path := setupParams(t)
+		if err := os.WriteFile(path, []byte(`{"salt":"AAAA","time":1}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		setupFd(t, testPassphrase)
+
+		p := &Passphrase{}
+		if err := p.Init(); err == nil {
+			t.Fatal("should fail with broken params")
+		}
+	})
+}
+
+func Test_NewParams(t *testing.T) {
+	params, err := NewParams([]byte(testPassphrase), testCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params1, err := NewParams([]byte(testPassphrase), testCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(params.Salt) == string(params1.Salt) {
+		t.Fatal("salt is not random")
+	}
+
+	if _, err := NewParams(nil, testCost); err == nil {
+		t.Fatal("should fail with empty passphrase")
+	}
+	if _, err := NewParams([]byte(testPassphrase), Cost{}); err == nil {
+		t.Fatal("should fail with zero cost")
+	}
+}
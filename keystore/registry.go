@@ -0,0 +1,80 @@
+package keystore
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// creates a keystore configured by env, Init is called by the user
+type Factory func() Keystore
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// makes a backend available by name, called from init of the backend
+// package, like database/sql drivers, panics on duplicated names
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("keystore: register factory is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("keystore: register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// names of registered backends, sorted
+func Backends() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// creates the backend registered by name, not initialized yet
+func New(name string) (Keystore, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown keystore %q, registered: %s", name, strings.Join(Backends(), ", "))
+	}
+
+	return factory(), nil
+}
+
+// parses comma separated names like "file,keyring,env",
+// a single name gives the backend as it is,
+// more give a composite trying them in order
+func Parse(config string) (Keystore, error) {
+	names := []string{}
+	for _, name := range strings.Split(config, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, errors.New("no keystore is configured")
+	}
+	if len(names) == 1 {
+		return New(names[0])
+	}
+
+	return NewComposite(names...)
+}
@@ -0,0 +1,128 @@
+package keystore
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+type unavailable struct{}
+
+func (u unavailable) Init() error {
+	return errors.New("unavailable")
+}
+
+func (u unavailable) GetKey() ([]byte, error) {
+	return nil, errors.New("unavailable")
+}
+
+var testKey0 = bytes.Repeat([]byte{1}, KEY_SIZE)
+var testKey1 = bytes.Repeat([]byte{2}, KEY_SIZE)
+
+func init() {
+	Register("test-unavailable", func() Keystore { return unavailable{} })
+	Register("test-single", func() Keystore { return singleKey(testKey0) })
+	Register("test-multi", func() Keystore { return multiKey{testKey1, testKey0} })
+}
+
+func Test_Register(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("should panic on duplicated name")
+		}
+	}()
+
+	Register("test-single", func() Keystore { return singleKey(testKey0) })
+}
+
+func Test_New(t *testing.T) {
+	ks, err := New("test-single")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ks.(singleKey); !ok {
+		t.Fatal("unexpected keystore")
+	}
+
+	_, err = New("unknown")
+	if err == nil || !strings.Contains(err.Error(), "test-single") {
+		t.Fatal("should fail with registered names")
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	ks, err := Parse(" test-single ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ks.(singleKey); !ok {
+		t.Fatal("single name should give the backend as it is")
+	}
+
+	ks, err = Parse("test-unavailable,test-single")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ks.(*Composite); !ok {
+		t.Fatal("names should give composite")
+	}
+
+	for _, config := range []string{"", " , ", "test-single,unknown"} {
+		if _, err := Parse(config); err == nil {
+			t.Fatalf("should fail with %q\n", config)
+		}
+	}
+}
+
+func TestComposite(t *testing.T) {
+	c, err := NewComposite("test-unavailable", "test-multi", "test-single")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	c.Logger = log.New(out, "", 0)
+
+	if _, err := c.GetKey(); err == nil {
+		t.Fatal("should fail before init")
+	}
+
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Source() != "test-multi" {
+		t.Fatalf("unexpected source %s\n", c.Source())
+	}
+	if !strings.Contains(out.String(), "using keystore test-multi") {
+		t.Fatal("source should be logged")
+	}
+
+	key, err := c.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, testKey1) {
+		t.Fatal("should get the key of the selected backend")
+	}
+
+	keys, err := GetKeys(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || !bytes.Equal(keys[1], testKey0) {
+		t.Fatal("should get previous keys of the selected backend")
+	}
+
+	t.Run("should fail when none is available", func(t *testing.T) {
+		c, err := NewComposite("test-unavailable", "test-unavailable")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Logger = log.New(io.Discard, "", 0)
+		if err := c.Init(); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"expvar"
+	"net/http"
 	"net/url"
 	"nidan-kai/app"
+	"nidan-kai/ratelimit"
+	"os"
 
 	echo4 "github.com/labstack/echo/v4"
 	echo4middleware "github.com/labstack/echo/v4/middleware"
@@ -30,11 +34,31 @@ func run() {
 	}
 	defer app.Close()
 
-	echo.POST("/api/mfa/qr/setup", app.SetUp)
-	echo.POST("/api/mfa/qr/verify", app.Verify)
+	ipExtractor, err := ratelimit.NewIPExtractor()
+	if err != nil {
+		echo.Logger.Fatal(err)
+	}
+	echo.IPExtractor = ipExtractor
+
+	limits, err := app.NewRateLimitStore()
+	if err != nil {
+		echo.Logger.Fatal(err)
+	}
+	app.Route(echo, limits)
 
 	echo.Group("/*", echo4middleware.Proxy(balancer))
 
+	// metrics expose cmdline and memstats, so they are served
+	// on their own listener only when "METRICS_ADDR" is set,
+	// which should not be reachable from outside
+	if metricsAddr := os.Getenv("METRICS_ADDR"); len(metricsAddr) > 0 {
+		go func() {
+			if err := http.ListenAndServe(metricsAddr, expvar.Handler()); err != nil {
+				echo.Logger.Error(err)
+			}
+		}()
+	}
+
 	if err := echo.Start("localhost:8081"); err != nil {
 		echo.Logger.Fatal(err)
 	}
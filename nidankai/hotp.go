@@ -0,0 +1,104 @@
+package nidankai
+
+import (
+	"crypto/subtle"
+	"errors"
+	"math"
+)
+
+func checkHotp(code int, params Params) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	if params.Type != TypeHotp {
+		return errors.New("params are not for hotp")
+	}
+	if code < 0 || code >= int(params.powered()) {
+		return errors.New("invalid code")
+	}
+
+	return nil
+}
+
+// finds the code within counter..counter+lookAhead,
+// returns the matched counter, next expected counter is matched+1
+func VerifyHotp(
+	code int,
+	secretKey []byte,
+	params Params,
+	counter uint64,
+	lookAhead uint,
+) (uint64, bool, error) {
+	if err := checkHotp(code, params); err != nil {
+		return 0, false, err
+	}
+
+	to := counter + min(uint64(lookAhead), math.MaxUint64-counter)
+
+	// check every counter in the window without returning early
+	// so that the time taken doesn't tell which counter matched
+	matched := uint64(0)
+	ok := 0
+	for c := counter; ; c++ {
+		otp, err := Hotp(secretKey, counterNonce(c), params)
+		if err != nil {
+			return 0, false, err
+		}
+
+		eq := subtle.ConstantTimeEq(otp, int32(code))
+		if eq == 1 && ok == 0 {
+			matched = c
+		}
+		ok |= eq
+
+		if c == to {
+			break
+		}
+	}
+
+	if ok != 1 {
+		return 0, false, nil
+	}
+
+	return matched, true, nil
+}
+
+// for tokens drifted far beyond the look-ahead window,
+// two consecutive codes are required (RFC 4226 7.4)
+// returns the counter of the second code
+func Resync(
+	code, nextCode int,
+	secretKey []byte,
+	params Params,
+	counter uint64,
+	window uint,
+) (uint64, bool, error) {
+	if err := checkHotp(code, params); err != nil {
+		return 0, false, err
+	}
+	if err := checkHotp(nextCode, params); err != nil {
+		return 0, false, err
+	}
+
+	to := counter + min(uint64(window), math.MaxUint64-counter-1)
+
+	prev, err := Hotp(secretKey, counterNonce(counter), params)
+	if err != nil {
+		return 0, false, err
+	}
+	for c := counter + 1; c <= to+1; c++ {
+		otp, err := Hotp(secretKey, counterNonce(c), params)
+		if err != nil {
+			return 0, false, err
+		}
+
+		first := subtle.ConstantTimeEq(prev, int32(code))
+		second := subtle.ConstantTimeEq(otp, int32(nextCode))
+		if first&second == 1 {
+			return c, true, nil
+		}
+		prev = otp
+	}
+
+	return 0, false, nil
+}
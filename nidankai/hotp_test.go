@@ -0,0 +1,99 @@
+package nidankai
+
+import (
+	"testing"
+)
+
+// codes from RFC 4226 Appendix D
+var rfc4226Codes = []int{
+	755224,
+	287082,
+	359152,
+	969429,
+	338314,
+	254676,
+	287922,
+	162583,
+	399871,
+	520489,
+}
+
+func Test_VerifyHotp(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	params := DefaultHotpParams()
+
+	testCases := []struct {
+		name      string
+		code      int
+		counter   uint64
+		lookAhead uint
+		ok        bool
+		matched   uint64
+	}{
+		{"current counter", rfc4226Codes[3], 3, 0, true, 3},
+		{"ahead without look ahead", rfc4226Codes[4], 3, 0, false, 0},
+		{"ahead within look ahead", rfc4226Codes[6], 3, 3, true, 6},
+		{"ahead out of look ahead", rfc4226Codes[7], 3, 3, false, 0},
+		{"behind counter", rfc4226Codes[2], 3, 5, false, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matched, ok, err := VerifyHotp(tc.code, secret, params, tc.counter, tc.lookAhead)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tc.ok {
+				t.Fatalf("expected %v but returned %v", tc.ok, ok)
+			}
+			if ok && matched != tc.matched {
+				t.Fatalf("expected counter %d but returned %d", tc.matched, matched)
+			}
+		})
+	}
+
+	t.Run("should fail with totp params", func(t *testing.T) {
+		_, ok, err := VerifyHotp(rfc4226Codes[0], secret, DefaultParams(), 0, 0)
+		if err == nil || ok {
+			t.Fatal("should fail but returned ok")
+		}
+	})
+}
+
+func Test_Resync(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	params := DefaultHotpParams()
+
+	t.Run("should resync to the second code", func(t *testing.T) {
+		matched, ok, err := Resync(rfc4226Codes[7], rfc4226Codes[8], secret, params, 1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("should success but returned false")
+		}
+		if matched != 8 {
+			t.Fatalf("expected counter 8 but returned %d", matched)
+		}
+	})
+
+	t.Run("should fail with non consecutive codes", func(t *testing.T) {
+		_, ok, err := Resync(rfc4226Codes[6], rfc4226Codes[8], secret, params, 1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("should fail but returned true")
+		}
+	})
+
+	t.Run("should fail out of window", func(t *testing.T) {
+		_, ok, err := Resync(rfc4226Codes[7], rfc4226Codes[8], secret, params, 1, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("should fail but returned true")
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package nidankai
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"nidan-kai/secret"
+	"strconv"
+	"strings"
+)
+
+const KEY_URI_SCHEME = "otpauth"
+
+// everything an authenticator needs, in the form of Key Uri Format
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+type Key struct {
+	Issuer      string
+	AccountName string
+	Secret      []byte
+	Params      Params
+	// only for hotp, the counter the authenticator starts with
+	Counter uint64
+	// optional, url of the icon shown by some authenticators
+	Image string
+}
+
+func (k Key) Validate() error {
+	if err := k.Params.Validate(); err != nil {
+		return err
+	}
+	if len(k.AccountName) == 0 {
+		return errors.New("account name should not be empty")
+	}
+	// colon separates issuer and account name in the label
+	if strings.Contains(k.Issuer, ":") || strings.Contains(k.AccountName, ":") {
+		return errors.New("issuer and account name should not contain colon")
+	}
+	if len(k.Secret) == 0 {
+		return errors.New("secret should not be empty")
+	}
+	if len(k.Image) > 0 {
+		if err := validateImage(k.Image); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateImage(image string) error {
+	u, err := url.Parse(image)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || len(u.Host) == 0 {
+		return errors.New("image should be an absolute http(s) url")
+	}
+
+	return nil
+}
+
+// spaces have to be %20, some authenticators show '+' as it is
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// label is "issuer:account name", parameters are in fixed order
+func (k Key) String() string {
+	label := url.PathEscape(k.AccountName)
+	if len(k.Issuer) > 0 {
+		label = url.PathEscape(k.Issuer) + ":" + label
+	}
+
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%s://%s/%s", KEY_URI_SCHEME, k.Params.Type, label)
+	b.WriteString("?secret=")
+	b.WriteString(secret.SecretEncoder().EncodeToString(k.Secret))
+	if len(k.Issuer) > 0 {
+		b.WriteString("&issuer=")
+		b.WriteString(escape(k.Issuer))
+	}
+	b.WriteString("&algorithm=")
+	b.WriteString(string(k.Params.Algorithm))
+	b.WriteString("&digits=")
+	b.WriteString(strconv.Itoa(int(k.Params.Digits)))
+	if k.Params.Type == TypeHotp {
+		b.WriteString("&counter=")
+		b.WriteString(strconv.FormatUint(k.Counter, 10))
+	} else {
+		b.WriteString("&period=")
+		b.WriteString(strconv.FormatUint(uint64(k.Params.Period), 10))
+	}
+	if len(k.Image) > 0 {
+		b.WriteString("&image=")
+		b.WriteString(escape(k.Image))
+	}
+
+	return b.String()
+}
+
+// returns the only value of the parameter, duplicates are rejected
+func single(query url.Values, name string) (string, bool, error) {
+	values, ok := query[name]
+	if !ok {
+		return "", false, nil
+	}
+	if len(values) != 1 {
+		return "", false, fmt.Errorf("duplicated parameter %s", name)
+	}
+
+	return values[0], true, nil
+}
+
+// cuts the escaped label at the first colon, which may be url-encoded,
+// returns the whole label as the account name without colon
+func cutLabel(label string) (string, string, bool) {
+	i := strings.Index(label, ":")
+	sep := 1
+	if j := strings.Index(strings.ToUpper(label), "%3A"); j >= 0 && (i < 0 || j < i) {
+		i, sep = j, 3
+	}
+	if i < 0 {
+		return "", label, false
+	}
+
+	return label[:i], label[i+sep:], true
+}
+
+func ParseURI(uri string) (Key, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return Key{}, err
+	}
+	if u.Scheme != KEY_URI_SCHEME {
+		return Key{}, errors.New("unexpected scheme")
+	}
+	if len(u.Opaque) > 0 || u.User != nil || len(u.Fragment) > 0 {
+		return Key{}, errors.New("unexpected uri format")
+	}
+
+	params := Params{
+		Type:      OtpType(strings.ToLower(u.Host)),
+		Algorithm: QR_MFA_ARGORITHM,
+		Digits:    QR_MFA_DIGITS,
+	}
+	if params.Type == TypeTotp {
+		params.Period = QR_MFA_PERIOD
+	}
+
+	key := Key{}
+
+	// split before unescaping, as issuer and account name
+	// may contain escaped slashes, and spaces may precede account name
+	label := strings.TrimPrefix(u.EscapedPath(), "/")
+	if strings.Contains(label, "/") {
+		return Key{}, errors.New("unexpected label format")
+	}
+	issuer, account, found := cutLabel(label)
+	key.AccountName, err = url.PathUnescape(account)
+	if err != nil {
+		return Key{}, err
+	}
+	if found {
+		key.AccountName = strings.TrimLeft(key.AccountName, " ")
+		key.Issuer, err = url.PathUnescape(issuer)
+		if err != nil {
+			return Key{}, err
+		}
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return Key{}, err
+	}
+
+	sec, ok, err := single(query, "secret")
+	if err != nil {
+		return Key{}, err
+	}
+	if !ok {
+		return Key{}, errors.New("missing secret")
+	}
+	// authenticators accept lower case and padding
+	sec = strings.TrimRight(strings.ToUpper(sec), "=")
+	key.Secret, err = secret.SecretEncoder().DecodeString(sec)
+	if err != nil {
+		return Key{}, err
+	}
+
+	issuer, ok, err = single(query, "issuer")
+	if err != nil {
+		return Key{}, err
+	}
+	if ok {
+		if found && issuer != key.Issuer {
+			return Key{}, errors.New("issuer does not match label prefix")
+		}
+		key.Issuer = issuer
+	}
+
+	algorithm, ok, err := single(query, "algorithm")
+	if err != nil {
+		return Key{}, err
+	}
+	if ok {
+		params.Algorithm = Algorithm(strings.ToUpper(algorithm))
+	}
+
+	digits, ok, err := single(query, "digits")
+	if err != nil {
+		return Key{}, err
+	}
+	if ok {
+		d, err := strconv.ParseUint(digits, 10, 8)
+		if err != nil {
+			return Key{}, err
+		}
+		params.Digits = uint8(d)
+	}
+
+	period, ok, err := single(query, "period")
+	if err != nil {
+		return Key{}, err
+	}
+	if ok {
+		if params.Type != TypeTotp {
+			return Key{}, errors.New("period is only for totp")
+		}
+		p, err := strconv.ParseUint(period, 10, 32)
+		if err != nil {
+			return Key{}, err
+		}
+		params.Period = uint32(p)
+	}
+
+	counter, ok, err := single(query, "counter")
+	if err != nil {
+		return Key{}, err
+	}
+	if ok {
+		if params.Type != TypeHotp {
+			return Key{}, errors.New("counter is only for hotp")
+		}
+		key.Counter, err = strconv.ParseUint(counter, 10, 64)
+		if err != nil {
+			return Key{}, err
+		}
+	} else if params.Type == TypeHotp {
+		return Key{}, errors.New("missing counter")
+	}
+
+	image, _, err := single(query, "image")
+	if err != nil {
+		return Key{}, err
+	}
+	key.Image = image
+
+	key.Params = params
+	if err := key.Validate(); err != nil {
+		return Key{}, err
+	}
+
+	return key, nil
+}
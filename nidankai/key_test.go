@@ -0,0 +1,221 @@
+package nidankai
+
+import (
+	"bytes"
+	"testing"
+)
+
+var testSecret = []byte("12345678901234567890")
+
+// base32 of testSecret
+var testSecretB32 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func Test_KeyRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  Key
+	}{
+		{
+			"default totp",
+			Key{
+				Issuer:      "NidanKai",
+				AccountName: "alice@example.com",
+				Secret:      testSecret,
+				Params:      DefaultParams(),
+			},
+		},
+		{
+			"spaces and plus",
+			Key{
+				Issuer:      "ACME Co & Sons",
+				AccountName: "john doe+tag@example.com",
+				Secret:      testSecret,
+				Params: Params{
+					Type:      TypeTotp,
+					Algorithm: AlgorithmSHA512,
+					Digits:    8,
+					Period:    60,
+				},
+			},
+		},
+		{
+			"without issuer",
+			Key{
+				AccountName: "alice@example.com",
+				Secret:      testSecret,
+				Params:      DefaultParams(),
+			},
+		},
+		{
+			"slash in label",
+			Key{
+				Issuer:      "ACME/Tools",
+				AccountName: "dev/ops@example.com",
+				Secret:      testSecret,
+				Params:      DefaultParams(),
+			},
+		},
+		{
+			"hotp with image",
+			Key{
+				Issuer:      "NidanKai",
+				AccountName: "alice@example.com",
+				Secret:      testSecret,
+				Params:      DefaultHotpParams(),
+				Counter:     42,
+				Image:       "https://example.com/icon.png?size=64&v=1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uri := tc.key.String()
+			key, err := ParseURI(uri)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key.Issuer != tc.key.Issuer {
+				t.Fatalf("wrong issuer %s\n", key.Issuer)
+			}
+			if key.AccountName != tc.key.AccountName {
+				t.Fatalf("wrong account name %s\n", key.AccountName)
+			}
+			if !bytes.Equal(key.Secret, tc.key.Secret) {
+				t.Fatal("wrong secret")
+			}
+			if key.Params != tc.key.Params {
+				t.Fatalf("wrong params %v\n", key.Params)
+			}
+			if key.Counter != tc.key.Counter {
+				t.Fatal("wrong counter")
+			}
+			if key.Image != tc.key.Image {
+				t.Fatalf("wrong image %s\n", key.Image)
+			}
+			if key.String() != uri {
+				t.Fatal("not round-tripped")
+			}
+		})
+	}
+}
+
+func TestKey_String(t *testing.T) {
+	key := Key{
+		Issuer:      "ACME Co",
+		AccountName: "john.doe@email.com",
+		Secret:      testSecret,
+		Params:      DefaultParams(),
+	}
+
+	expected := "otpauth://totp/ACME%20Co:john.doe@email.com" +
+		"?secret=" + testSecretB32 +
+		"&issuer=ACME%20Co&algorithm=SHA1&digits=6&period=30"
+	if key.String() != expected {
+		t.Fatalf("unexpected uri %s\n", key.String())
+	}
+}
+
+func Test_ParseURI(t *testing.T) {
+	t.Run("example from the spec", func(t *testing.T) {
+		key, err := ParseURI("otpauth://totp/Example:alice@google.com?secret=JBSWY3DPEHPK3PXP&issuer=Example")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.Issuer != "Example" || key.AccountName != "alice@google.com" {
+			t.Fatal("wrong label")
+		}
+		if key.Params != DefaultParams() {
+			t.Fatal("should fall back to default params")
+		}
+	})
+
+	quirks := []struct {
+		name    string
+		uri     string
+		issuer  string
+		account string
+	}{
+		{
+			"encoded colon",
+			"otpauth://totp/ACME%20Co%3Ajohn.doe@email.com?secret=" + testSecretB32,
+			"ACME Co",
+			"john.doe@email.com",
+		},
+		{
+			"spaces before account name",
+			"otpauth://totp/ACME%20Co:%20%20john.doe@email.com?secret=" + testSecretB32,
+			"ACME Co",
+			"john.doe@email.com",
+		},
+		{
+			"escaped slash and lower case colon",
+			"otpauth://totp/ACME%2FTools%3ajohn?secret=" + testSecretB32,
+			"ACME/Tools",
+			"john",
+		},
+		{
+			"issuer only in parameter",
+			"otpauth://totp/john.doe@email.com?secret=" + testSecretB32 + "&issuer=ACME+Co",
+			"ACME Co",
+			"john.doe@email.com",
+		},
+		{
+			"lower case and padded secret",
+			"otpauth://TOTP/ACME:john?secret=gezdgnbvgy3tqojqgezdgnbvgy3tqojq====&algorithm=sha1",
+			"ACME",
+			"john",
+		},
+	}
+
+	for _, tc := range quirks {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseURI(tc.uri)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key.Issuer != tc.issuer {
+				t.Fatalf("wrong issuer %s\n", key.Issuer)
+			}
+			if key.AccountName != tc.account {
+				t.Fatalf("wrong account name %s\n", key.AccountName)
+			}
+			if !bytes.Equal(key.Secret, testSecret) {
+				t.Fatal("wrong secret")
+			}
+		})
+	}
+
+	invalids := []struct {
+		name string
+		uri  string
+	}{
+		{"wrong scheme", "https://totp/ACME:john?secret=" + testSecretB32},
+		{"unknown type", "otpauth://motp/ACME:john?secret=" + testSecretB32},
+		{"missing secret", "otpauth://totp/ACME:john"},
+		{"empty secret", "otpauth://totp/ACME:john?secret="},
+		{"broken secret", "otpauth://totp/ACME:john?secret=1234"},
+		{"duplicated secret", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&secret=" + testSecretB32},
+		{"issuer mismatch", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&issuer=Other"},
+		{"empty account", "otpauth://totp/ACME:?secret=" + testSecretB32},
+		{"unknown algorithm", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&algorithm=MD5"},
+		{"too many digits", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&digits=9"},
+		{"zero period", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&period=0"},
+		{"negative period", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&period=-30"},
+		{"counter for totp", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&counter=1"},
+		{"hotp without counter", "otpauth://hotp/ACME:john?secret=" + testSecretB32},
+		{"period for hotp", "otpauth://hotp/ACME:john?secret=" + testSecretB32 + "&counter=1&period=30"},
+		{"relative image", "otpauth://totp/ACME:john?secret=" + testSecretB32 + "&image=icon.png"},
+		{"nested label", "otpauth://totp/ACME/john?secret=" + testSecretB32},
+	}
+
+	for _, tc := range invalids {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseURI(tc.uri)
+			if err == nil {
+				t.Fatalf("should fail but returned %v\n", key)
+			}
+		})
+	}
+}
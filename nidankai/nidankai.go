@@ -2,51 +2,103 @@ package nidankai
 
 import (
 	"crypto/subtle"
-	"encoding/base64"
 	"errors"
-	"fmt"
-	"net/url"
-	"nidan-kai/secret"
-	"strconv"
 	"time"
-
-	"github.com/skip2/go-qrcode"
 )
 
-func SetUp(appName, email string, secretKey []byte) (string, error) {
-	encSec := secret.SecretEncoder().EncodeToString(secretKey)
-	path := url.PathEscape(fmt.Sprintf("%s:%s", appName, email))
-	query := url.Values{}
-	query.Set("secret", encSec)
-	query.Set("issuer", appName)
-	query.Set("algorithm", QR_MFA_ARGORITHM)
-	query.Set("digits", strconv.Itoa(QR_MFA_DIGITS))
-	query.Set("period", strconv.Itoa(QR_MFA_PERIOD))
-	url := fmt.Sprintf("otpauth://totp/%s?%s", path, query.Encode())
-
-	qr, err := qrcode.Encode(url, qrcode.Medium, QR_SIZE)
+// returns png in data uri with default options
+func SetUp(key Key) (string, error) {
+	qr, err := Render(key, DataUriRenderer{DefaultQrOptions()})
 	if err != nil {
 		return "", err
 	}
 
-	encQr := base64.StdEncoding.EncodeToString(qr)
-	return "data:image/png;base64," + encQr, nil
+	return string(qr), nil
+}
+
+type Clock func() time.Time
+
+// how many time steps before and after the current one
+// are accepted, to tolerate clock drift of authenticators
+type Window struct {
+	Behind uint
+	Ahead  uint
 }
 
-func Verify(code int, secretKey []byte) (bool, error) {
-	if code >= int(__QrMfaPowered) {
-		return false, errors.New("invalid code")
+func DefaultWindow() Window {
+	return Window{
+		Behind: 1,
+		Ahead:  1,
 	}
+}
 
-	now := time.Now().Unix()
-	otp, err := Totp(secretKey, now, QR_MFA_PERIOD)
-	if err != nil {
-		return false, err
+type Verifier struct {
+	Window Window
+	Clock  Clock
+}
+
+func NewVerifier(window Window, clock Clock) Verifier {
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return Verifier{
+		Window: window,
+		Clock:  clock,
 	}
+}
 
-	if subtle.ConstantTimeEq(otp, int32(code)) != 1 {
-		return false, nil
+// returns the time step the code matched
+func (v Verifier) Verify(code int, secretKey []byte, params Params) (uint64, bool, error) {
+	if err := params.Validate(); err != nil {
+		return 0, false, err
+	}
+	if params.Type != TypeTotp {
+		return 0, false, errors.New("params are not for totp")
+	}
+	if code < 0 || code >= int(params.powered()) {
+		return 0, false, errors.New("invalid code")
 	}
 
-	return true, nil
+	clock := v.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	now := clock().Unix()
+	if now < 0 {
+		return 0, false, errors.New("clock is before unix epoch")
+	}
+
+	current := uint64(now) / uint64(params.Period)
+	from := current - min(uint64(v.Window.Behind), current)
+	to := current + uint64(v.Window.Ahead)
+
+	// check every step in the window without returning early
+	// so that the time taken doesn't tell which step matched
+	matched := uint64(0)
+	ok := 0
+	for step := from; step <= to; step++ {
+		otp, err := Hotp(secretKey, counterNonce(step), params)
+		if err != nil {
+			return 0, false, err
+		}
+
+		eq := subtle.ConstantTimeEq(otp, int32(code))
+		if eq == 1 && ok == 0 {
+			matched = step
+		}
+		ok |= eq
+	}
+
+	if ok != 1 {
+		return 0, false, nil
+	}
+
+	return matched, true, nil
+}
+
+// verifies only the current time step
+func Verify(code int, secretKey []byte, params Params) (uint64, bool, error) {
+	return NewVerifier(Window{}, time.Now).Verify(code, secretKey, params)
 }
@@ -36,7 +36,7 @@ func Test_Hotp(t *testing.T) {
 		t.Run(fmt.Sprintf("count_%d", tc.count), func(t *testing.T) {
 			nonce := [8]byte{}
 			binary.BigEndian.PutUint64(nonce[:], tc.count)
-			code, err := Hotp(secret, nonce)
+			code, err := Hotp(secret, nonce, DefaultParams())
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -64,7 +64,7 @@ func Test_Totp(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(fmt.Sprintf("time_%d", tc.time), func(t *testing.T) {
-			code, err := Totp(secret, tc.time, QR_MFA_PERIOD)
+			code, err := Totp(secret, tc.time, DefaultParams())
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -75,17 +75,101 @@ func Test_Totp(t *testing.T) {
 	}
 }
 
+func Test_TotpParams(t *testing.T) {
+	// Test cases from RFC 6238 Appendix B
+	seeds := map[Algorithm][]byte{
+		AlgorithmSHA1:   []byte("12345678901234567890"),
+		AlgorithmSHA256: []byte("12345678901234567890123456789012"),
+		AlgorithmSHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	testCases := []struct {
+		time      int64
+		algorithm Algorithm
+		expected  int32
+	}{
+		{59, AlgorithmSHA1, 94287082},
+		{59, AlgorithmSHA256, 46119246},
+		{59, AlgorithmSHA512, 90693936},
+		{1111111109, AlgorithmSHA1, 7081804},
+		{1111111109, AlgorithmSHA256, 68084774},
+		{1111111109, AlgorithmSHA512, 25091201},
+		{1111111111, AlgorithmSHA1, 14050471},
+		{1111111111, AlgorithmSHA256, 67062674},
+		{1111111111, AlgorithmSHA512, 99943326},
+		{1234567890, AlgorithmSHA1, 89005924},
+		{1234567890, AlgorithmSHA256, 91819424},
+		{1234567890, AlgorithmSHA512, 93441116},
+		{2000000000, AlgorithmSHA1, 69279037},
+		{2000000000, AlgorithmSHA256, 90698825},
+		{2000000000, AlgorithmSHA512, 38618901},
+		{20000000000, AlgorithmSHA1, 65353130},
+		{20000000000, AlgorithmSHA256, 77737706},
+		{20000000000, AlgorithmSHA512, 47863826},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s_time_%d", tc.algorithm, tc.time), func(t *testing.T) {
+			params := Params{
+				Type:      TypeTotp,
+				Algorithm: tc.algorithm,
+				Digits:    8,
+				Period:    30,
+			}
+			code, err := Totp(seeds[tc.algorithm], tc.time, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.expected != code {
+				t.Fatal("seems generating invlid totp code")
+			}
+		})
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	if err := DefaultParams().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := DefaultHotpParams().Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		params Params
+	}{
+		{"unknown type", Params{Type: "motp", Algorithm: AlgorithmSHA1, Digits: 6, Period: 30}},
+		{"unknown algorithm", Params{Type: TypeTotp, Algorithm: "MD5", Digits: 6, Period: 30}},
+		{"too few digits", Params{Type: TypeTotp, Algorithm: AlgorithmSHA1, Digits: 5, Period: 30}},
+		{"too many digits", Params{Type: TypeTotp, Algorithm: AlgorithmSHA1, Digits: 9, Period: 30}},
+		{"zero period", Params{Type: TypeTotp, Algorithm: AlgorithmSHA1, Digits: 6, Period: 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.params.Validate(); err == nil {
+				t.Fatal("should fail but returned nil")
+			}
+		})
+	}
+}
+
 func TestNidanKai_SetUp(t *testing.T) {
 	t.Setenv(envKey, testKEY)
 	envStore := envkey.EnvKey{}
-	secret, err := secret.GenerateEncryptedSecret(envStore)
+	secret, err := secret.GenerateEncryptedSecret(envStore, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	issuer := "TestApp"
 	email := "test@example.com"
 
-	qrString, err := SetUp(issuer, email, secret)
+	qrString, err := SetUp(Key{
+		Issuer:      issuer,
+		AccountName: email,
+		Secret:      secret,
+		Params:      DefaultParams(),
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -95,24 +179,37 @@ func TestNidanKai_SetUp(t *testing.T) {
 	if !strings.HasPrefix(qrString, "data:image/png;base64,") {
 		t.Fatal("invalid qr format")
 	}
+
+	hotpString, err := SetUp(Key{
+		Issuer:      issuer,
+		AccountName: email,
+		Secret:      secret,
+		Params:      DefaultHotpParams(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hotpString, "data:image/png;base64,") {
+		t.Fatal("invalid qr format")
+	}
 }
 
 func TestNidanKai_Verify(t *testing.T) {
 	t.Setenv(envKey, testKEY)
 	envStore := envkey.EnvKey{}
-	secret, err := secret.GenerateEncryptedSecret(envStore)
+	secret, err := secret.GenerateEncryptedSecret(envStore, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	t.Run("should success", func(t *testing.T) {
 		now := time.Now().Unix()
-		correctCode, err := Totp(secret, now, QR_MFA_PERIOD)
+		correctCode, err := Totp(secret, now, DefaultParams())
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		ok, err := Verify(int(correctCode), secret)
+		_, ok, err := Verify(int(correctCode), secret, DefaultParams())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -123,7 +220,7 @@ func TestNidanKai_Verify(t *testing.T) {
 
 	t.Run("should fail", func(t *testing.T) {
 		now := time.Now().Unix()
-		correctCode, err := Totp(secret, now, QR_MFA_PERIOD)
+		correctCode, err := Totp(secret, now, DefaultParams())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -131,7 +228,7 @@ func TestNidanKai_Verify(t *testing.T) {
 		// Get an incorrect code
 		incorrectCode := (correctCode + 1) % 1000000
 
-		ok, err := Verify(int(incorrectCode), secret)
+		_, ok, err := Verify(int(incorrectCode), secret, DefaultParams())
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -140,10 +237,90 @@ func TestNidanKai_Verify(t *testing.T) {
 		}
 	})
 
+	t.Run("should success with sha512 and 8 digits", func(t *testing.T) {
+		params := Params{
+			Type:      TypeTotp,
+			Algorithm: AlgorithmSHA512,
+			Digits:    8,
+			Period:    60,
+		}
+		now := time.Now().Unix()
+		correctCode, err := Totp(secret, now, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, ok, err := Verify(int(correctCode), secret, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("should success but returned false")
+		}
+	})
+
 	t.Run("should return error for invalid code format", func(t *testing.T) {
-		ok, err := Verify(1000000, secret)
+		_, ok, err := Verify(1000000, secret, DefaultParams())
 		if err == nil || ok {
 			t.Fatal("should fail but retured ok")
 		}
 	})
 }
+
+func TestVerifier_Window(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	params := DefaultParams()
+	// 1111111109 is step 37037036
+	now := time.Unix(1111111109, 0)
+	clock := func() time.Time { return now }
+
+	codeAt := func(t *testing.T, step int64) int {
+		code, err := Totp(secret, step*int64(params.Period), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return int(code)
+	}
+
+	testCases := []struct {
+		name   string
+		window Window
+		step   int64
+		ok     bool
+	}{
+		{"current step without window", Window{}, 37037036, true},
+		{"previous step without window", Window{}, 37037035, false},
+		{"previous step within window", Window{Behind: 1}, 37037035, true},
+		{"next step within window", Window{Ahead: 1}, 37037037, true},
+		{"next step out of behind window", Window{Behind: 1}, 37037037, false},
+		{"two steps behind with window 1", DefaultWindow(), 37037034, false},
+		{"two steps behind with window 2", Window{Behind: 2}, 37037034, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewVerifier(tc.window, clock)
+			step, ok, err := v.Verify(codeAt(t, tc.step), secret, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tc.ok {
+				t.Fatalf("expected %v but returned %v", tc.ok, ok)
+			}
+			if ok && step != uint64(tc.step) {
+				t.Fatalf("expected step %d but returned %d", tc.step, step)
+			}
+		})
+	}
+
+	t.Run("should not underflow near epoch", func(t *testing.T) {
+		v := NewVerifier(Window{Behind: 5}, func() time.Time { return time.Unix(10, 0) })
+		step, ok, err := v.Verify(codeAt(t, 0), secret, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || step != 0 {
+			t.Fatal("should match step 0")
+		}
+	})
+}
@@ -3,39 +3,138 @@ package nidankai
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/binary"
 	"errors"
+	"hash"
 	"math"
 )
 
 const QR_SIZE = 256
 const QR_MFA_DIGITS = 6
-const QR_MFA_ARGORITHM = "SHA1"
+const QR_MFA_ARGORITHM = AlgorithmSHA1
 const QR_MFA_PERIOD = 30
 
-var __QrMfaPowered = uint32(math.Pow10(QR_MFA_DIGITS))
+const QR_MFA_MIN_DIGITS = 6
+const QR_MFA_MAX_DIGITS = 8
 
-func Hotp(secret []byte, nonce [8]byte) (int32, error) {
-	hmac := hmac.New(sha1.New, secret)
+// counters accepted ahead of the stored one (RFC 4226 7.4)
+const QR_MFA_HOTP_LOOK_AHEAD = 10
+
+// counters searched for two consecutive codes on resync
+const QR_MFA_HOTP_RESYNC_WINDOW = 1000
+
+type OtpType string
+
+const (
+	TypeTotp OtpType = "totp"
+	TypeHotp OtpType = "hotp"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmSHA1   Algorithm = "SHA1"
+	AlgorithmSHA256 Algorithm = "SHA256"
+	AlgorithmSHA512 Algorithm = "SHA512"
+)
+
+func (a Algorithm) hasher() (func() hash.Hash, error) {
+	switch a {
+	case AlgorithmSHA1:
+		return sha1.New, nil
+	case AlgorithmSHA256:
+		return sha256.New, nil
+	case AlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, errors.New("unsupported algorithm")
+	}
+}
+
+// parameters an otp secret is issued with,
+// these have to be kept together with the secret
+type Params struct {
+	Type      OtpType
+	Algorithm Algorithm
+	Digits    uint8
+	Period    uint32
+}
+
+func DefaultParams() Params {
+	return Params{
+		Type:      TypeTotp,
+		Algorithm: QR_MFA_ARGORITHM,
+		Digits:    QR_MFA_DIGITS,
+		Period:    QR_MFA_PERIOD,
+	}
+}
+
+func DefaultHotpParams() Params {
+	return Params{
+		Type:      TypeHotp,
+		Algorithm: QR_MFA_ARGORITHM,
+		Digits:    QR_MFA_DIGITS,
+	}
+}
+
+func (p Params) Validate() error {
+	if p.Type != TypeTotp && p.Type != TypeHotp {
+		return errors.New("unsupported otp type")
+	}
+	if _, err := p.Algorithm.hasher(); err != nil {
+		return err
+	}
+	if p.Digits < QR_MFA_MIN_DIGITS || p.Digits > QR_MFA_MAX_DIGITS {
+		return errors.New("digits should be in 6-8")
+	}
+	// period is not used for hotp
+	if p.Type == TypeTotp && p.Period == 0 {
+		return errors.New("period should not be 0")
+	}
+
+	return nil
+}
+
+func (p Params) powered() uint32 {
+	return uint32(math.Pow10(int(p.Digits)))
+}
+
+func Hotp(secret []byte, nonce [8]byte, params Params) (int32, error) {
+	if err := params.Validate(); err != nil {
+		return 0, err
+	}
+
+	hasher, err := params.Algorithm.hasher()
+	if err != nil {
+		return 0, err
+	}
+
+	hmac := hmac.New(hasher, secret)
 	if n, err := hmac.Write(nonce[:]); err != nil || n != 8 {
 		return 0, errors.New("failed to write noce to hasher")
 	}
 
-	h := hmac.Sum(nil)                // sha1.Size=20
+	h := hmac.Sum(nil)                // sha1.Size=20, sha512.Size=64
 	offset := int(h[len(h)-1] & 0x0f) // max=15
 	n := binary.BigEndian.Uint32(h[offset : offset+4])
-	n &= 0x7fffffff                   // 0b01111111......
-	code := int32(n % __QrMfaPowered) // max=999999
+	n &= 0x7fffffff                     // 0b01111111......
+	code := int32(n % params.powered()) // max=99999999
 	return code, nil
 }
 
-func Totp(secret []byte, t int64, p uint32) (int32, error) {
-	if p == 0 {
+func Totp(secret []byte, t int64, params Params) (int32, error) {
+	if params.Period == 0 {
 		return 0, errors.New("period should not be 0")
 	}
 
-	counter := uint64(t / int64(p))
+	counter := uint64(t / int64(params.Period))
+	return Hotp(secret, counterNonce(counter), params)
+}
+
+func counterNonce(counter uint64) [8]byte {
 	buf := [8]byte{}
 	binary.BigEndian.PutUint64(buf[:], counter)
-	return Hotp(secret, buf)
+	return buf
 }
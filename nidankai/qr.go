@@ -0,0 +1,202 @@
+package nidankai
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+type RecoveryLevel = qrcode.RecoveryLevel
+
+const (
+	RecoveryLow     RecoveryLevel = qrcode.Low
+	RecoveryMedium  RecoveryLevel = qrcode.Medium
+	RecoveryHigh    RecoveryLevel = qrcode.High
+	RecoveryHighest RecoveryLevel = qrcode.Highest
+)
+
+type QrOptions struct {
+	// width and height in pixels, not used for terminal
+	Size  int
+	Level RecoveryLevel
+}
+
+func DefaultQrOptions() QrOptions {
+	return QrOptions{
+		Size:  QR_SIZE,
+		Level: RecoveryMedium,
+	}
+}
+
+func (o QrOptions) Validate() error {
+	if o.Size <= 0 {
+		return errors.New("size should be positive")
+	}
+	if o.Level < RecoveryLow || o.Level > RecoveryHighest {
+		return errors.New("unknown recovery level")
+	}
+
+	return nil
+}
+
+type Renderer interface {
+	Render(content string) ([]byte, error)
+	ContentType() string
+}
+
+type PngRenderer struct {
+	Options QrOptions
+}
+
+func (r PngRenderer) Render(content string) ([]byte, error) {
+	if err := r.Options.Validate(); err != nil {
+		return nil, err
+	}
+
+	qr, err := qrcode.New(content, r.Options.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	return qr.PNG(r.Options.Size)
+}
+
+func (r PngRenderer) ContentType() string {
+	return "image/png"
+}
+
+// png embedded in data uri, to be set to src of img directly
+type DataUriRenderer struct {
+	Options QrOptions
+}
+
+func (r DataUriRenderer) Render(content string) ([]byte, error) {
+	png, err := PngRenderer(r).Render(content)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := "data:image/png;base64,"
+	b := make([]byte, len(prefix)+base64.StdEncoding.EncodedLen(len(png)))
+	copy(b, prefix)
+	base64.StdEncoding.Encode(b[len(prefix):], png)
+	return b, nil
+}
+
+func (r DataUriRenderer) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+type SvgRenderer struct {
+	Options QrOptions
+}
+
+// one path with a sub path for each horizontal run of dark modules
+func (r SvgRenderer) Render(content string) ([]byte, error) {
+	if err := r.Options.Validate(); err != nil {
+		return nil, err
+	}
+
+	qr, err := qrcode.New(content, r.Options.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	bitmap := qr.Bitmap()
+	n := len(bitmap)
+
+	b := bytes.Buffer{}
+	fmt.Fprintf(
+		&b,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		r.Options.Size,
+		r.Options.Size,
+		n,
+		n,
+	)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, n, n)
+	b.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	b.WriteString(`"/></svg>`)
+
+	return b.Bytes(), nil
+}
+
+func (r SvgRenderer) ContentType() string {
+	return "image/svg+xml"
+}
+
+// two modules in a character with unicode half blocks,
+// blocks are light modules, which fits dark terminals
+type TerminalRenderer struct {
+	Level RecoveryLevel
+	// blocks are dark modules, for light terminals
+	Invert bool
+}
+
+func (r TerminalRenderer) Render(content string) ([]byte, error) {
+	if r.Level < RecoveryLow || r.Level > RecoveryHighest {
+		return nil, errors.New("unknown recovery level")
+	}
+
+	qr, err := qrcode.New(content, r.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	bitmap := qr.Bitmap()
+	b := strings.Builder{}
+	for y := 0; y < len(bitmap); y += 2 {
+		for x := range bitmap[y] {
+			top := !bitmap[y][x]
+			// out of the bitmap is quiet zone, which is light
+			bottom := y+1 >= len(bitmap) || !bitmap[y+1][x]
+			if r.Invert {
+				top, bottom = !top, !bottom
+			}
+
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	return []byte(b.String()), nil
+}
+
+func (r TerminalRenderer) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func Render(key Key, renderer Renderer) ([]byte, error) {
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+
+	return renderer.Render(key.String())
+}
@@ -0,0 +1,122 @@
+package nidankai
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+)
+
+var testKey = Key{
+	Issuer:      "NidanKai",
+	AccountName: "alice@example.com",
+	Secret:      testSecret,
+	Params:      DefaultParams(),
+}
+
+func Test_RenderPng(t *testing.T) {
+	b, err := Render(testKey, PngRenderer{QrOptions{Size: 128, Level: RecoveryHigh}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 128 || img.Bounds().Dy() != 128 {
+		t.Fatal("wrong image size")
+	}
+}
+
+func Test_RenderDataUri(t *testing.T) {
+	b, err := Render(testKey, DataUriRenderer{DefaultQrOptions()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), "data:image/png;base64,") {
+		t.Fatal("invalid data uri")
+	}
+}
+
+func Test_RenderSvg(t *testing.T) {
+	b, err := Render(testKey, SvgRenderer{DefaultQrOptions()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svg := struct {
+		XMLName xml.Name `xml:"svg"`
+		Width   int      `xml:"width,attr"`
+		Paths   []struct {
+			D string `xml:"d,attr"`
+		} `xml:"path"`
+	}{}
+	if err := xml.Unmarshal(b, &svg); err != nil {
+		t.Fatal(err)
+	}
+	if svg.Width != QR_SIZE {
+		t.Fatal("wrong svg size")
+	}
+	if len(svg.Paths) != 1 || len(svg.Paths[0].D) == 0 {
+		t.Fatal("empty svg")
+	}
+}
+
+func Test_RenderTerminal(t *testing.T) {
+	qr, err := qrcode.New(testKey.String(), RecoveryLow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := len(qr.Bitmap())
+
+	for _, invert := range []bool{false, true} {
+		b, err := Render(testKey, TerminalRenderer{Level: RecoveryLow, Invert: invert})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+		if len(lines) != (n+1)/2 {
+			t.Fatalf("expected %d lines but got %d", (n+1)/2, len(lines))
+		}
+		for _, line := range lines {
+			if utf8.RuneCountInString(line) != n {
+				t.Fatal("wrong line width")
+			}
+		}
+
+		// top left is the quiet zone
+		quiet, _ := utf8.DecodeRuneInString(lines[0])
+		if !invert && quiet != '█' {
+			t.Fatal("quiet zone should be blocks")
+		}
+		if invert && quiet != ' ' {
+			t.Fatal("inverted quiet zone should be spaces")
+		}
+	}
+}
+
+func Test_RenderFail(t *testing.T) {
+	renderers := []Renderer{
+		PngRenderer{QrOptions{Size: 0, Level: RecoveryMedium}},
+		SvgRenderer{QrOptions{Size: 256, Level: RecoveryLevel(9)}},
+		TerminalRenderer{Level: RecoveryLevel(-1)},
+	}
+
+	for _, r := range renderers {
+		b, err := Render(testKey, r)
+		if err == nil {
+			t.Fatalf("should fail but returned %v\n", b)
+		}
+	}
+
+	_, err := Render(Key{}, PngRenderer{DefaultQrOptions()})
+	if err == nil {
+		t.Fatal("should fail with invalid key")
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// full buckets are removed at most once in this
+const SWEEP_INTERVAL = time.Minute
+
+type memoryEntry struct {
+	bucket bucket
+	fullAt time.Time
+}
+
+// for a single server instance, buckets are lost on restart
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]memoryEntry
+	sweptAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]memoryEntry{},
+	}
+}
+
+func (s *MemoryStore) Take(c context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	if err := limit.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	entry, ok := s.buckets[key]
+	if !ok {
+		entry.bucket = newBucket(limit, now)
+	}
+
+	b, res := entry.bucket.take(limit, now)
+	if res.Allowed {
+		s.buckets[key] = memoryEntry{
+			bucket: b,
+			fullAt: b.fullAt(limit),
+		}
+	}
+
+	return res, nil
+}
+
+// keeps memory from growing with every client seen
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < SWEEP_INTERVAL {
+		return
+	}
+	s.sweptAt = now
+
+	for key, entry := range s.buckets {
+		if !entry.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
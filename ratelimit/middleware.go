@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"errors"
+	"expvar"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// counts by route, published with expvar on "METRICS_ADDR"
+var rejected = expvar.NewMap("ratelimit_rejected")
+var storeErrors = expvar.NewMap("ratelimit_store_errors")
+
+// limits requests by route and client ip, the client ip is
+// taken by echo's IPExtractor, see NewIPExtractor,
+// requests are let through when the store fails
+// as users are locked out by the app anyway
+func Middleware(store Store, limit Limit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			route := ctx.Path()
+			key := route + " " + ctx.RealIP()
+
+			res, err := store.Take(ctx.Request().Context(), key, limit, time.Now())
+			if err != nil {
+				ctx.Logger().Error(err)
+				storeErrors.Add(route, 1)
+				return next(ctx)
+			}
+
+			if !res.Allowed {
+				ctx.Logger().Warn("rate limited")
+				rejected.Add(route, 1)
+				secs := int(math.Ceil(res.RetryAfter.Seconds()))
+				ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(secs, 1)))
+				return echo.ErrTooManyRequests
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// takes the client ip from "X-Forwarded-For" only when the request is
+// from "TRUSTED_PROXIES" like "10.0.0.0/8,192.0.2.1",
+// the remote address is used as it is when not set
+func NewIPExtractor() (echo.IPExtractor, error) {
+	config := os.Getenv("TRUSTED_PROXIES")
+	if len(config) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// nothing is trusted unless listed
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, raw := range strings.Split(config, ",") {
+		ipNet, err := parseProxy(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
+// accepts a single ip as well as cidr
+func parseProxy(raw string) (*net.IPNet, error) {
+	if strings.Contains(raw, "/") {
+		_, ipNet, err := net.ParseCIDR(raw)
+		return ipNet, err
+	}
+
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return nil, errors.New("invalid trusted proxy")
+	}
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 8 * net.IPv4len
+	}
+
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(bits, bits),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// token bucket, holding up to Burst tokens refilled at Rate per second,
+// each request takes one
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// n requests a minute at most, all of them at once
+func PerMinute(n int) Limit {
+	return Limit{
+		Rate:  float64(n) / 60,
+		Burst: n,
+	}
+}
+
+func (l Limit) Validate() error {
+	if l.Rate <= 0 || math.IsInf(l.Rate, 0) || math.IsNaN(l.Rate) {
+		return errors.New("rate should be positive")
+	}
+	if l.Burst <= 0 {
+		return errors.New("burst should be positive")
+	}
+	return nil
+}
+
+type Result struct {
+	Allowed bool
+	// until the next token when not allowed
+	RetryAfter time.Duration
+}
+
+// keeps buckets by key, the one shared by server instances
+// has to take tokens atomically between them
+type Store interface {
+	Take(c context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// state of a bucket, tokens as of refilledAt
+type bucket struct {
+	tokens     float64
+	refilledAt time.Time
+}
+
+func newBucket(limit Limit, now time.Time) bucket {
+	return bucket{
+		tokens:     float64(limit.Burst),
+		refilledAt: now,
+	}
+}
+
+// refills and takes a token, the bucket is unchanged when not allowed
+func (b bucket) take(limit Limit, now time.Time) (bucket, Result) {
+	tokens := b.tokens
+	// clock going back doesn't drain the bucket
+	if elapsed := now.Sub(b.refilledAt); elapsed > 0 {
+		tokens = min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	}
+
+	if tokens < 1 {
+		wait := time.Duration(math.Ceil((1 - tokens) / limit.Rate * float64(time.Second)))
+		return b, Result{RetryAfter: wait}
+	}
+
+	return bucket{
+		tokens:     tokens - 1,
+		refilledAt: now,
+	}, Result{Allowed: true}
+}
+
+// when the bucket has refilled and is the same as a new one
+func (b bucket) fullAt(limit Limit) time.Time {
+	missing := float64(limit.Burst) - b.tokens
+	if missing <= 0 {
+		return b.refilledAt
+	}
+	return b.refilledAt.Add(time.Duration(math.Ceil(missing / limit.Rate * float64(time.Second))))
+}
@@ -0,0 +1,260 @@
+package ratelimit
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"nidan-kai/ent"
+	"nidan-kai/ent/enttest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var _ Store = &MemoryStore{}
+var _ Store = &SqlStore{}
+
+var testLimit = Limit{Rate: 1, Burst: 2}
+var testNow = time.Unix(1111111109, 0)
+
+// takes the burst, gets rejected, then refilled
+func testStore(t *testing.T, store Store) {
+	c := context.Background()
+
+	for i := range testLimit.Burst {
+		res, err := store.Take(c, "key", testLimit, testNow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Allowed {
+			t.Fatalf("should allow the burst, rejected at %d\n", i)
+		}
+	}
+
+	res, err := store.Take(c, "key", testLimit, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed {
+		t.Fatal("should reject beyond the burst")
+	}
+	if res.RetryAfter != time.Second {
+		t.Fatal("unexpected retry after")
+	}
+
+	t.Run("should not share buckets between keys", func(t *testing.T) {
+		res, err := store.Take(c, "other", testLimit, testNow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Allowed {
+			t.Fatal("should allow other key")
+		}
+	})
+
+	t.Run("should refill by the rate", func(t *testing.T) {
+		res, err := store.Take(c, "key", testLimit, testNow.Add(time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Allowed {
+			t.Fatal("should be refilled")
+		}
+
+		res, err = store.Take(c, "key", testLimit, testNow.Add(time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allowed {
+			t.Fatal("should refill only one")
+		}
+	})
+}
+
+func TestLimit(t *testing.T) {
+	if PerMinute(60).Validate() != nil {
+		t.Fatal("should be valid")
+	}
+	if (Limit{Rate: 0, Burst: 1}).Validate() == nil {
+		t.Fatal("should be invalid without rate")
+	}
+	if (Limit{Rate: 1, Burst: 0}).Validate() == nil {
+		t.Fatal("should be invalid without burst")
+	}
+}
+
+func TestBucket(t *testing.T) {
+	b := newBucket(testLimit, testNow)
+	if !b.fullAt(testLimit).Equal(testNow) {
+		t.Fatal("new bucket should be full")
+	}
+
+	b, res := b.take(testLimit, testNow)
+	if !res.Allowed {
+		t.Fatal("should allow")
+	}
+	if !b.fullAt(testLimit).Equal(testNow.Add(time.Second)) {
+		t.Fatal("unexpected full at")
+	}
+
+	t.Run("should not drain on clock going back", func(t *testing.T) {
+		_, res := b.take(testLimit, testNow.Add(-time.Hour))
+		if !res.Allowed {
+			t.Fatal("should allow the rest")
+		}
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	testStore(t, store)
+
+	t.Run("should sweep full buckets", func(t *testing.T) {
+		store.Take(context.Background(), "sweep", testLimit, testNow.Add(SWEEP_INTERVAL))
+		if len(store.buckets) != 1 {
+			t.Fatal("full buckets should be removed")
+		}
+	})
+}
+
+func TestSqlStore(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:ratelimit?mode=memory&_fk=1")
+	defer client.Close()
+	c := context.Background()
+
+	store := NewSqlStore(client)
+	testStore(t, store)
+
+	t.Run("should share buckets between instances", func(t *testing.T) {
+		other := NewSqlStore(client)
+		res, err := other.Take(c, "key", testLimit, testNow.Add(time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allowed {
+			t.Fatal("should be taken by the other instance")
+		}
+	})
+
+	t.Run("should reject on contention", func(t *testing.T) {
+		// every update loses to another instance
+		interfering := false
+		client.RateLimitBucket.Use(func(next ent.Mutator) ent.Mutator {
+			return ent.MutateFunc(func(c context.Context, m ent.Mutation) (ent.Value, error) {
+				if m.Op().Is(ent.OpUpdate) && !interfering {
+					interfering = true
+					client.RateLimitBucket.Update().AddVersion(1).ExecX(c)
+					interfering = false
+				}
+				return next.Mutate(c, m)
+			})
+		})
+
+		res, err := store.Take(c, "contended", testLimit, testNow)
+		if err != nil || !res.Allowed {
+			t.Fatal("should create the bucket")
+		}
+		res, err = store.Take(c, "contended", testLimit, testNow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allowed || res.RetryAfter <= 0 {
+			t.Fatal("should reject when contended")
+		}
+	})
+
+	t.Run("should sweep full buckets", func(t *testing.T) {
+		_, err := store.Take(c, "sweep", testLimit, testNow.Add(SWEEP_INTERVAL))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.RateLimitBucket.Query().CountX(c) != 1 {
+			t.Fatal("full buckets should be removed")
+		}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.POST("/limited", func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	}, Middleware(NewMemoryStore(), Limit{Rate: 1, Burst: 1}))
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/limited", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// counted across runs with -count
+	rejectedOf := func() int64 {
+		if v, ok := rejected.Get("/limited").(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	before := rejectedOf()
+
+	if send("192.0.2.1:1234").Code != http.StatusOK {
+		t.Fatal("should allow the first")
+	}
+
+	rec := send("192.0.2.1:1234")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("should reject, got %d\n", rec.Code)
+	}
+	if rec.Header().Get(echo.HeaderRetryAfter) != "1" {
+		t.Fatal("unexpected retry after")
+	}
+	if rejectedOf()-before != 1 {
+		t.Fatal("rejection should be counted")
+	}
+
+	if send("192.0.2.2:1234").Code != http.StatusOK {
+		t.Fatal("should limit by client ip")
+	}
+}
+
+func TestNewIPExtractor(t *testing.T) {
+	newRequest := func(remoteAddr string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.1")
+		return req
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "")
+	extract, err := NewIPExtractor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extract(newRequest("10.0.0.1:1234")) != "10.0.0.1" {
+		t.Fatal("should ignore forwarded for without trusted proxies")
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.0.2.1")
+	extract, err = NewIPExtractor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extract(newRequest("10.0.0.1:1234")) != "203.0.113.1" {
+		t.Fatal("should trust forwarded for from cidr")
+	}
+	if extract(newRequest("192.0.2.1:1234")) != "203.0.113.1" {
+		t.Fatal("should trust forwarded for from ip")
+	}
+	if extract(newRequest("127.0.0.1:1234")) != "127.0.0.1" {
+		t.Fatal("should not trust unlisted proxies")
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "not an ip")
+	if _, err := NewIPExtractor(); err == nil {
+		t.Fatal("should fail on invalid proxy")
+	}
+}
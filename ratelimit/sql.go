@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/ratelimitbucket"
+	"sync"
+	"time"
+)
+
+// tries before giving up on concurrent updates of the same bucket,
+// the request is rejected then rather than let through
+const MAX_SQL_RETRIES = 5
+
+// shared by server instances through the database,
+// buckets are updated with optimistic locking
+type SqlStore struct {
+	client *ent.Client
+
+	mu      sync.Mutex
+	sweptAt time.Time
+}
+
+func NewSqlStore(client *ent.Client) *SqlStore {
+	return &SqlStore{
+		client: client,
+	}
+}
+
+func (s *SqlStore) Take(c context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	if err := limit.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	if err := s.sweep(c, now); err != nil {
+		return Result{}, err
+	}
+
+	for range MAX_SQL_RETRIES {
+		res, ok, err := s.take(c, key, limit, now)
+		if err != nil {
+			return Result{}, err
+		}
+		if ok {
+			return res, nil
+		}
+	}
+
+	// a burst on the same key, which is what this limits
+	return Result{RetryAfter: time.Duration(float64(time.Second) / limit.Rate)}, nil
+}
+
+// returns false when others updated the bucket meanwhile
+func (s *SqlStore) take(c context.Context, key string, limit Limit, now time.Time) (Result, bool, error) {
+	row, err := s.client.RateLimitBucket.Query().
+		Where(ratelimitbucket.Key(key)).
+		Only(c)
+	if ent.IsNotFound(err) {
+		b, res := newBucket(limit, now).take(limit, now)
+
+		id, err := binid.NewSequential()
+		if err != nil {
+			return Result{}, false, err
+		}
+		err = s.client.RateLimitBucket.Create().
+			SetID(id).
+			SetKey(key).
+			SetTokens(b.tokens).
+			SetRefilledAt(b.refilledAt).
+			SetFullAt(b.fullAt(limit)).
+			Exec(c)
+		if ent.IsConstraintError(err) {
+			// created by others meanwhile
+			return Result{}, false, nil
+		} else if err != nil {
+			return Result{}, false, err
+		}
+		return res, true, nil
+	} else if err != nil {
+		return Result{}, false, err
+	}
+
+	b, res := bucket{
+		tokens:     row.Tokens,
+		refilledAt: row.RefilledAt,
+	}.take(limit, now)
+	if !res.Allowed {
+		return res, true, nil
+	}
+
+	n, err := s.client.RateLimitBucket.Update().
+		Where(
+			ratelimitbucket.ID(row.ID),
+			ratelimitbucket.Version(row.Version),
+		).
+		SetTokens(b.tokens).
+		SetRefilledAt(b.refilledAt).
+		SetFullAt(b.fullAt(limit)).
+		AddVersion(1).
+		Save(c)
+	if err != nil {
+		return Result{}, false, err
+	}
+
+	return res, n == 1, nil
+}
+
+// removes full buckets at most once in SWEEP_INTERVAL on each instance
+func (s *SqlStore) sweep(c context.Context, now time.Time) error {
+	s.mu.Lock()
+	if now.Sub(s.sweptAt) < SWEEP_INTERVAL {
+		s.mu.Unlock()
+		return nil
+	}
+	s.sweptAt = now
+	s.mu.Unlock()
+
+	_, err := s.client.RateLimitBucket.Delete().
+		Where(ratelimitbucket.FullAtLTE(now)).
+		Exec(c)
+	return err
+}
@@ -0,0 +1,142 @@
+package recovery
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const CODE_COUNT = 10
+const CODE_LEN = 10 // (10 * 5 = 50bits)
+
+// argon2id, as recommended by OWASP
+const HASH_TIME = 2
+const HASH_MEMORY = 19 * 1024
+const HASH_THREADS = 1
+const HASH_SALT_LEN = 16
+const HASH_KEY_LEN = 32
+
+// without ambiguous letters like 0/O and 1/I
+const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func Generate() (string, error) {
+	b := make([]byte, CODE_LEN)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// len(alphabet) is 32, so masking keeps it uniform
+	for i := range b {
+		b[i] = alphabet[b[i]&0x1f]
+	}
+
+	half := CODE_LEN / 2
+	return string(b[:half]) + "-" + string(b[half:]), nil
+}
+
+func GenerateSet() ([]string, error) {
+	codes := make([]string, CODE_COUNT)
+	for i := range codes {
+		code, err := Generate()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+
+	return codes, nil
+}
+
+// accepts lower case and missing or extra separators
+func Normalize(code string) (string, error) {
+	b := strings.Builder{}
+	for _, r := range strings.ToUpper(code) {
+		if r == '-' || r == ' ' {
+			continue
+		}
+		if !strings.ContainsRune(alphabet, r) {
+			return "", errors.New("invalid character in recovery code")
+		}
+		b.WriteRune(r)
+	}
+
+	if b.Len() != CODE_LEN {
+		return "", errors.New("unexpected recovery code length")
+	}
+
+	return b.String(), nil
+}
+
+// returns phc formatted string, parameters are kept within it
+// so that they can be tuned without breaking stored hashes
+func Hash(code string) (string, error) {
+	norm, err := Normalize(code)
+	if err != nil {
+		return "", err
+	}
+
+	salt := make([]byte, HASH_SALT_LEN)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(norm), salt, HASH_TIME, HASH_MEMORY, HASH_THREADS, HASH_KEY_LEN)
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		HASH_MEMORY,
+		HASH_TIME,
+		HASH_THREADS,
+		enc.EncodeToString(salt),
+		enc.EncodeToString(key),
+	), nil
+}
+
+func Compare(code, hashed string) (bool, error) {
+	norm, err := Normalize(code)
+	if err != nil {
+		return false, err
+	}
+
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return false, errors.New("unexpected hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, err
+	}
+	if version != argon2.Version {
+		return false, errors.New("unsupported argon2 version")
+	}
+
+	var memory, time uint32
+	var threads uint8
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads)
+	if err != nil {
+		return false, err
+	}
+
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[4])
+	if err != nil {
+		return false, err
+	}
+	expected, err := enc.DecodeString(parts[5])
+	if err != nil {
+		return false, err
+	}
+	if len(expected) == 0 {
+		return false, errors.New("empty hash")
+	}
+
+	key := argon2.IDKey([]byte(norm), salt, time, memory, threads, uint32(len(expected)))
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
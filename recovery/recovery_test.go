@@ -0,0 +1,117 @@
+package recovery
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Generate(t *testing.T) {
+	codes, err := GenerateSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != CODE_COUNT {
+		t.Fatal("wrong number of codes")
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != CODE_LEN+1 {
+			t.Fatalf("unexpected code %s\n", code)
+		}
+		if _, err := Normalize(code); err != nil {
+			t.Fatal(err)
+		}
+		if seen[code] {
+			t.Fatal("code is not random")
+		}
+		seen[code] = true
+	}
+}
+
+func Test_Normalize(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"ABCDE-FGHJK", "ABCDEFGHJK", true},
+		{"abcde-fghjk", "ABCDEFGHJK", true},
+		{" abcde fghjk ", "ABCDEFGHJK", true},
+		{"ABCDEFGHJK", "ABCDEFGHJK", true},
+		{"ABCDE-FGHJ", "", false},
+		{"ABCDE-FGHJKL", "", false},
+		{"ABCDE-FGHI0", "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			norm, err := Normalize(tc.input)
+			if tc.ok && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatalf("should fail but returned %s\n", norm)
+			}
+			if norm != tc.expected {
+				t.Fatal("wrong normalized code")
+			}
+		})
+	}
+}
+
+func Test_HashCompare(t *testing.T) {
+	code, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashed, err := Hash(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(hashed, strings.ReplaceAll(code, "-", "")) {
+		t.Fatal("hash contains the code")
+	}
+
+	hashed1, err := Hash(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashed == hashed1 {
+		t.Fatal("hash is not salted")
+	}
+
+	t.Run("should match", func(t *testing.T) {
+		ok, err := Compare(strings.ToLower(code), hashed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("should match but returned false")
+		}
+	})
+
+	t.Run("should not match", func(t *testing.T) {
+		other, err := Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ok, err := Compare(other, hashed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("should not match but returned true")
+		}
+	})
+
+	t.Run("should fail with broken hash", func(t *testing.T) {
+		ok, err := Compare(code, "$argon2id$v=19$m=1,t=1,p=1$")
+		if err == nil || ok {
+			t.Fatal("should fail but returned ok")
+		}
+	})
+}
@@ -0,0 +1,303 @@
+package reencrypt
+
+import (
+	"context"
+	"errors"
+	"log"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/jobcheckpoint"
+	"nidan-kai/ent/mfaqr"
+	"nidan-kai/secret"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const DEFAULT_BATCH_SIZE = 100
+const DEFAULT_JOB_NAME = "reencrypt-mfa-qrs"
+const DEFAULT_REWRAP_JOB_NAME = "rewrap-data-keys"
+
+type Options struct {
+	// checkpoint name, jobs with the same name resume each other
+	Name      string
+	BatchSize int
+	// ignores the checkpoint and starts from the first row
+	Restart bool
+	Logger  *log.Logger
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Name:      DEFAULT_JOB_NAME,
+		BatchSize: DEFAULT_BATCH_SIZE,
+		Logger:    log.Default(),
+	}
+}
+
+type Stats struct {
+	Scanned     uint64
+	Reencrypted uint64
+	// already under the current key, or changed by others meanwhile
+	Skipped uint64
+	Failed  uint64
+}
+
+// loads the checkpoint to resume from, creates one for the first run
+func loadCheckpoint(
+	c context.Context,
+	client *ent.Client,
+	opts Options,
+) (*ent.JobCheckpoint, error) {
+	cp, err := client.JobCheckpoint.Query().
+		Where(jobcheckpoint.Name(opts.Name)).
+		Only(c)
+	if ent.IsNotFound(err) {
+		id, err := binid.NewSequential()
+		if err != nil {
+			return nil, err
+		}
+
+		return client.JobCheckpoint.Create().
+			SetID(id).
+			SetName(opts.Name).
+			Save(c)
+	} else if err != nil {
+		return nil, err
+	}
+
+	// completed job starts over, for the next rotation
+	if opts.Restart || cp.CompletedAt != nil {
+		return cp.Update().
+			ClearCursor().
+			SetProcessed(0).
+			ClearFailedIds().
+			ClearCompletedAt().
+			Save(c)
+	}
+
+	return cp, nil
+}
+
+// re-encrypts a row under the current key, returns false when skipped
+func reencryptRow(
+	c context.Context,
+	client *ent.Client,
+	encryptor secret.Encryptor,
+	row *ent.MfaQr,
+) (bool, error) {
+	// not encrypted with the master key
+	if row.DataKeyID != nil {
+		return false, nil
+	}
+
+	ad := secret.MfaQrContext(row.ID, row.UserID)
+	if row.SecretBound {
+		current, err := encryptor.IsCurrent(row.Secret)
+		if err != nil {
+			return false, err
+		}
+		if current {
+			return false, nil
+		}
+	}
+
+	var oldAd []byte
+	if row.SecretBound {
+		oldAd = ad
+	}
+	sec, err := encryptor.Decrypt(row.Secret, oldAd)
+	if err != nil {
+		return false, err
+	}
+
+	enc, err := encryptor.Encrypt(sec, ad)
+	if err != nil {
+		return false, err
+	}
+
+	// the row may be rewritten by the server meanwhile,
+	// the condition keeps this from overwriting it
+	n, err := client.MfaQr.Update().
+		Where(
+			mfaqr.ID(row.ID),
+			mfaqr.SecretEQ(row.Secret),
+		).
+		SetSecret(enc).
+		SetSecretBound(true).
+		Save(c)
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// walks rows in batches by id, checkpointing after each batch,
+// fetch returns rows after the cursor in order of id,
+// fetchIds returns the rows failed last time to be retried first,
+// the job is left incomplete while any row fails
+func walk[T any](
+	c context.Context,
+	client *ent.Client,
+	opts Options,
+	fetch func(after *binid.BinId) ([]T, error),
+	fetchIds func(ids []binid.BinId) ([]T, error),
+	idOf func(row T) binid.BinId,
+	process func(row T) (bool, error),
+) (Stats, error) {
+	stats := Stats{}
+	if len(opts.Name) == 0 {
+		return stats, errors.New("job name should not be empty")
+	}
+	if opts.BatchSize <= 0 {
+		return stats, errors.New("batch size should be positive")
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	cp, err := loadCheckpoint(c, client, opts)
+	if err != nil {
+		return stats, err
+	}
+	if cp.Cursor != nil {
+		logger.Printf("resuming after %s, %d rows processed\n", cp.Cursor.String(), cp.Processed)
+	}
+
+	failed := []string{}
+	handle := func(row T) {
+		stats.Scanned++
+
+		ok, err := process(row)
+		if err != nil {
+			// keep going, failed rows are left as they are
+			logger.Printf("failed to re-encrypt %s: %v\n", idOf(row).String(), err)
+			stats.Failed++
+			failed = append(failed, idOf(row).String())
+			return
+		}
+		if ok {
+			stats.Reencrypted++
+		} else {
+			stats.Skipped++
+		}
+	}
+
+	if len(cp.FailedIds) > 0 {
+		logger.Printf("retrying %d rows failed last time\n", len(cp.FailedIds))
+		ids := make([]binid.BinId, len(cp.FailedIds))
+		for i, raw := range cp.FailedIds {
+			ids[i], err = binid.FromUUIDString(raw)
+			if err != nil {
+				return stats, err
+			}
+		}
+
+		// rows deleted meanwhile are not returned, and forgotten
+		rows, err := fetchIds(ids)
+		if err != nil {
+			return stats, err
+		}
+		for _, row := range rows {
+			handle(row)
+		}
+	}
+
+	for {
+		if err := c.Err(); err != nil {
+			return stats, err
+		}
+
+		rows, err := fetch(cp.Cursor)
+		if err != nil {
+			return stats, err
+		}
+
+		for _, row := range rows {
+			handle(row)
+		}
+
+		done := len(rows) < opts.BatchSize
+		update := cp.Update().
+			AddProcessed(int64(len(rows))).
+			SetFailedIds(failed)
+		if len(rows) > 0 {
+			update.SetCursor(idOf(rows[len(rows)-1]))
+		}
+		if done && len(failed) == 0 {
+			update.SetCompletedAt(time.Now())
+		}
+		cp, err = update.Save(c)
+		if err != nil {
+			return stats, err
+		}
+
+		logger.Printf(
+			"processed %d rows, re-encrypted: %d skipped: %d failed: %d\n",
+			cp.Processed,
+			stats.Reencrypted,
+			stats.Skipped,
+			stats.Failed,
+		)
+
+		if done {
+			if len(failed) > 0 {
+				logger.Printf("%d rows failed, run again to retry them\n", len(failed))
+			}
+			return stats, nil
+		}
+	}
+}
+
+// walks all mfa_qrs rows in batches and re-encrypts secrets under
+// the current key, previous keys have to be kept in the backend
+// until this completes so that the server can keep decrypting,
+// secrets sealed with data keys are skipped, see Rewrap for them
+func Run(
+	c context.Context,
+	client *ent.Client,
+	encryptor secret.Encryptor,
+	opts Options,
+) (Stats, error) {
+	fetch := func(after *binid.BinId) ([]*ent.MfaQr, error) {
+		// soft deleted rows are included, their secrets are still there
+		query := client.MfaQr.Query().
+			Select(
+				mfaqr.FieldID,
+				mfaqr.FieldUserID,
+				mfaqr.FieldSecret,
+				mfaqr.FieldSecretBound,
+				mfaqr.FieldDataKeyID,
+			).
+			Order(sql.OrderByField(mfaqr.FieldID).ToFunc()).
+			Limit(opts.BatchSize)
+		if after != nil {
+			query = query.Where(mfaqr.IDGT(*after))
+		}
+
+		return query.All(c)
+	}
+	fetchIds := func(ids []binid.BinId) ([]*ent.MfaQr, error) {
+		return client.MfaQr.Query().
+			Select(
+				mfaqr.FieldID,
+				mfaqr.FieldUserID,
+				mfaqr.FieldSecret,
+				mfaqr.FieldSecretBound,
+				mfaqr.FieldDataKeyID,
+			).
+			Where(mfaqr.IDIn(ids...)).
+			Order(sql.OrderByField(mfaqr.FieldID).ToFunc()).
+			All(c)
+	}
+	idOf := func(row *ent.MfaQr) binid.BinId {
+		return row.ID
+	}
+	process := func(row *ent.MfaQr) (bool, error) {
+		return reencryptRow(c, client, encryptor, row)
+	}
+
+	return walk(c, client, opts, fetch, fetchIds, idOf, process)
+}
@@ -0,0 +1,226 @@
+package reencrypt
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/enttest"
+	"nidan-kai/keystore"
+	"nidan-kai/keystore/envkey"
+	"nidan-kai/secret"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var testKEY = "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT="
+var testPrevKEY = "UUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUU="
+var envKey = "ENV_SECRET_KEY"
+var prevEnvKey = "ENV_SECRET_KEY_PREVIOUS"
+
+func setUpRows(t *testing.T, client *ent.Client, n int) []binid.BinId {
+	c := context.Background()
+	userId, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.User.Create().
+		SetID(userId).
+		SetName("test").
+		SetEmail("test@example.com").
+		Exec(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]binid.BinId, n)
+	for i := range ids {
+		id, err := binid.NewSequential()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// every other row is encrypted before binding
+		bound := i%2 == 0
+		var ad []byte
+		if bound {
+			ad = secret.MfaQrContext(id, userId)
+		}
+		sec, err := secret.GenerateEncryptedSecret(envkey.EnvKey{}, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = client.MfaQr.Create().
+			SetID(id).
+			SetUserID(userId).
+			SetSecret(sec).
+			SetSecretBound(bound).
+			Exec(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+
+	return ids
+}
+
+func testOptions() Options {
+	opts := DefaultOptions()
+	opts.BatchSize = 3
+	opts.Logger = log.New(io.Discard, "", 0)
+	return opts
+}
+
+func Test_Run(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:reencrypt?mode=memory&_fk=1")
+	defer client.Close()
+	c := context.Background()
+
+	t.Setenv(envKey, testPrevKEY)
+	setUpRows(t, client, 7)
+
+	// rotate
+	t.Setenv(envKey, testKEY)
+	t.Setenv(prevEnvKey, testPrevKEY)
+	store := envkey.EnvKey{}
+
+	stats, err := Run(c, client, secret.KeystoreEncryptor{Keystore: store}, testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Scanned != 7 || stats.Reencrypted != 7 || stats.Failed != 0 {
+		t.Fatalf("unexpected stats %+v\n", stats)
+	}
+
+	key, err := store.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := client.MfaQr.Query().AllX(c)
+	for _, row := range rows {
+		id, ok := secret.KeyIdOf(row.Secret)
+		if !ok || id != keystore.NewKeyId(key) {
+			t.Fatal("should be encrypted with the primary key")
+		}
+		if !row.SecretBound {
+			t.Fatal("should be bound")
+		}
+	}
+
+	// previous key is not needed anymore
+	t.Setenv(prevEnvKey, "")
+	for _, row := range rows {
+		_, err := secret.Decrypt(row.Secret, store, secret.MfaQrContext(row.ID, row.UserID))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("should skip rows already re-encrypted", func(t *testing.T) {
+		stats, err := Run(c, client, secret.KeystoreEncryptor{Keystore: store}, testOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Scanned != 7 || stats.Skipped != 7 {
+			t.Fatalf("unexpected stats %+v\n", stats)
+		}
+	})
+}
+
+func Test_RunResume(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:resume?mode=memory&_fk=1")
+	defer client.Close()
+
+	t.Setenv(envKey, testPrevKEY)
+	ids := setUpRows(t, client, 5)
+
+	t.Setenv(envKey, testKEY)
+	t.Setenv(prevEnvKey, testPrevKEY)
+	store := envkey.EnvKey{}
+
+	// cancel after the first batch
+	c, cancel := context.WithCancel(context.Background())
+	opts := testOptions()
+	opts.Logger = log.New(cancelWriter(cancel), "", 0)
+	_, err := Run(c, client, secret.KeystoreEncryptor{Keystore: store}, opts)
+	if err == nil {
+		t.Fatal("should be canceled")
+	}
+
+	cp := client.JobCheckpoint.Query().OnlyX(context.Background())
+	if cp.Cursor == nil || *cp.Cursor != ids[2] || cp.CompletedAt != nil {
+		t.Fatal("checkpoint should be at the end of the first batch")
+	}
+
+	stats, err := Run(context.Background(), client, secret.KeystoreEncryptor{Keystore: store}, testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Scanned != 2 || stats.Reencrypted != 2 {
+		t.Fatalf("should resume from the checkpoint but %+v\n", stats)
+	}
+}
+
+type cancelWriter context.CancelFunc
+
+func (w cancelWriter) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte("processed")) {
+		w()
+	}
+	return len(p), nil
+}
+
+func Test_RunFailed(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:failed?mode=memory&_fk=1")
+	defer client.Close()
+	c := context.Background()
+
+	t.Setenv(envKey, testPrevKEY)
+	ids := setUpRows(t, client, 5)
+	valid := client.MfaQr.GetX(c, ids[1]).Secret
+	client.MfaQr.UpdateOneID(ids[1]).
+		SetSecret(bytes.Repeat([]byte{1}, len(valid))).
+		ExecX(c)
+
+	t.Setenv(envKey, testKEY)
+	t.Setenv(prevEnvKey, testPrevKEY)
+	encryptor := secret.KeystoreEncryptor{Keystore: envkey.EnvKey{}}
+
+	stats, err := Run(c, client, encryptor, testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Reencrypted != 4 || stats.Failed != 1 {
+		t.Fatalf("unexpected stats %+v\n", stats)
+	}
+
+	cp := client.JobCheckpoint.Query().OnlyX(c)
+	if cp.CompletedAt != nil {
+		t.Fatal("should not be completed with failed rows")
+	}
+	if len(cp.FailedIds) != 1 || cp.FailedIds[0] != ids[1].String() {
+		t.Fatal("should record failed rows")
+	}
+
+	t.Run("should retry only failed rows", func(t *testing.T) {
+		client.MfaQr.UpdateOneID(ids[1]).SetSecret(valid).ExecX(c)
+
+		stats, err := Run(c, client, encryptor, testOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Scanned != 1 || stats.Reencrypted != 1 || stats.Failed != 0 {
+			t.Fatalf("unexpected stats %+v\n", stats)
+		}
+
+		cp := client.JobCheckpoint.Query().OnlyX(c)
+		if cp.CompletedAt == nil || len(cp.FailedIds) != 0 {
+			t.Fatal("should be completed")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package reencrypt
+
+import (
+	"context"
+	"nidan-kai/binid"
+	"nidan-kai/ent"
+	"nidan-kai/ent/datakey"
+	"nidan-kai/secret"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+func DefaultRewrapOptions() Options {
+	opts := DefaultOptions()
+	opts.Name = DEFAULT_REWRAP_JOB_NAME
+	return opts
+}
+
+// re-wraps a data key under the current key, returns false when skipped
+func rewrapRow(
+	c context.Context,
+	client *ent.Client,
+	encryptor secret.Encryptor,
+	row *ent.DataKey,
+) (bool, error) {
+	current, err := encryptor.IsCurrent(row.WrappedKey)
+	if err != nil {
+		return false, err
+	}
+	if current {
+		return false, nil
+	}
+
+	ad := secret.DataKeyContext(row.ID, row.UserID)
+	dek, err := encryptor.UnwrapKey(row.WrappedKey, ad)
+	if err != nil {
+		return false, err
+	}
+
+	wrapped, err := encryptor.WrapKey(dek, ad)
+	clear(dek)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := client.DataKey.Update().
+		Where(
+			datakey.ID(row.ID),
+			datakey.WrappedKeyEQ(row.WrappedKey),
+		).
+		SetWrappedKey(wrapped).
+		Save(c)
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// walks all data_keys rows in batches and re-wraps data keys under
+// the current key, secrets sealed with them are left untouched
+func Rewrap(
+	c context.Context,
+	client *ent.Client,
+	encryptor secret.Encryptor,
+	opts Options,
+) (Stats, error) {
+	fetch := func(after *binid.BinId) ([]*ent.DataKey, error) {
+		query := client.DataKey.Query().
+			Order(sql.OrderByField(datakey.FieldID).ToFunc()).
+			Limit(opts.BatchSize)
+		if after != nil {
+			query = query.Where(datakey.IDGT(*after))
+		}
+
+		return query.All(c)
+	}
+	fetchIds := func(ids []binid.BinId) ([]*ent.DataKey, error) {
+		return client.DataKey.Query().
+			Where(datakey.IDIn(ids...)).
+			Order(sql.OrderByField(datakey.FieldID).ToFunc()).
+			All(c)
+	}
+	idOf := func(row *ent.DataKey) binid.BinId {
+		return row.ID
+	}
+	process := func(row *ent.DataKey) (bool, error) {
+		return rewrapRow(c, client, encryptor, row)
+	}
+
+	return walk(c, client, opts, fetch, fetchIds, idOf, process)
+}
@@ -0,0 +1,121 @@
+package reencrypt
+
+import (
+	"bytes"
+	"context"
+	"nidan-kai/binid"
+	"nidan-kai/ent/enttest"
+	"nidan-kai/keystore/envkey"
+	"nidan-kai/secret"
+	"testing"
+)
+
+func Test_Rewrap(t *testing.T) {
+	client := enttest.Open(t, "sqlite3", "file:rewrap?mode=memory&_fk=1")
+	defer client.Close()
+	c := context.Background()
+
+	t.Setenv(envKey, testPrevKEY)
+	encryptor := secret.KeystoreEncryptor{Keystore: envkey.EnvKey{}}
+
+	userId, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.User.Create().
+		SetID(userId).
+		SetName("test").
+		SetEmail("test@example.com").
+		ExecX(c)
+
+	dataKeyId, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dkAd := secret.DataKeyContext(dataKeyId, userId)
+	dek, wrapped, err := secret.GenerateDataKey(encryptor, dkAd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.DataKey.Create().
+		SetID(dataKeyId).
+		SetUserID(userId).
+		SetWrappedKey(wrapped).
+		ExecX(c)
+
+	secId, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec, err := secret.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := secret.Seal(sec, dek, secret.MfaQrContext(secId, userId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.MfaQr.Create().
+		SetID(secId).
+		SetUserID(userId).
+		SetSecret(sealed).
+		SetSecretBound(true).
+		SetDataKeyID(dataKeyId).
+		ExecX(c)
+
+	// rotate
+	t.Setenv(envKey, testKEY)
+	t.Setenv(prevEnvKey, testPrevKEY)
+
+	stats, err := Run(c, client, encryptor, testOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Skipped != 1 || stats.Reencrypted != 0 {
+		t.Fatalf("sealed secrets should be skipped but %+v\n", stats)
+	}
+
+	opts := testOptions()
+	opts.Name = DEFAULT_REWRAP_JOB_NAME
+	stats, err = Rewrap(c, client, encryptor, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Scanned != 1 || stats.Reencrypted != 1 {
+		t.Fatalf("unexpected stats %+v\n", stats)
+	}
+
+	// previous key is not needed anymore
+	t.Setenv(prevEnvKey, "")
+	dk := client.DataKey.GetX(c, dataKeyId)
+	rewrapped, err := encryptor.UnwrapKey(dk.WrappedKey, dkAd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dek, rewrapped) {
+		t.Fatal("data key should be kept")
+	}
+
+	// secret is untouched
+	row := client.MfaQr.GetX(c, secId)
+	if !bytes.Equal(row.Secret, sealed) {
+		t.Fatal("secret should not be rewritten")
+	}
+	dec, err := secret.Open(row.Secret, rewrapped, secret.MfaQrContext(secId, userId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sec, dec) {
+		t.Fatal("decrypted secret differs")
+	}
+
+	t.Run("should skip data keys already re-wrapped", func(t *testing.T) {
+		stats, err := Rewrap(c, client, encryptor, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Scanned != 1 || stats.Skipped != 1 {
+			t.Fatalf("unexpected stats %+v\n", stats)
+		}
+	})
+}
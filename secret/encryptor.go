@@ -0,0 +1,58 @@
+package secret
+
+import (
+	"nidan-kai/keystore"
+)
+
+// backend encrypting and decrypting secrets,
+// either locally with keystore or delegated to external services
+type Encryptor interface {
+	Encrypt(sec, ad []byte) ([]byte, error)
+	Decrypt(enc, ad []byte) ([]byte, error)
+	// for data keys of envelope encryption
+	WrapKey(dek, ad []byte) ([]byte, error)
+	UnwrapKey(wrapped, ad []byte) ([]byte, error)
+	// reports whether enc or wrapped is encrypted under the current key,
+	// the rest are re-encrypted on rotation
+	IsCurrent(enc []byte) (bool, error)
+}
+
+// encrypts locally with keys from the keystore
+type KeystoreEncryptor struct {
+	Keystore keystore.Keystore
+}
+
+func (e KeystoreEncryptor) Encrypt(sec, ad []byte) ([]byte, error) {
+	return Encrypt(sec, e.Keystore, ad)
+}
+
+func (e KeystoreEncryptor) Decrypt(enc, ad []byte) ([]byte, error) {
+	return Decrypt(enc, e.Keystore, ad)
+}
+
+func (e KeystoreEncryptor) WrapKey(dek, ad []byte) ([]byte, error) {
+	return WrapKey(dek, e.Keystore, ad)
+}
+
+func (e KeystoreEncryptor) UnwrapKey(wrapped, ad []byte) ([]byte, error) {
+	return UnwrapKey(wrapped, e.Keystore, ad)
+}
+
+func (e KeystoreEncryptor) IsCurrent(enc []byte) (bool, error) {
+	key, err := e.Keystore.GetKey()
+	if err != nil {
+		return false, err
+	}
+
+	id, ok := KeyIdOf(enc)
+	return ok && id == keystore.NewKeyId(key), nil
+}
+
+func GenerateEncryptedSecretWith(encryptor Encryptor, ad []byte) ([]byte, error) {
+	sec, err := GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptor.Encrypt(sec, ad)
+}
@@ -0,0 +1,62 @@
+package secret
+
+import (
+	"nidan-kai/keystore/envkey"
+	"testing"
+)
+
+var _ Encryptor = KeystoreEncryptor{}
+
+func TestKeystoreEncryptor(t *testing.T) {
+	t.Setenv(envKey, testPrevKEY)
+	e := KeystoreEncryptor{envkey.EnvKey{}}
+	ad := []byte("ad")
+
+	enc, err := GenerateEncryptedSecretWith(e, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dec, err := e.Decrypt(enc, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dec) != SECRET_LEN {
+		t.Fatal("wrong decrypted")
+	}
+
+	ok, err := e.IsCurrent(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("should be current")
+	}
+
+	t.Setenv(envKey, testKEY)
+	t.Setenv(prevEnvKey, testPrevKEY)
+
+	ok, err = e.IsCurrent(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("should not be current after rotation")
+	}
+
+	rotated, err := e.Decrypt(enc, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reenc, err := e.Encrypt(rotated, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err = e.IsCurrent(reenc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("should be current after re-encryption")
+	}
+}
@@ -0,0 +1,121 @@
+package secret
+
+import (
+	"crypto/rand"
+	"errors"
+	"nidan-kai/binid"
+	"nidan-kai/keystore"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// secrets are sealed with a data key per user,
+// and only data keys are encrypted with the master key,
+// so rotating the master key re-wraps data keys only
+
+const DATA_KEY_LEN = chacha20poly1305.KeySize
+
+const DATA_KEY_CONTEXT_LABEL = "nidan-kai/data_keys"
+
+const FORMAT_SEALED = 0x02
+const WRAPPED_KEY_LEN = HEADER_LEN + NONCE_LEN + DATA_KEY_LEN + chacha20poly1305.Overhead // 77
+const SEALED_LEN = 1 + LEGACY_LEN                                                         // 61
+
+// associated data binding the data key to the data_keys row it's stored in
+func DataKeyContext(id, userId binid.BinId) []byte {
+	ad := make([]byte, 0, len(DATA_KEY_CONTEXT_LABEL)+len(id)+len(userId))
+	ad = append(ad, DATA_KEY_CONTEXT_LABEL...)
+	ad = append(ad, id[:]...)
+	ad = append(ad, userId[:]...)
+	return ad
+}
+
+// encrypts the data key with the primary key of the keystore
+func WrapKey(dek []byte, store keystore.Keystore, ad []byte) ([]byte, error) {
+	if len(dek) != DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return encrypt(dek, store, ad)
+}
+
+func UnwrapKey(wrapped []byte, store keystore.Keystore, ad []byte) ([]byte, error) {
+	// data keys always have header
+	if _, ok := KeyIdOf(wrapped); !ok || len(wrapped) != WRAPPED_KEY_LEN {
+		return nil, errors.New("unexpected wrapped data key")
+	}
+
+	dek, err := decrypt(wrapped, store, ad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dek) != DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return dek, nil
+}
+
+// returns the data key in plain to seal secrets with,
+// and wrapped one to store
+func GenerateDataKey(encryptor Encryptor, ad []byte) ([]byte, []byte, error) {
+	dek := make([]byte, DATA_KEY_LEN)
+	_, err := rand.Read(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wrapped, err := encryptor.WrapKey(dek, ad)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dek, wrapped, nil
+}
+
+func IsSealed(enc []byte) bool {
+	return len(enc) == SEALED_LEN && enc[0] == FORMAT_SEALED
+}
+
+// version(1) || nonce(24) || ciphertext
+func Seal(sec, dek, ad []byte) ([]byte, error) {
+	if len(sec) != SECRET_LEN {
+		return nil, errors.New("unexpected secret size")
+	}
+
+	chacha, err := chacha20poly1305.NewX(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher := make([]byte, 1+NONCE_LEN, SEALED_LEN)
+	cipher[0] = FORMAT_SEALED
+	_, err = rand.Read(cipher[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return chacha.Seal(cipher, cipher[1:], sec, HeaderAd(cipher[:1], ad)), nil
+}
+
+// ad has to be the same as the one on sealing
+func Open(enc, dek, ad []byte) ([]byte, error) {
+	if !IsSealed(enc) {
+		return nil, errors.New("unexpected sealed secret")
+	}
+
+	chacha, err := chacha20poly1305.NewX(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := chacha.Open(nil, enc[1:1+NONCE_LEN], enc[1+NONCE_LEN:], HeaderAd(enc[:1], ad))
+	if err != nil {
+		return nil, err
+	}
+	if len(dec) != SECRET_LEN {
+		return nil, errors.New("unexpected decrypted secret size")
+	}
+
+	return dec, nil
+}
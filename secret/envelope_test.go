@@ -0,0 +1,114 @@
+package secret
+
+import (
+	"bytes"
+	"nidan-kai/binid"
+	"nidan-kai/keystore/envkey"
+	"testing"
+)
+
+func TestEnvelope(t *testing.T) {
+	t.Setenv(envKey, testKEY)
+	e := KeystoreEncryptor{envkey.EnvKey{}}
+
+	id, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userId, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dkAd := DataKeyContext(id, userId)
+
+	dek, wrapped, err := GenerateDataKey(e, dkAd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wrapped) != WRAPPED_KEY_LEN {
+		t.Fatal("unexpected wrapped data key size")
+	}
+	if ok, err := e.IsCurrent(wrapped); err != nil || !ok {
+		t.Fatal("wrapped data key should be current")
+	}
+
+	unwrapped, err := e.UnwrapKey(wrapped, dkAd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dek, unwrapped) {
+		t.Fatal("unwrapped data key differs")
+	}
+
+	sec, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ad := MfaQrContext(id, userId)
+	sealed, err := Seal(sec, dek, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) {
+		t.Fatal("should be sealed")
+	}
+	if _, ok := KeyIdOf(sealed); ok {
+		t.Fatal("sealed secret has no key id")
+	}
+
+	dec, err := Open(sealed, dek, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sec, dec) {
+		t.Fatal("opened secret differs")
+	}
+
+	t.Run("should fail with other context", func(t *testing.T) {
+		if _, err := Open(sealed, dek, MfaQrContext(userId, id)); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+		if _, err := e.UnwrapKey(wrapped, DataKeyContext(userId, id)); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+
+	t.Run("should fail with other data key", func(t *testing.T) {
+		other, _, err := GenerateDataKey(e, dkAd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(sealed, other, ad); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+
+	t.Run("should not unwrap secrets as data keys", func(t *testing.T) {
+		enc, err := e.Encrypt(sec, dkAd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.UnwrapKey(enc, dkAd); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+		if _, err := e.WrapKey(sec, dkAd); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+
+	t.Run("should unwrap with previous key after rotation", func(t *testing.T) {
+		t.Setenv(envKey, testPrevKEY)
+		t.Setenv(prevEnvKey, testKEY)
+
+		if ok, err := e.IsCurrent(wrapped); err != nil || ok {
+			t.Fatal("wrapped data key should not be current")
+		}
+		unwrapped, err := e.UnwrapKey(wrapped, dkAd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dek, unwrapped) {
+			t.Fatal("unwrapped data key differs")
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package pkcs11key
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"nidan-kai/keystore"
+	"nidan-kai/secret"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const FORMAT_PKCS11 = 0x03
+const HEADER_LEN = 1 + keystore.KEY_ID_SIZE
+const IV_LEN = 12
+const TAG_LEN = 16
+
+const KEY_ID_LABEL = "nidan-kai/pkcs11-key"
+
+// aes-gcm on the token
+type tokenCipher interface {
+	// returns iv actually used, tokens may replace the given one
+	seal(label string, iv, value, ad []byte) ([]byte, []byte, error)
+	open(label string, iv, cipher, ad []byte) ([]byte, error)
+	hasKey(label string) (bool, error)
+	generateKey(label string) error
+	close() error
+}
+
+// encrypts secrets and data keys with aes keys held in a pkcs#11 token,
+// keys never leave the token, reads "PKCS11_MODULE", "PKCS11_PIN",
+// "PKCS11_TOKEN_LABEL" or "PKCS11_SLOT", "PKCS11_KEY_LABEL"
+// and comma separated "PKCS11_KEY_LABEL_PREVIOUS" for rotation
+type Pkcs11 struct {
+	// primary first
+	labels []string
+	ids    []keystore.KeyId
+	token  tokenCipher
+}
+
+type config struct {
+	module     string
+	pin        string
+	tokenLabel string
+	slot       uint
+	hasSlot    bool
+	labels     []string
+}
+
+func getEnv() (config, error) {
+	// don't inject other than env
+	// to prevent exposing sensitive info
+	// just write within module for testing
+
+	cfg := config{
+		module:     os.Getenv("PKCS11_MODULE"),
+		pin:        os.Getenv("PKCS11_PIN"),
+		tokenLabel: os.Getenv("PKCS11_TOKEN_LABEL"),
+	}
+	if len(cfg.module) == 0 {
+		return config{}, errors.New("env for pkcs11 module is not set")
+	}
+	if len(cfg.pin) == 0 {
+		return config{}, errors.New("env for pkcs11 pin is not set")
+	}
+
+	if rawSlot := os.Getenv("PKCS11_SLOT"); len(rawSlot) > 0 {
+		slot, err := strconv.ParseUint(rawSlot, 10, 32)
+		if err != nil {
+			return config{}, errors.New("invalid pkcs11 slot")
+		}
+		cfg.slot = uint(slot)
+		cfg.hasSlot = true
+	}
+	if len(cfg.tokenLabel) == 0 && !cfg.hasSlot {
+		return config{}, errors.New("env for pkcs11 token label or slot is not set")
+	}
+
+	label := os.Getenv("PKCS11_KEY_LABEL")
+	if len(label) == 0 {
+		return config{}, errors.New("env for pkcs11 key label is not set")
+	}
+	cfg.labels = append(cfg.labels, label)
+	for _, prev := range strings.Split(os.Getenv("PKCS11_KEY_LABEL_PREVIOUS"), ",") {
+		prev = strings.TrimSpace(prev)
+		if len(prev) > 0 {
+			cfg.labels = append(cfg.labels, prev)
+		}
+	}
+
+	return cfg, nil
+}
+
+// opens a session on the token configured by env,
+// Close has to be called after use
+func New() (*Pkcs11, error) {
+	cfg, err := getEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := openToken(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPkcs11(token, cfg.labels), nil
+}
+
+func newPkcs11(token tokenCipher, labels []string) *Pkcs11 {
+	ids := make([]keystore.KeyId, len(labels))
+	for i, label := range labels {
+		ids[i] = keyIdOf(label)
+	}
+
+	return &Pkcs11{
+		labels: labels,
+		ids:    ids,
+		token:  token,
+	}
+}
+
+// keys on the token are identified by label
+func keyIdOf(label string) keystore.KeyId {
+	h := sha256.New()
+	h.Write([]byte(KEY_ID_LABEL))
+	h.Write([]byte(label))
+
+	id := keystore.KeyId{}
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+// checks every configured key exists, keys are not created here
+func (p *Pkcs11) Init() error {
+	for _, label := range p.labels {
+		ok, err := p.token.hasKey(label)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("could not find pkcs11 key " + label)
+		}
+	}
+
+	return nil
+}
+
+// creates the primary key on the token, fails if it already exists
+func (p *Pkcs11) Provision() error {
+	ok, err := p.token.hasKey(p.labels[0])
+	if err != nil {
+		return err
+	}
+	if ok {
+		return errors.New("pkcs11 key already exists")
+	}
+
+	return p.token.generateKey(p.labels[0])
+}
+
+func (p *Pkcs11) Close() error {
+	return p.token.close()
+}
+
+// version(1) || key id(4) || iv(12) || ciphertext
+func (p *Pkcs11) encrypt(value, ad []byte) ([]byte, error) {
+	header := make([]byte, HEADER_LEN)
+	header[0] = FORMAT_PKCS11
+	copy(header[1:], p.ids[0][:])
+
+	iv := make([]byte, IV_LEN)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	iv, ct, err := p.token.seal(p.labels[0], iv, value, secret.HeaderAd(header, ad))
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != IV_LEN {
+		return nil, errors.New("unexpected iv size")
+	}
+
+	enc := make([]byte, 0, HEADER_LEN+IV_LEN+len(ct))
+	enc = append(enc, header...)
+	enc = append(enc, iv...)
+	return append(enc, ct...), nil
+}
+
+func (p *Pkcs11) decrypt(enc, ad []byte) ([]byte, error) {
+	if len(enc) < HEADER_LEN+IV_LEN+TAG_LEN || enc[0] != FORMAT_PKCS11 {
+		return nil, errors.New("not a pkcs11 ciphertext")
+	}
+
+	id := keystore.KeyId{}
+	copy(id[:], enc[1:HEADER_LEN])
+	for i, label := range p.labels {
+		if p.ids[i] != id {
+			continue
+		}
+
+		return p.token.open(
+			label,
+			enc[HEADER_LEN:HEADER_LEN+IV_LEN],
+			enc[HEADER_LEN+IV_LEN:],
+			secret.HeaderAd(enc[:HEADER_LEN], ad),
+		)
+	}
+
+	return nil, errors.New("could not find pkcs11 key for " + id.String())
+}
+
+func (p *Pkcs11) Encrypt(sec, ad []byte) ([]byte, error) {
+	if len(sec) != secret.SECRET_LEN {
+		return nil, errors.New("unexpected secret size")
+	}
+
+	return p.encrypt(sec, ad)
+}
+
+func (p *Pkcs11) Decrypt(enc, ad []byte) ([]byte, error) {
+	dec, err := p.decrypt(enc, ad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dec) != secret.SECRET_LEN {
+		return nil, errors.New("unexpected decrypted secret size")
+	}
+
+	return dec, nil
+}
+
+func (p *Pkcs11) WrapKey(dek, ad []byte) ([]byte, error) {
+	if len(dek) != secret.DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return p.encrypt(dek, ad)
+}
+
+func (p *Pkcs11) UnwrapKey(wrapped, ad []byte) ([]byte, error) {
+	dek, err := p.decrypt(wrapped, ad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dek) != secret.DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return dek, nil
+}
+
+func (p *Pkcs11) IsCurrent(enc []byte) (bool, error) {
+	if len(enc) < HEADER_LEN || enc[0] != FORMAT_PKCS11 {
+		return false, errors.New("not a pkcs11 ciphertext")
+	}
+
+	id := keystore.KeyId{}
+	copy(id[:], enc[1:HEADER_LEN])
+	return id == p.ids[0], nil
+}
@@ -0,0 +1,195 @@
+package pkcs11key
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"nidan-kai/secret"
+	"testing"
+)
+
+var _ secret.Encryptor = &Pkcs11{}
+
+// software stand-in for the token
+type fakeToken struct {
+	keys map[string][]byte
+}
+
+func (f *fakeToken) gcm(label string) (cipher.AEAD, error) {
+	key, ok := f.keys[label]
+	if !ok {
+		return nil, errors.New("no key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (f *fakeToken) seal(label string, iv, value, ad []byte) ([]byte, []byte, error) {
+	gcm, err := f.gcm(label)
+	if err != nil {
+		return nil, nil, err
+	}
+	return iv, gcm.Seal(nil, iv, value, ad), nil
+}
+
+func (f *fakeToken) open(label string, iv, ct, ad []byte) ([]byte, error) {
+	gcm, err := f.gcm(label)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, iv, ct, ad)
+}
+
+func (f *fakeToken) hasKey(label string) (bool, error) {
+	_, ok := f.keys[label]
+	return ok, nil
+}
+
+func (f *fakeToken) generateKey(label string) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return err
+	}
+	f.keys[label] = key
+	return nil
+}
+
+func (f *fakeToken) close() error {
+	return nil
+}
+
+func TestPkcs11(t *testing.T) {
+	token := &fakeToken{keys: map[string][]byte{}}
+	p := newPkcs11(token, []string{"v1"})
+
+	if err := p.Init(); err == nil {
+		t.Fatal("should fail without key on the token")
+	}
+	if err := p.Provision(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Provision(); err == nil {
+		t.Fatal("should not overwrite existing key")
+	}
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	sec := []byte("12345678901234567890")
+	ad := []byte("ad")
+	enc, err := p.Encrypt(sec, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enc) != HEADER_LEN+IV_LEN+secret.SECRET_LEN+TAG_LEN {
+		t.Fatal("unexpected encrypted size")
+	}
+
+	dec, err := p.Decrypt(enc, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sec, dec) {
+		t.Fatal("decrypted secret differs")
+	}
+
+	t.Run("should fail with other associated data", func(t *testing.T) {
+		if _, err := p.Decrypt(enc, []byte("other")); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+
+	t.Run("should fail with tampered header", func(t *testing.T) {
+		tampered := bytes.Clone(enc)
+		tampered[1] ^= 1
+		if _, err := p.Decrypt(tampered, ad); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+
+	t.Run("should wrap data keys", func(t *testing.T) {
+		dek := make([]byte, secret.DATA_KEY_LEN)
+		rand.Read(dek)
+		wrapped, err := p.WrapKey(dek, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unwrapped, err := p.UnwrapKey(wrapped, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dek, unwrapped) {
+			t.Fatal("unwrapped data key differs")
+		}
+		if _, err := p.Decrypt(wrapped, ad); err == nil {
+			t.Fatal("should not decrypt data keys as secrets")
+		}
+	})
+
+	t.Run("should decrypt with previous key after rotation", func(t *testing.T) {
+		if err := token.generateKey("v2"); err != nil {
+			t.Fatal(err)
+		}
+		rotated := newPkcs11(token, []string{"v2", "v1"})
+		if err := rotated.Init(); err != nil {
+			t.Fatal(err)
+		}
+
+		current, err := rotated.IsCurrent(enc)
+		if err != nil || current {
+			t.Fatal("should not be current after rotation")
+		}
+		dec, err := rotated.Decrypt(enc, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reenc, err := rotated.Encrypt(dec, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		current, err = rotated.IsCurrent(reenc)
+		if err != nil || !current {
+			t.Fatal("should be current after re-encryption")
+		}
+
+		// previous key is dropped
+		if _, err := newPkcs11(token, []string{"v2"}).Decrypt(enc, ad); err == nil {
+			t.Fatal("should fail without previous key")
+		}
+	})
+}
+
+func TestPkcs11_Env(t *testing.T) {
+	t.Setenv("PKCS11_MODULE", "/usr/lib/softhsm/libsofthsm2.so")
+	t.Setenv("PKCS11_PIN", "1234")
+	t.Setenv("PKCS11_TOKEN_LABEL", "")
+	t.Setenv("PKCS11_SLOT", "")
+	t.Setenv("PKCS11_KEY_LABEL", "v2")
+	t.Setenv("PKCS11_KEY_LABEL_PREVIOUS", "v1, v0")
+
+	if _, err := getEnv(); err == nil {
+		t.Fatal("should fail without token")
+	}
+
+	t.Setenv("PKCS11_SLOT", "x")
+	if _, err := getEnv(); err == nil {
+		t.Fatal("should fail with invalid slot")
+	}
+
+	t.Setenv("PKCS11_SLOT", "3")
+	cfg, err := getEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.hasSlot || cfg.slot != 3 {
+		t.Fatal("slot is not read")
+	}
+	if len(cfg.labels) != 3 || cfg.labels[0] != "v2" || cfg.labels[2] != "v0" {
+		t.Fatalf("unexpected labels %v\n", cfg.labels)
+	}
+}
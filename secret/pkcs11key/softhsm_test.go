@@ -0,0 +1,149 @@
+//go:build cgo
+
+package pkcs11key
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+)
+
+var softHsmPaths = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/lib/aarch64-linux-gnu/softhsm/libsofthsm2.so",
+	"/usr/local/lib/softhsm/libsofthsm2.so",
+}
+
+var testTokenLabel = "nidan-kai-test"
+var testPin = "1234"
+var testSoPin = "5678"
+
+// "SOFTHSM2_MODULE" overrides the library path,
+// skipped when softhsm2 is not installed
+func findSoftHsm(t *testing.T) string {
+	if module := os.Getenv("SOFTHSM2_MODULE"); len(module) > 0 {
+		return module
+	}
+	for _, path := range softHsmPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	t.Skip("softhsm2 is not installed")
+	return ""
+}
+
+// initializes a token in a temporary directory
+func setUpSoftHsm(t *testing.T) string {
+	module := findSoftHsm(t)
+
+	dir := t.TempDir()
+	tokens := filepath.Join(dir, "tokens")
+	if err := os.Mkdir(tokens, 0700); err != nil {
+		t.Fatal(err)
+	}
+	conf := filepath.Join(dir, "softhsm2.conf")
+	err := os.WriteFile(conf, []byte("directories.tokendir = "+tokens+"\nobjectstore.backend = file\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		t.Fatal("could not load softhsm2")
+	}
+	defer ctx.Destroy()
+	if err := ctx.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Finalize()
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil || len(slots) == 0 {
+		t.Fatal("could not find free slot")
+	}
+	if err := ctx.InitToken(slots[0], testSoPin, testTokenLabel); err != nil {
+		t.Fatal(err)
+	}
+
+	// slots are renumbered after initialization
+	slot, err := findSlot(ctx, config{tokenLabel: testTokenLabel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.CloseSession(session)
+	if err := ctx.Login(session, pkcs11.CKU_SO, testSoPin); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Logout(session)
+	if err := ctx.InitPIN(session, testPin); err != nil {
+		t.Fatal(err)
+	}
+
+	return module
+}
+
+func TestPkcs11_SoftHsm(t *testing.T) {
+	module := setUpSoftHsm(t)
+	t.Setenv("PKCS11_MODULE", module)
+	t.Setenv("PKCS11_PIN", testPin)
+	t.Setenv("PKCS11_TOKEN_LABEL", testTokenLabel)
+	t.Setenv("PKCS11_SLOT", "")
+	t.Setenv("PKCS11_KEY_LABEL", "v1")
+	t.Setenv("PKCS11_KEY_LABEL_PREVIOUS", "")
+
+	p, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Init(); err == nil {
+		t.Fatal("should fail without key on the token")
+	}
+	if err := p.Provision(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	sec := []byte("12345678901234567890")
+	ad := []byte("ad")
+	enc, err := p.Encrypt(sec, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// key is persisted on the token
+	p, err = New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if err := p.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	dec, err := p.Decrypt(enc, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sec, dec) {
+		t.Fatal("decrypted secret differs")
+	}
+	if _, err := p.Decrypt(enc, []byte("other")); err == nil {
+		t.Fatal("should fail with other associated data")
+	}
+}
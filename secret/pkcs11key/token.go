@@ -0,0 +1,213 @@
+//go:build cgo
+
+package pkcs11key
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+)
+
+const TAG_BITS = TAG_LEN * 8
+
+type token struct {
+	// sessions are not safe for concurrent use
+	mu      sync.Mutex
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	handles map[string]pkcs11.ObjectHandle
+}
+
+func findSlot(ctx *pkcs11.Ctx, cfg config) (uint, error) {
+	if cfg.hasSlot {
+		return cfg.slot, nil
+	}
+
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, err
+		}
+		if info.Label == cfg.tokenLabel {
+			return slot, nil
+		}
+	}
+
+	return 0, errors.New("could not find pkcs11 token " + cfg.tokenLabel)
+}
+
+func openToken(cfg config) (tokenCipher, error) {
+	ctx := pkcs11.New(cfg.module)
+	if ctx == nil {
+		return nil, errors.New("could not load pkcs11 module")
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+
+	t := &token{
+		ctx:     ctx,
+		handles: map[string]pkcs11.ObjectHandle{},
+	}
+	slot, err := findSlot(ctx, cfg)
+	if err != nil {
+		t.finalize()
+		return nil, err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		t.finalize()
+		return nil, err
+	}
+	t.session = session
+
+	err = ctx.Login(session, pkcs11.CKU_USER, cfg.pin)
+	if err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		ctx.CloseSession(session)
+		t.finalize()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *token) finalize() {
+	t.ctx.Finalize()
+	t.ctx.Destroy()
+}
+
+func (t *token) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ctx.Logout(t.session)
+	err := t.ctx.CloseSession(t.session)
+	t.finalize()
+	return err
+}
+
+// caller holds the lock
+func (t *token) find(label string) (pkcs11.ObjectHandle, bool, error) {
+	if h, ok := t.handles[label]; ok {
+		return h, true, nil
+	}
+
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := t.ctx.FindObjectsInit(t.session, template); err != nil {
+		return 0, false, err
+	}
+	found, _, err := t.ctx.FindObjects(t.session, 2)
+	if ferr := t.ctx.FindObjectsFinal(t.session); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch len(found) {
+	case 0:
+		return 0, false, nil
+	case 1:
+		t.handles[label] = found[0]
+		return found[0], true, nil
+	default:
+		return 0, false, errors.New("found multiple pkcs11 keys labeled " + label)
+	}
+}
+
+func (t *token) hasKey(label string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok, err := t.find(label)
+	return ok, err
+}
+
+// aes-256 key, not extractable from the token
+func (t *token) generateKey(label string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+	}
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)}
+
+	h, err := t.ctx.GenerateKey(t.session, mech, template)
+	if err != nil {
+		return err
+	}
+	t.handles[label] = h
+
+	return nil
+}
+
+func (t *token) seal(label string, iv, value, ad []byte) ([]byte, []byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok, err := t.find(label)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, errors.New("could not find pkcs11 key " + label)
+	}
+
+	params := pkcs11.NewGCMParams(iv, ad, TAG_BITS)
+	defer params.Free()
+
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+	if err := t.ctx.EncryptInit(t.session, mech, h); err != nil {
+		return nil, nil, err
+	}
+	ct, err := t.ctx.Encrypt(t.session, value)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return params.IV(), ct, nil
+}
+
+func (t *token) open(label string, iv, cipher, ad []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok, err := t.find(label)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("could not find pkcs11 key " + label)
+	}
+
+	params := pkcs11.NewGCMParams(iv, ad, TAG_BITS)
+	defer params.Free()
+
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}
+	if err := t.ctx.DecryptInit(t.session, mech, h); err != nil {
+		return nil, err
+	}
+
+	return t.ctx.Decrypt(t.session, cipher)
+}
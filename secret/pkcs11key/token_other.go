@@ -0,0 +1,10 @@
+//go:build !cgo
+
+package pkcs11key
+
+import "errors"
+
+// pkcs#11 modules are loaded through cgo
+func openToken(cfg config) (tokenCipher, error) {
+	return nil, errors.New("pkcs11 is only supported with cgo")
+}
@@ -0,0 +1,119 @@
+package secret
+
+import (
+	"errors"
+	"nidan-kai/keystore"
+	"time"
+)
+
+// keys are fetched from the keystore again after this,
+// to pick up rotation done without restarting
+const DEFAULT_SEALER_TTL = keystore.DEFAULT_CACHE_TTL
+
+// encryptor caching ciphers built from keystore keys,
+// so that the keystore is not asked on every call
+type Sealer struct {
+	ciphers *keystore.Cache[keyCipher]
+}
+
+func NewSealer(store keystore.Keystore, ttl time.Duration) *Sealer {
+	return &Sealer{
+		ciphers: keystore.NewCache(store, ttl, newKeyCipher),
+	}
+}
+
+// fetches keys from the keystore and replaces cached ciphers
+func (s *Sealer) Refresh() error {
+	return s.ciphers.Refresh()
+}
+
+// drops cached ciphers, they are loaded again on the next use
+func (s *Sealer) Purge() {
+	s.ciphers.Purge()
+}
+
+func (s *Sealer) encrypt(value, ad []byte) ([]byte, error) {
+	ciphers, err := s.ciphers.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	return sealV1(ciphers[0], value, ad)
+}
+
+func (s *Sealer) decrypt(enc, ad []byte) ([]byte, error) {
+	ciphers, err := s.ciphers.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := openAny(ciphers, enc, ad)
+	if !errors.Is(err, errUnknownKey) {
+		return dec, err
+	}
+
+	// encrypted by others with a key newer than the cached ones
+	ciphers, err = s.ciphers.Reload(func(ciphers []keyCipher) bool {
+		_, err := openAny(ciphers, enc, ad)
+		return errors.Is(err, errUnknownKey)
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAny(ciphers, enc, ad)
+}
+
+func (s *Sealer) Encrypt(sec, ad []byte) ([]byte, error) {
+	if len(sec) != SECRET_LEN {
+		return nil, errors.New("unexpected secret size")
+	}
+
+	return s.encrypt(sec, ad)
+}
+
+func (s *Sealer) Decrypt(enc, ad []byte) ([]byte, error) {
+	dec, err := s.decrypt(enc, ad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dec) != SECRET_LEN {
+		return nil, errors.New("unexpected decrypted secret size")
+	}
+
+	return dec, nil
+}
+
+func (s *Sealer) WrapKey(dek, ad []byte) ([]byte, error) {
+	if len(dek) != DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return s.encrypt(dek, ad)
+}
+
+func (s *Sealer) UnwrapKey(wrapped, ad []byte) ([]byte, error) {
+	if _, ok := KeyIdOf(wrapped); !ok || len(wrapped) != WRAPPED_KEY_LEN {
+		return nil, errors.New("unexpected wrapped data key")
+	}
+
+	dek, err := s.decrypt(wrapped, ad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dek) != DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return dek, nil
+}
+
+func (s *Sealer) IsCurrent(enc []byte) (bool, error) {
+	ciphers, err := s.ciphers.Get()
+	if err != nil {
+		return false, err
+	}
+
+	id, ok := KeyIdOf(enc)
+	return ok && id == ciphers[0].id, nil
+}
@@ -0,0 +1,311 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"nidan-kai/keystore"
+	"nidan-kai/keystore/envkey"
+	"sync"
+	"testing"
+	"time"
+)
+
+var _ Encryptor = &Sealer{}
+
+// counts how many times keys are fetched
+type countingKeystore struct {
+	keystore.MultiKeystore
+	count int
+}
+
+func (c *countingKeystore) GetKeys() ([][]byte, error) {
+	c.count++
+	return c.MultiKeystore.GetKeys()
+}
+
+func TestSealer(t *testing.T) {
+	t.Setenv(envKey, testPrevKEY)
+	store := &countingKeystore{MultiKeystore: envkey.EnvKey{}}
+	now := time.Unix(1111111109, 0)
+	s := NewSealer(store, time.Minute)
+	s.ciphers.Clock = func() time.Time { return now }
+
+	sec, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ad := []byte("ad")
+
+	enc, err := s.Encrypt(sec, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		dec, err := s.Decrypt(enc, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sec, dec) {
+			t.Fatal("decrypted secret differs")
+		}
+	}
+	if store.count != 1 {
+		t.Fatalf("keys should be cached but fetched %d times\n", store.count)
+	}
+
+	t.Run("should be compatible with keystore encryptor", func(t *testing.T) {
+		e := KeystoreEncryptor{envkey.EnvKey{}}
+		dec, err := e.Decrypt(enc, ad)
+		if err != nil || !bytes.Equal(sec, dec) {
+			t.Fatal("should decrypt secrets encrypted by sealer")
+		}
+
+		other, err := e.Encrypt(sec, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec, err = s.Decrypt(other, ad)
+		if err != nil || !bytes.Equal(sec, dec) {
+			t.Fatal("should decrypt secrets encrypted by keystore encryptor")
+		}
+	})
+
+	t.Run("should refresh on ttl", func(t *testing.T) {
+		count := store.count
+		now = now.Add(time.Minute)
+		if _, err := s.Decrypt(enc, ad); err != nil {
+			t.Fatal(err)
+		}
+		if store.count != count+1 {
+			t.Fatal("keys should be fetched again after ttl")
+		}
+	})
+
+	t.Run("should refresh on unknown key", func(t *testing.T) {
+		// rotated by another instance
+		t.Setenv(envKey, testKEY)
+		t.Setenv(prevEnvKey, testPrevKEY)
+		rotated, err := KeystoreEncryptor{envkey.EnvKey{}}.Encrypt(sec, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok, err := s.IsCurrent(enc); err != nil || !ok {
+			t.Fatal("cached key should be still current before refresh")
+		}
+
+		dec, err := s.Decrypt(rotated, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sec, dec) {
+			t.Fatal("decrypted secret differs")
+		}
+		if ok, err := s.IsCurrent(rotated); err != nil || !ok {
+			t.Fatal("should be current after refresh")
+		}
+		if ok, err := s.IsCurrent(enc); err != nil || ok {
+			t.Fatal("should not be current after refresh")
+		}
+	})
+
+	t.Run("should wrap data keys", func(t *testing.T) {
+		dek, wrapped, err := GenerateDataKey(s, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unwrapped, err := s.UnwrapKey(wrapped, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dek, unwrapped) {
+			t.Fatal("unwrapped data key differs")
+		}
+	})
+
+	t.Run("should zero keys from keystore", func(t *testing.T) {
+		key := bytes.Repeat([]byte{1}, keystore.KEY_SIZE)
+		keys := [][]byte{key}
+		s := NewSealer(fixedKeystore(keys), 0)
+		if err := s.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(key, make([]byte, keystore.KEY_SIZE)) {
+			t.Fatal("key should be zeroed")
+		}
+	})
+}
+
+// returns the same buffers
+type fixedKeystore [][]byte
+
+func (f fixedKeystore) Init() error {
+	return nil
+}
+
+func (f fixedKeystore) GetKey() ([]byte, error) {
+	return f[0], nil
+}
+
+func (f fixedKeystore) GetKeys() ([][]byte, error) {
+	return f, nil
+}
+
+func BenchmarkDecrypt(b *testing.B) {
+	b.Setenv(envKey, testKEY)
+	store := envkey.EnvKey{}
+	ad := []byte("ad")
+	enc, err := GenerateEncryptedSecret(store, ad)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := Decrypt(enc, store, ad); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSealer_Decrypt(b *testing.B) {
+	b.Setenv(envKey, testKEY)
+	s := NewSealer(envkey.EnvKey{}, DEFAULT_SEALER_TTL)
+	ad := []byte("ad")
+	enc, err := GenerateEncryptedSecretWith(s, ad)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for b.Loop() {
+		if _, err := s.Decrypt(enc, ad); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncrypt(b *testing.B) {
+	b.Setenv(envKey, testKEY)
+	store := envkey.EnvKey{}
+	sec := bytes.Repeat([]byte{1}, SECRET_LEN)
+	ad := []byte("ad")
+
+	for b.Loop() {
+		if _, err := Encrypt(sec, store, ad); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSealer_Encrypt(b *testing.B) {
+	b.Setenv(envKey, testKEY)
+	s := NewSealer(envkey.EnvKey{}, DEFAULT_SEALER_TTL)
+	sec := bytes.Repeat([]byte{1}, SECRET_LEN)
+	ad := []byte("ad")
+
+	for b.Loop() {
+		if _, err := s.Encrypt(sec, ad); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// fails while down, blocks while gate is set
+type unreliableKeystore struct {
+	keystore.MultiKeystore
+	mu    sync.Mutex
+	count int
+	down  bool
+	gate  chan struct{}
+}
+
+func (u *unreliableKeystore) GetKeys() ([][]byte, error) {
+	u.mu.Lock()
+	u.count++
+	down, gate := u.down, u.gate
+	u.mu.Unlock()
+
+	if gate != nil {
+		<-gate
+	}
+	if down {
+		return nil, errors.New("keystore is down")
+	}
+	return u.MultiKeystore.GetKeys()
+}
+
+func TestSealer_Refresh(t *testing.T) {
+	t.Setenv(envKey, testKEY)
+	store := &unreliableKeystore{MultiKeystore: envkey.EnvKey{}}
+	now := time.Unix(1111111109, 0)
+	s := NewSealer(store, time.Minute)
+	s.ciphers.Clock = func() time.Time { return now }
+
+	sec, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ad := []byte("ad")
+	enc, err := s.Encrypt(sec, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should refresh once for concurrent callers", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		store.count = 0
+		store.gate = make(chan struct{})
+
+		wg := sync.WaitGroup{}
+		for range 10 {
+			wg.Go(func() {
+				if _, err := s.Decrypt(enc, ad); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+		// callers other than the refreshing one return with stale ciphers
+		time.Sleep(10 * time.Millisecond)
+		close(store.gate)
+		wg.Wait()
+
+		store.gate = nil
+		if store.count != 1 {
+			t.Fatalf("keys should be fetched once but %d times\n", store.count)
+		}
+	})
+
+	t.Run("should fall back to stale ciphers while keystore is down", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		store.count = 0
+		store.down = true
+
+		for range 10 {
+			if _, err := s.Decrypt(enc, ad); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if store.count != 1 {
+			t.Fatalf("failed refresh should not be retried at once but %d times\n", store.count)
+		}
+
+		now = now.Add(keystore.REFRESH_RETRY_INTERVAL)
+		if _, err := s.Encrypt(sec, ad); err != nil {
+			t.Fatal(err)
+		}
+		if store.count != 2 {
+			t.Fatal("should retry after the interval")
+		}
+	})
+
+	t.Run("should fail without cached ciphers", func(t *testing.T) {
+		s.Purge()
+		if _, err := s.Decrypt(enc, ad); err == nil {
+			t.Fatal("should fail while keystore is down")
+		}
+
+		store.down = false
+		if _, err := s.Decrypt(enc, ad); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package secret
 
 import (
+	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base32"
 	"errors"
+	"nidan-kai/binid"
 	"nidan-kai/keystore"
 
 	"golang.org/x/crypto/chacha20poly1305"
@@ -11,58 +13,165 @@ import (
 
 const SECRET_LEN = 20 // (20 / 5 * 8 = 32)
 
+const MFA_QR_CONTEXT_LABEL = "nidan-kai/mfa_qrs"
+
+const FORMAT_V1 = 0x01
+const HEADER_LEN = 1 + keystore.KEY_ID_SIZE
+const NONCE_LEN = chacha20poly1305.NonceSizeX
+const LEGACY_LEN = NONCE_LEN + SECRET_LEN + chacha20poly1305.Overhead // 60
+const V1_LEN = HEADER_LEN + LEGACY_LEN                                // 65
+
 func SecretEncoder() *base32.Encoding {
 	return base32.StdEncoding.WithPadding(base32.NoPadding)
 }
 
-func GenerateEncryptedSecret(keystore keystore.Keystore) ([]byte, error) {
+// associated data binding the secret to the mfa_qrs row it's stored in,
+// so that ciphertext swapped into another row fails to decrypt
+func MfaQrContext(id, userId binid.BinId) []byte {
+	ad := make([]byte, 0, len(MFA_QR_CONTEXT_LABEL)+len(id)+len(userId))
+	ad = append(ad, MFA_QR_CONTEXT_LABEL...)
+	ad = append(ad, id[:]...)
+	ad = append(ad, userId[:]...)
+	return ad
+}
+
+// returns a random secret in plain
+func GenerateSecret() ([]byte, error) {
 	sec := make([]byte, SECRET_LEN)
 	_, err := rand.Read(sec)
 	if err != nil {
 		return nil, err
 	}
 
-	return encrypt(sec, keystore)
+	return sec, nil
 }
 
-func encrypt(value []byte, keystore keystore.Keystore) ([]byte, error) {
-	key, err := keystore.GetKey()
+func GenerateEncryptedSecret(store keystore.Keystore, ad []byte) ([]byte, error) {
+	sec, err := GenerateSecret()
 	if err != nil {
 		return nil, err
 	}
 
-	chacha, err := chacha20poly1305.NewX(key)
+	return encrypt(sec, store, ad)
+}
+
+// for secrets already generated, e.g. on re-encryption
+func Encrypt(sec []byte, store keystore.Keystore, ad []byte) ([]byte, error) {
+	if len(sec) != SECRET_LEN {
+		return nil, errors.New("unexpected secret size")
+	}
+
+	return encrypt(sec, store, ad)
+}
+
+// header is authenticated together with ad
+func HeaderAd(header, ad []byte) []byte {
+	b := make([]byte, 0, len(header)+len(ad))
+	b = append(b, header...)
+	return append(b, ad...)
+}
+
+// version(1) || key id(4) || nonce(24) || ciphertext
+func encrypt(value []byte, store keystore.Keystore, ad []byte) ([]byte, error) {
+	key, err := store.GetKey()
 	if err != nil {
 		return nil, err
 	}
 
-	nonceSize := chacha.NonceSize()
-	cipher := make([]byte, nonceSize, nonceSize+len(value)+chacha.Overhead())
-	_, err = rand.Read(cipher)
+	c, err := newKeyCipher(key)
 	if err != nil {
 		return nil, err
 	}
 
-	enc := chacha.Seal(cipher, cipher[:nonceSize], value, nil)
-	return enc, nil
+	return sealV1(c, value, ad)
 }
 
-func Decrypt(enc []byte, keystore keystore.Keystore) ([]byte, error) {
-	key, err := keystore.GetKey()
+func sealV1(c keyCipher, value, ad []byte) ([]byte, error) {
+	chacha, keyId := c.aead, c.id
+	nonceSize := chacha.NonceSize()
+	buf := make([]byte, HEADER_LEN+nonceSize, HEADER_LEN+nonceSize+len(value)+chacha.Overhead())
+	buf[0] = FORMAT_V1
+	copy(buf[1:HEADER_LEN], keyId[:])
+	_, err := rand.Read(buf[HEADER_LEN:])
 	if err != nil {
 		return nil, err
 	}
 
+	enc := chacha.Seal(
+		buf,
+		buf[HEADER_LEN:],
+		value,
+		HeaderAd(buf[:HEADER_LEN], ad),
+	)
+	return enc, nil
+}
+
+// returns id of the key the secret or data key is encrypted with,
+// false for secrets without header
+func KeyIdOf(enc []byte) (keystore.KeyId, bool) {
+	if (len(enc) != V1_LEN && len(enc) != WRAPPED_KEY_LEN) || enc[0] != FORMAT_V1 {
+		return keystore.KeyId{}, false
+	}
+
+	id := keystore.KeyId{}
+	copy(id[:], enc[1:HEADER_LEN])
+	return id, true
+}
+
+// key selectable by id on decryption
+type keyCipher struct {
+	id   keystore.KeyId
+	aead cipher.AEAD
+}
+
+func newKeyCipher(key []byte) (keyCipher, error) {
 	chacha, err := chacha20poly1305.NewX(key)
 	if err != nil {
-		return nil, err
+		return keyCipher{}, err
 	}
-	nonceSize := chacha.NonceSize()
-	if len(enc) <= nonceSize {
+
+	return keyCipher{
+		id:   keystore.NewKeyId(key),
+		aead: chacha,
+	}, nil
+}
+
+var errUnknownKey = errors.New("could not find the key secret is encrypted with")
+
+// selects the key by id, or tries all of them for secrets without header
+func openAny(ciphers []keyCipher, enc, ad []byte) ([]byte, error) {
+	if id, ok := KeyIdOf(enc); ok {
+		for _, c := range ciphers {
+			if c.id != id {
+				continue
+			}
+
+			nonce := enc[HEADER_LEN : HEADER_LEN+NONCE_LEN]
+			return c.aead.Open(nil, nonce, enc[HEADER_LEN+NONCE_LEN:], HeaderAd(enc[:HEADER_LEN], ad))
+		}
+
+		return nil, errUnknownKey
+	}
+
+	// nonce || ciphertext without header, encrypted before versioning,
+	// key is unknown so try all of them
+	if len(enc) != LEGACY_LEN {
 		return nil, errors.New("unexpected encryptedd secret size")
 	}
+	for _, c := range ciphers {
+		dec, err := c.aead.Open(nil, enc[:NONCE_LEN], enc[NONCE_LEN:], ad)
+		if err == nil {
+			return dec, nil
+		}
+	}
+
+	return nil, errors.New("could not decrypt secret with any key")
+}
 
-	dec, err := chacha.Open(nil, enc[:nonceSize], enc[nonceSize:], nil)
+// ad has to be the same as the one on encryption,
+// nil for secrets encrypted before binding
+func Decrypt(enc []byte, store keystore.Keystore, ad []byte) ([]byte, error) {
+	dec, err := decrypt(enc, store, ad)
 	if err != nil {
 		return nil, err
 	}
@@ -72,3 +181,21 @@ func Decrypt(enc []byte, keystore keystore.Keystore) ([]byte, error) {
 
 	return dec, nil
 }
+
+func decrypt(enc []byte, store keystore.Keystore, ad []byte) ([]byte, error) {
+	keys, err := keystore.GetKeys(store)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphers := make([]keyCipher, 0, len(keys))
+	for _, key := range keys {
+		c, err := newKeyCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		ciphers = append(ciphers, c)
+	}
+
+	return openAny(ciphers, enc, ad)
+}
@@ -2,9 +2,13 @@ package secret
 
 import (
 	"bytes"
+	"nidan-kai/binid"
+	"nidan-kai/keystore"
 	"nidan-kai/keystore/envkey"
 	"os"
 	"testing"
+
+	"golang.org/x/crypto/chacha20poly1305"
 )
 
 var testKEY = "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT="
@@ -14,19 +18,19 @@ var envKey = "ENV_SECRET_KEY"
 func Test_GenerateSecret(t *testing.T) {
 	e := envkey.EnvKey{}
 
-	b, err := GenerateEncryptedSecret(e)
+	b, err := GenerateEncryptedSecret(e, nil)
 	if err == nil {
 		t.Fatalf("env is not set but got %v\n", b)
 	}
 
 	t.Setenv(envKey, testKEY)
 
-	secret1, err := GenerateEncryptedSecret(envkey.EnvKey{})
+	secret1, err := GenerateEncryptedSecret(envkey.EnvKey{}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	secret2, err := GenerateEncryptedSecret(envkey.EnvKey{})
+	secret2, err := GenerateEncryptedSecret(envkey.EnvKey{}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -43,12 +47,12 @@ func Test_Decrypt(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	original, err := GenerateEncryptedSecret(e)
+	original, err := GenerateEncryptedSecret(e, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	dec, err := Decrypt(original, e)
+	dec, err := Decrypt(original, e, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -61,7 +65,7 @@ func Test_Decrypt(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	dec, err = Decrypt(original, e)
+	dec, err = Decrypt(original, e, nil)
 	if err == nil {
 		t.Fatal("env is not set but err is nil")
 	}
@@ -73,18 +77,216 @@ func Test_DecryptVars(t *testing.T) {
 
 	fail := []byte{7, 7, 7}
 
-	dec, err := Decrypt(fail, e)
+	dec, err := Decrypt(fail, e, nil)
 	if err == nil {
 		t.Fatalf("should fail, but returns %v\n", dec)
 	}
 
-	failEnc, err := encrypt(fail, e)
+	failEnc, err := encrypt(fail, e, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	dec, err = Decrypt(failEnc, e)
+	dec, err = Decrypt(failEnc, e, nil)
 	if err == nil {
 		t.Fatalf("should fail, but returns %v\n", dec)
 	}
 }
+
+func Test_DecryptContext(t *testing.T) {
+	e := envkey.EnvKey{}
+	t.Setenv(envKey, testKEY)
+
+	id, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userId, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherId, err := binid.NewSequential()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ad := MfaQrContext(id, userId)
+	enc, err := GenerateEncryptedSecret(e, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should success with the same context", func(t *testing.T) {
+		dec, err := Decrypt(enc, e, MfaQrContext(id, userId))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dec) != SECRET_LEN {
+			t.Fatal("wrong decrypted")
+		}
+	})
+
+	t.Run("should fail with another user", func(t *testing.T) {
+		dec, err := Decrypt(enc, e, MfaQrContext(id, otherId))
+		if err == nil {
+			t.Fatalf("should fail, but returns %v\n", dec)
+		}
+	})
+
+	t.Run("should fail with another row", func(t *testing.T) {
+		dec, err := Decrypt(enc, e, MfaQrContext(otherId, userId))
+		if err == nil {
+			t.Fatalf("should fail, but returns %v\n", dec)
+		}
+	})
+
+	t.Run("should fail without context", func(t *testing.T) {
+		dec, err := Decrypt(enc, e, nil)
+		if err == nil {
+			t.Fatalf("should fail, but returns %v\n", dec)
+		}
+	})
+	t.Run("should rebind legacy secret", func(t *testing.T) {
+		legacy, err := GenerateEncryptedSecret(e, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dec, err := Decrypt(legacy, e, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bound, err := Encrypt(dec, e, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rebound, err := Decrypt(bound, e, MfaQrContext(id, userId))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dec, rebound) {
+			t.Fatal("secret is changed")
+		}
+	})
+
+	t.Run("should fail to encrypt wrong size", func(t *testing.T) {
+		enc, err := Encrypt([]byte{7, 7, 7}, e, ad)
+		if err == nil {
+			t.Fatalf("should fail, but returns %v\n", enc)
+		}
+	})
+}
+
+var testPrevKEY = "UUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUUU="
+var prevEnvKey = "ENV_SECRET_KEY_PREVIOUS"
+
+func Test_DecryptRotated(t *testing.T) {
+	e := envkey.EnvKey{}
+	t.Setenv(envKey, testPrevKEY)
+
+	enc, err := GenerateEncryptedSecret(e, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enc) != V1_LEN || enc[0] != FORMAT_V1 {
+		t.Fatal("unexpected format")
+	}
+
+	prevKey, err := e.GetKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, ok := KeyIdOf(enc)
+	if !ok || id != keystore.NewKeyId(prevKey) {
+		t.Fatal("wrong key id in header")
+	}
+
+	dec, err := Decrypt(enc, e, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should decrypt with previous key", func(t *testing.T) {
+		t.Setenv(envKey, testKEY)
+		t.Setenv(prevEnvKey, testPrevKEY)
+
+		rotated, err := Decrypt(enc, e, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dec, rotated) {
+			t.Fatal("wrong decrypted")
+		}
+
+		reenc, err := Encrypt(rotated, e, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, ok := KeyIdOf(reenc)
+		if !ok || id != keystore.NewKeyId(testBytes) {
+			t.Fatal("should be encrypted with the primary key")
+		}
+	})
+
+	t.Run("should fail without previous key", func(t *testing.T) {
+		t.Setenv(envKey, testKEY)
+
+		dec, err := Decrypt(enc, e, nil)
+		if err == nil {
+			t.Fatalf("should fail, but returns %v\n", dec)
+		}
+	})
+
+	t.Run("should fail with tampered header", func(t *testing.T) {
+		tampered := bytes.Clone(enc)
+		tampered[0] = 0x02
+
+		dec, err := Decrypt(tampered, e, nil)
+		if err == nil {
+			t.Fatalf("should fail, but returns %v\n", dec)
+		}
+	})
+}
+
+func Test_DecryptLegacy(t *testing.T) {
+	e := envkey.EnvKey{}
+	t.Setenv(envKey, testKEY)
+	t.Setenv(prevEnvKey, testPrevKEY)
+
+	// nonce || ciphertext, as encrypted before versioning
+	chacha, err := chacha20poly1305.NewX(testBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sec := bytes.Repeat([]byte{7}, SECRET_LEN)
+	nonce := make([]byte, NONCE_LEN)
+	legacy := chacha.Seal(nonce, nonce, sec, nil)
+	if len(legacy) != LEGACY_LEN {
+		t.Fatal("unexpected legacy size")
+	}
+	if _, ok := KeyIdOf(legacy); ok {
+		t.Fatal("legacy should not have key id")
+	}
+
+	dec, err := Decrypt(legacy, e, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec, sec) {
+		t.Fatal("wrong decrypted")
+	}
+
+	// rotated, legacy secret is under the previous key now
+	t.Setenv(envKey, testPrevKEY)
+	t.Setenv(prevEnvKey, testKEY)
+
+	dec, err = Decrypt(legacy, e, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec, sec) {
+		t.Fatal("wrong decrypted")
+	}
+}
@@ -0,0 +1,437 @@
+package vaulttransit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"nidan-kai/secret"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DEFAULT_MOUNT = "transit"
+const CIPHERTEXT_PREFIX = "vault:v"
+
+const REQUEST_TIMEOUT = 10 * time.Second
+const MAX_RETRIES = 3
+const RETRY_BASE_DELAY = 100 * time.Millisecond
+
+// latest key version is cached for this long
+const KEY_INFO_TTL = time.Minute
+
+// delegates encryption of secrets to transit secrets engine of vault,
+// key never leaves vault, reads
+// "VAULT_ADDR", "VAULT_TRANSIT_KEY", optional "VAULT_TRANSIT_MOUNT",
+// "VAULT_NAMESPACE", and "VAULT_TOKEN" or "VAULT_ROLE_ID" with
+// "VAULT_SECRET_ID" for approle
+type Transit struct {
+	addr      *url.URL
+	mount     string
+	keyName   string
+	namespace string
+	roleId    string
+	secretId  string
+
+	client *http.Client
+	// for tests, no sleep between retries
+	retryDelay time.Duration
+
+	mu            sync.Mutex
+	token         string
+	latestVersion int
+	checkedAt     time.Time
+}
+
+type config struct {
+	addr      string
+	mount     string
+	keyName   string
+	namespace string
+	token     string
+	roleId    string
+	secretId  string
+}
+
+func getEnv() (config, error) {
+	// don't inject other than env
+	// to prevent exposing sensitive info
+	// just write within module for testing
+
+	cfg := config{
+		addr:      os.Getenv("VAULT_ADDR"),
+		mount:     os.Getenv("VAULT_TRANSIT_MOUNT"),
+		keyName:   os.Getenv("VAULT_TRANSIT_KEY"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		roleId:    os.Getenv("VAULT_ROLE_ID"),
+		secretId:  os.Getenv("VAULT_SECRET_ID"),
+	}
+
+	if len(cfg.addr) == 0 {
+		return config{}, errors.New("env for vault address is not set")
+	}
+	if len(cfg.keyName) == 0 {
+		return config{}, errors.New("env for vault transit key is not set")
+	}
+	if len(cfg.mount) == 0 {
+		cfg.mount = DEFAULT_MOUNT
+	}
+	if len(cfg.token) == 0 && (len(cfg.roleId) == 0 || len(cfg.secretId) == 0) {
+		return config{}, errors.New("env for vault token or approle is not set")
+	}
+
+	return cfg, nil
+}
+
+func New() (*Transit, error) {
+	cfg, err := getEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := url.Parse(cfg.addr)
+	if err != nil {
+		return nil, err
+	}
+	if addr.Scheme != "https" && addr.Scheme != "http" {
+		return nil, errors.New("unexpected vault address")
+	}
+
+	return &Transit{
+		addr:       addr,
+		mount:      strings.Trim(cfg.mount, "/"),
+		keyName:    cfg.keyName,
+		namespace:  cfg.namespace,
+		roleId:     cfg.roleId,
+		secretId:   cfg.secretId,
+		client:     &http.Client{Timeout: REQUEST_TIMEOUT},
+		retryDelay: RETRY_BASE_DELAY,
+		token:      cfg.token,
+	}, nil
+}
+
+// authenticates and checks the key exists
+func (t *Transit) Init() error {
+	if t.usesAppRole() {
+		if err := t.login(); err != nil {
+			return err
+		}
+	}
+
+	_, err := t.refreshLatestVersion()
+	return err
+}
+
+type httpError struct {
+	status int
+	errors []string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("vault responded %d: %s", e.status, strings.Join(e.errors, ", "))
+}
+
+func retryable(err error) bool {
+	he := &httpError{}
+	if errors.As(err, &he) {
+		return he.status == http.StatusTooManyRequests || he.status >= 500
+	}
+
+	// network errors
+	return true
+}
+
+func (t *Transit) endpoint(path string) string {
+	return t.addr.JoinPath("v1", path).String()
+}
+
+func (t *Transit) do(method, path, token string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = b
+	}
+
+	req, err := http.NewRequest(method, t.endpoint(path), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(token) > 0 {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if len(t.namespace) > 0 {
+		req.Header.Set("X-Vault-Namespace", t.namespace)
+	}
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		e := struct {
+			Errors []string `json:"errors"`
+		}{}
+		json.Unmarshal(b, &e)
+		return &httpError{status: res.StatusCode, errors: e.Errors}
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
+
+func (t *Transit) usesAppRole() bool {
+	return len(t.roleId) > 0 && len(t.secretId) > 0
+}
+
+func (t *Transit) login() error {
+	body := map[string]string{
+		"role_id":   t.roleId,
+		"secret_id": t.secretId,
+	}
+	res := struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}{}
+
+	err := t.withRetry(func() error {
+		return t.do(http.MethodPost, "auth/approle/login", "", body, &res)
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Auth.ClientToken) == 0 {
+		return errors.New("vault returned empty token")
+	}
+
+	t.mu.Lock()
+	t.token = res.Auth.ClientToken
+	t.mu.Unlock()
+
+	return nil
+}
+
+func (t *Transit) withRetry(fn func() error) error {
+	var err error
+	delay := t.retryDelay
+	for i := 0; i <= MAX_RETRIES; i++ {
+		err = fn()
+		if err == nil || !retryable(err) {
+			return err
+		}
+
+		if i < MAX_RETRIES {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	return err
+}
+
+// retries on transient errors, logs in again once when token is expired
+func (t *Transit) call(method, path string, body, out any) error {
+	relogged := false
+	for {
+		t.mu.Lock()
+		token := t.token
+		t.mu.Unlock()
+
+		err := t.withRetry(func() error {
+			return t.do(method, path, token, body, out)
+		})
+
+		he := &httpError{}
+		if errors.As(err, &he) && he.status == http.StatusForbidden &&
+			t.usesAppRole() && !relogged {
+			if err := t.login(); err != nil {
+				return err
+			}
+			relogged = true
+			continue
+		}
+
+		return err
+	}
+}
+
+func encodeAd(ad []byte) string {
+	if len(ad) == 0 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(ad)
+}
+
+func (t *Transit) Encrypt(sec, ad []byte) ([]byte, error) {
+	if len(sec) != secret.SECRET_LEN {
+		return nil, errors.New("unexpected secret size")
+	}
+
+	return t.encrypt(sec, ad)
+}
+
+func (t *Transit) Decrypt(enc, ad []byte) ([]byte, error) {
+	dec, err := t.decrypt(enc, ad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dec) != secret.SECRET_LEN {
+		return nil, errors.New("unexpected decrypted secret size")
+	}
+
+	return dec, nil
+}
+
+func (t *Transit) WrapKey(dek, ad []byte) ([]byte, error) {
+	if len(dek) != secret.DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return t.encrypt(dek, ad)
+}
+
+func (t *Transit) UnwrapKey(wrapped, ad []byte) ([]byte, error) {
+	dek, err := t.decrypt(wrapped, ad)
+	if err != nil {
+		return nil, err
+	}
+	if len(dek) != secret.DATA_KEY_LEN {
+		return nil, errors.New("unexpected data key size")
+	}
+
+	return dek, nil
+}
+
+func (t *Transit) encrypt(value, ad []byte) ([]byte, error) {
+	body := map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(value),
+	}
+	if len(ad) > 0 {
+		body["associated_data"] = encodeAd(ad)
+	}
+	res := struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}{}
+
+	err := t.call(http.MethodPost, fmt.Sprintf("%s/encrypt/%s", t.mount, t.keyName), body, &res)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := versionOf([]byte(res.Data.Ciphertext)); err != nil {
+		return nil, err
+	}
+
+	return []byte(res.Data.Ciphertext), nil
+}
+
+func (t *Transit) decrypt(enc, ad []byte) ([]byte, error) {
+	if _, err := versionOf(enc); err != nil {
+		return nil, err
+	}
+
+	body := map[string]string{
+		"ciphertext": string(enc),
+	}
+	if len(ad) > 0 {
+		body["associated_data"] = encodeAd(ad)
+	}
+	res := struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}{}
+
+	err := t.call(http.MethodPost, fmt.Sprintf("%s/decrypt/%s", t.mount, t.keyName), body, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(res.Data.Plaintext)
+}
+
+// "vault:v<version>:<base64>"
+func versionOf(enc []byte) (int, error) {
+	s := string(enc)
+	if !strings.HasPrefix(s, CIPHERTEXT_PREFIX) {
+		return 0, errors.New("not a vault ciphertext")
+	}
+
+	rawVersion, _, found := strings.Cut(strings.TrimPrefix(s, CIPHERTEXT_PREFIX), ":")
+	if !found {
+		return 0, errors.New("not a vault ciphertext")
+	}
+
+	version, err := strconv.Atoi(rawVersion)
+	if err != nil || version <= 0 {
+		return 0, errors.New("invalid vault key version")
+	}
+
+	return version, nil
+}
+
+func (t *Transit) refreshLatestVersion() (int, error) {
+	res := struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+		} `json:"data"`
+	}{}
+
+	err := t.call(http.MethodGet, fmt.Sprintf("%s/keys/%s", t.mount, t.keyName), nil, &res)
+	if err != nil {
+		return 0, err
+	}
+	if res.Data.LatestVersion <= 0 {
+		return 0, errors.New("vault returned invalid key version")
+	}
+
+	t.mu.Lock()
+	t.latestVersion = res.Data.LatestVersion
+	t.checkedAt = time.Now()
+	t.mu.Unlock()
+
+	return res.Data.LatestVersion, nil
+}
+
+func (t *Transit) IsCurrent(enc []byte) (bool, error) {
+	version, err := versionOf(enc)
+	if err != nil {
+		return false, err
+	}
+
+	t.mu.Lock()
+	latest := t.latestVersion
+	fresh := time.Since(t.checkedAt) < KEY_INFO_TTL
+	t.mu.Unlock()
+
+	if latest == 0 || !fresh {
+		latest, err = t.refreshLatestVersion()
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return version == latest, nil
+}
@@ -0,0 +1,371 @@
+package vaulttransit
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"nidan-kai/secret"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var _ secret.Encryptor = &Transit{}
+
+var testRoleId = "test-role"
+var testSecretId = "test-secret"
+var testKeyName = "nidan-kai"
+
+// stand-in for transit endpoints of vault
+type fakeVault struct {
+	mu     sync.Mutex
+	keys   [][]byte
+	tokens map[string]bool
+	// requests answered with 503 before serving
+	failures int
+	logins   int
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	v := &fakeVault{tokens: map[string]bool{"root": true}}
+	v.rotate(t)
+	return v
+}
+
+func (v *fakeVault) rotate(t *testing.T) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	v.mu.Lock()
+	v.keys = append(v.keys, key)
+	v.mu.Unlock()
+}
+
+func (v *fakeVault) revoke() {
+	v.mu.Lock()
+	v.tokens = map[string]bool{}
+	v.mu.Unlock()
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeErr(w http.ResponseWriter, status int, msg string) {
+	writeJson(w, status, map[string][]string{"errors": {msg}})
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.failures > 0 {
+		v.failures--
+		writeErr(w, http.StatusServiceUnavailable, "sealed")
+		return
+	}
+
+	body := map[string]string{}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if r.URL.Path == "/v1/auth/approle/login" {
+		if body["role_id"] != testRoleId || body["secret_id"] != testSecretId {
+			writeErr(w, http.StatusBadRequest, "invalid role or secret id")
+			return
+		}
+		v.logins++
+		token := fmt.Sprintf("approle-%d", v.logins)
+		v.tokens[token] = true
+		writeJson(w, http.StatusOK, map[string]any{
+			"auth": map[string]any{"client_token": token},
+		})
+		return
+	}
+
+	if !v.tokens[r.Header.Get("X-Vault-Token")] {
+		writeErr(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	ad, err := base64.StdEncoding.DecodeString(body["associated_data"])
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/transit/keys/" + testKeyName:
+		writeJson(w, http.StatusOK, map[string]any{
+			"data": map[string]any{"latest_version": len(v.keys)},
+		})
+	case "/v1/transit/encrypt/" + testKeyName:
+		plain, err := base64.StdEncoding.DecodeString(body["plaintext"])
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		version := len(v.keys)
+		aead, _ := chacha20poly1305.NewX(v.keys[version-1])
+		nonce := make([]byte, aead.NonceSize())
+		rand.Read(nonce)
+		ct := aead.Seal(nonce, nonce, plain, ad)
+		writeJson(w, http.StatusOK, map[string]any{
+			"data": map[string]any{
+				"ciphertext": fmt.Sprintf("vault:v%d:%s", version, base64.StdEncoding.EncodeToString(ct)),
+			},
+		})
+	case "/v1/transit/decrypt/" + testKeyName:
+		parts := strings.SplitN(body["ciphertext"], ":", 3)
+		if len(parts) != 3 {
+			writeErr(w, http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+		if err != nil || version <= 0 || version > len(v.keys) {
+			writeErr(w, http.StatusBadRequest, "invalid key version")
+			return
+		}
+		ct, err := base64.StdEncoding.DecodeString(parts[2])
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		aead, _ := chacha20poly1305.NewX(v.keys[version-1])
+		if len(ct) < aead.NonceSize() {
+			writeErr(w, http.StatusBadRequest, "invalid ciphertext")
+			return
+		}
+		plain, err := aead.Open(nil, ct[:aead.NonceSize()], ct[aead.NonceSize():], ad)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, "cipher: message authentication failed")
+			return
+		}
+		writeJson(w, http.StatusOK, map[string]any{
+			"data": map[string]any{"plaintext": base64.StdEncoding.EncodeToString(plain)},
+		})
+	default:
+		writeErr(w, http.StatusNotFound, "not found")
+	}
+}
+
+func setUpTransit(t *testing.T, vault *fakeVault, approle bool) *Transit {
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TRANSIT_KEY", testKeyName)
+	t.Setenv("VAULT_TRANSIT_MOUNT", "")
+	t.Setenv("VAULT_NAMESPACE", "")
+	if approle {
+		t.Setenv("VAULT_TOKEN", "")
+		t.Setenv("VAULT_ROLE_ID", testRoleId)
+		t.Setenv("VAULT_SECRET_ID", testSecretId)
+	} else {
+		t.Setenv("VAULT_TOKEN", "root")
+		t.Setenv("VAULT_ROLE_ID", "")
+		t.Setenv("VAULT_SECRET_ID", "")
+	}
+
+	transit, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transit.retryDelay = 0
+	if err := transit.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	return transit
+}
+
+func TestTransit_EncryptDecrypt(t *testing.T) {
+	vault := newFakeVault(t)
+	transit := setUpTransit(t, vault, false)
+
+	sec := []byte("12345678901234567890")
+	ad := []byte("mfa_qrs/1")
+	enc, err := transit.Encrypt(sec, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(enc), "vault:v1:") {
+		t.Fatal("should be encrypted with the first version")
+	}
+
+	dec, err := transit.Decrypt(enc, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sec, dec) {
+		t.Fatal("decrypted secret differs")
+	}
+
+	t.Run("should fail with other associated data", func(t *testing.T) {
+		if _, err := transit.Decrypt(enc, []byte("mfa_qrs/2")); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+
+	t.Run("should wrap data keys", func(t *testing.T) {
+		dek := make([]byte, secret.DATA_KEY_LEN)
+		rand.Read(dek)
+		wrapped, err := transit.WrapKey(dek, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unwrapped, err := transit.UnwrapKey(wrapped, ad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(dek, unwrapped) {
+			t.Fatal("unwrapped data key differs")
+		}
+		if _, err := transit.Decrypt(wrapped, ad); err == nil {
+			t.Fatal("should not decrypt data keys as secrets")
+		}
+	})
+
+	t.Run("should refuse unexpected secret size", func(t *testing.T) {
+		if _, err := transit.Encrypt([]byte("short"), ad); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+
+	t.Run("should refuse non vault ciphertext", func(t *testing.T) {
+		if _, err := transit.Decrypt(make([]byte, 65), ad); err == nil {
+			t.Fatal("should fail but returned nil")
+		}
+	})
+}
+
+func TestTransit_Rotation(t *testing.T) {
+	vault := newFakeVault(t)
+	transit := setUpTransit(t, vault, false)
+
+	sec := []byte("12345678901234567890")
+	old, err := transit.Encrypt(sec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := transit.IsCurrent(old)
+	if err != nil || !current {
+		t.Fatal("should be current before rotation")
+	}
+
+	vault.rotate(t)
+	// drop cached version
+	transit.checkedAt = transit.checkedAt.Add(-KEY_INFO_TTL)
+
+	current, err = transit.IsCurrent(old)
+	if err != nil || current {
+		t.Fatal("should not be current after rotation")
+	}
+
+	enc, err := transit.Encrypt(sec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(enc), "vault:v2:") {
+		t.Fatal("should be encrypted with the latest version")
+	}
+	current, err = transit.IsCurrent(enc)
+	if err != nil || !current {
+		t.Fatal("should be current")
+	}
+
+	// old versions are still decryptable
+	dec, err := transit.Decrypt(old, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sec, dec) {
+		t.Fatal("decrypted secret differs")
+	}
+}
+
+func TestTransit_AppRole(t *testing.T) {
+	vault := newFakeVault(t)
+	transit := setUpTransit(t, vault, true)
+	if vault.logins != 1 {
+		t.Fatal("should log in on init")
+	}
+
+	sec := []byte("12345678901234567890")
+	enc, err := transit.Encrypt(sec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should log in again when token is expired", func(t *testing.T) {
+		vault.revoke()
+		dec, err := transit.Decrypt(enc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sec, dec) {
+			t.Fatal("decrypted secret differs")
+		}
+		if vault.logins != 2 {
+			t.Fatal("should log in again")
+		}
+	})
+}
+
+func TestTransit_Token(t *testing.T) {
+	vault := newFakeVault(t)
+	transit := setUpTransit(t, vault, false)
+
+	vault.revoke()
+	if _, err := transit.Encrypt([]byte("12345678901234567890"), nil); err == nil {
+		t.Fatal("should fail with revoked token")
+	}
+}
+
+func TestTransit_Retry(t *testing.T) {
+	vault := newFakeVault(t)
+	transit := setUpTransit(t, vault, false)
+
+	sec := []byte("12345678901234567890")
+	vault.failures = MAX_RETRIES
+	if _, err := transit.Encrypt(sec, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	vault.failures = MAX_RETRIES + 1
+	if _, err := transit.Encrypt(sec, nil); err == nil {
+		t.Fatal("should give up after retries")
+	}
+}
+
+func TestTransit_Env(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TRANSIT_KEY", testKeyName)
+	t.Setenv("VAULT_TOKEN", "root")
+	if _, err := New(); err == nil {
+		t.Fatal("should fail without address")
+	}
+
+	t.Setenv("VAULT_ADDR", "http://127.0.0.1:8200")
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_ROLE_ID", testRoleId)
+	t.Setenv("VAULT_SECRET_ID", "")
+	if _, err := New(); err == nil {
+		t.Fatal("should fail without credentials")
+	}
+}
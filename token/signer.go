@@ -0,0 +1,138 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"errors"
+	"nidan-kai/keystore"
+	"time"
+)
+
+// keys are fetched from the keystore again after this,
+// to pick up rotation done without restarting
+const DEFAULT_SIGNER_TTL = keystore.DEFAULT_CACHE_TTL
+
+// keeps signing keys apart from keys derived for other purposes
+const SIGNING_KEY_LABEL = "nidan-kai/token-signing-key"
+
+// keeps tokens with random key ids from hitting the keystore on every request
+const MIN_REFRESH_INTERVAL = 10 * time.Second
+
+var errUnknownKey = errors.New("token is signed with unknown key")
+
+type signingKey struct {
+	jwk  Jwk
+	priv ed25519.PrivateKey
+}
+
+// derives an ed25519 key from a keystore key,
+// so that no other key has to be managed for tokens
+func newSigningKey(key []byte) (signingKey, error) {
+	if len(key) != keystore.KEY_SIZE {
+		return signingKey{}, errors.New("unexpected key size")
+	}
+
+	seed, err := hkdf.Key(sha256.New, key, nil, SIGNING_KEY_LABEL, ed25519.SeedSize)
+	if err != nil {
+		return signingKey{}, err
+	}
+	defer clear(seed)
+
+	priv := ed25519.NewKeyFromSeed(seed)
+	return signingKey{
+		jwk:  newJwk(priv.Public().(ed25519.PublicKey)),
+		priv: priv,
+	}, nil
+}
+
+// signs tokens with the key derived from the primary keystore key,
+// and verifies ones signed with the previous keys as well
+type Signer struct {
+	keys *keystore.Cache[signingKey]
+}
+
+func NewSigner(store keystore.Keystore, ttl time.Duration) *Signer {
+	return &Signer{
+		keys: keystore.NewCache(store, ttl, newSigningKey),
+	}
+}
+
+// fetches keys from the keystore and replaces derived keys
+func (s *Signer) Refresh() error {
+	return s.keys.Refresh()
+}
+
+func find(keys []signingKey, kid string) (signingKey, bool) {
+	for _, key := range keys {
+		if key.jwk.KeyId == kid {
+			return key, true
+		}
+	}
+	return signingKey{}, false
+}
+
+func (s *Signer) Sign(claims Claims) (string, error) {
+	keys, err := s.keys.Get()
+	if err != nil {
+		return "", err
+	}
+
+	return sign(keys[0].jwk.KeyId, keys[0].priv, claims)
+}
+
+// checks the signature and expiry, the caller checks the other claims
+func (s *Signer) Verify(raw string, now time.Time) (Claims, error) {
+	kid, err := parseHeader(raw)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	keys, err := s.keys.Get()
+	if err != nil {
+		return Claims{}, err
+	}
+
+	key, ok := find(keys, kid)
+	if !ok {
+		key, ok, err = s.findNewer(kid)
+		if err != nil {
+			return Claims{}, err
+		}
+	}
+	if !ok {
+		return Claims{}, errUnknownKey
+	}
+
+	return verify(raw, key.priv.Public().(ed25519.PublicKey), now)
+}
+
+// looks for a key signed by others with a key newer than the cached ones
+func (s *Signer) findNewer(kid string) (signingKey, bool, error) {
+	keys, err := s.keys.Reload(func(keys []signingKey) bool {
+		_, ok := find(keys, kid)
+		return !ok
+	}, MIN_REFRESH_INTERVAL)
+	if err != nil {
+		return signingKey{}, false, err
+	}
+
+	key, ok := find(keys, kid)
+	return key, ok, nil
+}
+
+// public keys to be published, previous ones are kept
+// so that tokens signed before rotation can be verified
+func (s *Signer) Jwks() (JwkSet, error) {
+	keys, err := s.keys.Get()
+	if err != nil {
+		return JwkSet{}, err
+	}
+
+	set := JwkSet{Keys: make([]Jwk, len(keys))}
+	for i, key := range keys {
+		set.Keys[i] = key.jwk
+	}
+
+	return set, nil
+}
@@ -0,0 +1,150 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const ALGORITHM = "EdDSA"
+const TYPE = "JWT"
+
+// authentication method reference for otp (RFC 8176)
+const AMR_OTP = "otp"
+
+// tolerated clock drift between the issuer and verifiers
+const LEEWAY = 30 * time.Second
+
+var errInvalidToken = errors.New("invalid token")
+var errExpired = errors.New("token is expired")
+
+var encoding = base64.RawURLEncoding
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyId     string `json:"kid"`
+}
+
+// asserts that the subject passed mfa at AuthTime
+type Claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  string   `json:"aud,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+	AuthTime  int64    `json:"auth_time"`
+	Amr       []string `json:"amr"`
+	Id        string   `json:"jti"`
+}
+
+// public key in the form of jwk (RFC 8037)
+type Jwk struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyId     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+type JwkSet struct {
+	Keys []Jwk `json:"keys"`
+}
+
+func newJwk(pub ed25519.PublicKey) Jwk {
+	x := encoding.EncodeToString(pub)
+
+	// thumbprint of the required members in lexicographic order (RFC 7638)
+	thumbprint := sha256.Sum256([]byte(`{"crv":"Ed25519","kty":"OKP","x":"` + x + `"}`))
+
+	return Jwk{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		X:         x,
+		KeyId:     encoding.EncodeToString(thumbprint[:]),
+		Use:       "sig",
+		Algorithm: ALGORITHM,
+	}
+}
+
+func sign(kid string, priv ed25519.PrivateKey, claims Claims) (string, error) {
+	h, err := json.Marshal(header{
+		Algorithm: ALGORITHM,
+		Type:      TYPE,
+		KeyId:     kid,
+	})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	input := encoding.EncodeToString(h) + "." + encoding.EncodeToString(c)
+	sig := ed25519.Sign(priv, []byte(input))
+
+	return input + "." + encoding.EncodeToString(sig), nil
+}
+
+// returns the key id to look up the public key with
+func parseHeader(raw string) (string, error) {
+	rawHeader, _, ok := strings.Cut(raw, ".")
+	if !ok {
+		return "", errInvalidToken
+	}
+
+	b, err := encoding.DecodeString(rawHeader)
+	if err != nil {
+		return "", errInvalidToken
+	}
+	h := header{}
+	if err := json.Unmarshal(b, &h); err != nil {
+		return "", errInvalidToken
+	}
+
+	// never trust the algorithm given by the token
+	if h.Algorithm != ALGORITHM || len(h.KeyId) == 0 {
+		return "", errInvalidToken
+	}
+
+	return h.KeyId, nil
+}
+
+func verify(raw string, pub ed25519.PublicKey, now time.Time) (Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return Claims{}, errInvalidToken
+	}
+
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, errInvalidToken
+	}
+	if !ed25519.Verify(pub, []byte(parts[0]+"."+parts[1]), sig) {
+		return Claims{}, errInvalidToken
+	}
+
+	b, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, errInvalidToken
+	}
+	claims := Claims{}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return Claims{}, errInvalidToken
+	}
+
+	if !now.Before(time.Unix(claims.ExpiresAt, 0).Add(LEEWAY)) {
+		return Claims{}, errExpired
+	}
+	if now.Add(LEEWAY).Before(time.Unix(claims.IssuedAt, 0)) {
+		return Claims{}, errors.New("token is issued in the future")
+	}
+
+	return claims, nil
+}
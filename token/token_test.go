@@ -0,0 +1,216 @@
+package token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"nidan-kai/keystore"
+	"nidan-kai/keystore/envkey"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testKEY = "TTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTTT="
+var testPrevKEY = "PPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPPP="
+var envKey = "ENV_SECRET_KEY"
+var prevEnvKey = "ENV_SECRET_KEY_PREVIOUS"
+
+var testNow = time.Unix(1111111109, 0)
+
+func testClaims() Claims {
+	return Claims{
+		Issuer:    "NidanKai",
+		Subject:   "subject",
+		IssuedAt:  testNow.Unix(),
+		ExpiresAt: testNow.Add(time.Minute).Unix(),
+		AuthTime:  testNow.Unix(),
+		Amr:       []string{AMR_OTP},
+		Id:        "id",
+	}
+}
+
+func TestSigner(t *testing.T) {
+	t.Setenv(envKey, testKEY)
+	s := NewSigner(envkey.EnvKey{}, DEFAULT_SIGNER_TTL)
+
+	raw, err := s.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := s.Verify(raw, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "subject" || claims.Amr[0] != AMR_OTP {
+		t.Fatal("wrong claims")
+	}
+
+	t.Run("should reject expired", func(t *testing.T) {
+		if _, err := s.Verify(raw, testNow.Add(time.Minute+LEEWAY)); err == nil {
+			t.Fatal("expired token is accepted")
+		}
+	})
+
+	t.Run("should reject tampered claims", func(t *testing.T) {
+		parts := strings.Split(raw, ".")
+		tampered := testClaims()
+		tampered.Subject = "other"
+		b, _ := json.Marshal(tampered)
+		parts[1] = base64.RawURLEncoding.EncodeToString(b)
+		if _, err := s.Verify(strings.Join(parts, "."), testNow); err == nil {
+			t.Fatal("tampered token is accepted")
+		}
+	})
+
+	t.Run("should reject other algorithms", func(t *testing.T) {
+		parts := strings.Split(raw, ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT","kid":"x"}`))
+		if _, err := s.Verify(parts[0]+"."+parts[1]+".", testNow); err == nil {
+			t.Fatal("unsigned token is accepted")
+		}
+	})
+
+	t.Run("should be verified by other instances", func(t *testing.T) {
+		other := NewSigner(envkey.EnvKey{}, DEFAULT_SIGNER_TTL)
+		if _, err := other.Verify(raw, testNow); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestSigner_Rotation(t *testing.T) {
+	t.Setenv(envKey, testPrevKEY)
+	s := NewSigner(envkey.EnvKey{}, DEFAULT_SIGNER_TTL)
+
+	prev, err := s.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(envKey, testKEY)
+	t.Setenv(prevEnvKey, testPrevKEY)
+	if err := s.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := s.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw == prev {
+		t.Fatal("should sign with the new key")
+	}
+
+	if _, err := s.Verify(prev, testNow); err != nil {
+		t.Fatal("token signed before rotation should be verified")
+	}
+
+	set, err := s.Jwks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatal("should publish previous keys")
+	}
+	for _, jwk := range set.Keys {
+		if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" || jwk.Algorithm != ALGORITHM {
+			t.Fatal("unexpected jwk")
+		}
+	}
+
+	t.Run("should pick up keys newer than the cached ones", func(t *testing.T) {
+		t.Setenv(envKey, testPrevKEY)
+		t.Setenv(prevEnvKey, "")
+		cached := NewSigner(envkey.EnvKey{}, DEFAULT_SIGNER_TTL)
+		if _, err := cached.Verify(prev, testNow); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Setenv(envKey, testKEY)
+		if _, err := cached.Verify(raw, testNow); err == nil {
+			t.Fatal("should not refresh so often")
+		}
+
+		loadedAt := time.Now()
+		cached.keys.Clock = func() time.Time {
+			return loadedAt.Add(MIN_REFRESH_INTERVAL)
+		}
+		if _, err := cached.Verify(raw, testNow); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("should reject unknown keys", func(t *testing.T) {
+		t.Setenv(envKey, testKEY)
+		t.Setenv(prevEnvKey, "")
+		if err := s.Refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Verify(prev, testNow); err == nil {
+			t.Fatal("removed key is accepted")
+		}
+	})
+}
+
+func TestNewJwk(t *testing.T) {
+	// RFC 8037 A.3
+	x := "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+	pub, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk := newJwk(pub)
+	if jwk.X != x || jwk.KeyId != "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k" {
+		t.Fatal("wrong thumbprint")
+	}
+}
+
+// fails while down
+type unreliableKeystore struct {
+	keystore.MultiKeystore
+	count int
+	down  bool
+}
+
+func (u *unreliableKeystore) GetKeys() ([][]byte, error) {
+	u.count++
+	if u.down {
+		return nil, errors.New("keystore is down")
+	}
+	return u.MultiKeystore.GetKeys()
+}
+
+func TestSigner_StaleKeys(t *testing.T) {
+	t.Setenv(envKey, testKEY)
+	store := &unreliableKeystore{MultiKeystore: envkey.EnvKey{}}
+	now := time.Unix(1111111109, 0)
+	s := NewSigner(store, time.Minute)
+	s.keys.Clock = func() time.Time { return now }
+
+	raw, err := s.Sign(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Minute)
+	store.count = 0
+	store.down = true
+	for range 10 {
+		if _, err := s.Verify(raw, testNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.count != 1 {
+		t.Fatalf("failed refresh should not be retried at once but %d times\n", store.count)
+	}
+
+	t.Run("should fail without cached keys", func(t *testing.T) {
+		other := NewSigner(store, time.Minute)
+		if _, err := other.Sign(testClaims()); err == nil {
+			t.Fatal("should fail while keystore is down")
+		}
+	})
+}